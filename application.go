@@ -1,5 +1,12 @@
 package tuikit
 
+import (
+	"sync"
+
+	"github.com/flowexec/tuikit/i18n"
+	"github.com/flowexec/tuikit/themes"
+)
+
 type Application struct {
 	Name    string
 	Version string
@@ -7,8 +14,20 @@ type Application struct {
 	stateKey   string
 	stateVal   string
 	loadingMsg string
+
+	locale       string
+	translations map[string]map[i18n.MessageKey]string
+
+	segments       []themes.HeaderSegment
+	headerRenderer HeaderRenderer
+	headerMu       sync.RWMutex
 }
 
+// HeaderRenderer renders the application header line. It receives the ordered header
+// segments, including the legacy state segment and any segments contributed by the
+// current view. The returned string should be a single line followed by a newline.
+type HeaderRenderer func(theme themes.Theme, app *Application, segments []themes.HeaderSegment, width int) string
+
 type ApplicationOption func(*Application)
 
 func NewApplication(name string, opts ...ApplicationOption) *Application {
@@ -19,12 +38,26 @@ func NewApplication(name string, opts ...ApplicationOption) *Application {
 	for _, opt := range opts {
 		opt(a)
 	}
+	for locale, msgs := range a.translations {
+		i18n.Register(locale, msgs)
+	}
+	if a.locale != "" {
+		i18n.SetLocale(a.locale)
+	}
 	if a.loadingMsg == "" {
-		a.loadingMsg = "loading..."
+		a.loadingMsg = i18n.T(i18n.MsgLoading)
 	}
 	return a
 }
 
+// Locale returns the locale selected with WithLocale, or the active catalog locale when none was set.
+func (a *Application) Locale() string {
+	if a.locale == "" {
+		return i18n.Locale()
+	}
+	return a.locale
+}
+
 func WithStateKey(key string) ApplicationOption {
 	return func(a *Application) {
 		a.stateKey = key
@@ -55,3 +88,91 @@ func WithLoadingMsg(msg string) ApplicationOption {
 		a.loadingMsg = msg
 	}
 }
+
+// WithLocale selects the locale used for built-in UI strings and date formats.
+//
+// The locale is process-global: it is applied to the shared i18n catalog when the
+// application is created, so it also applies to any other Application in the process,
+// and the last one created wins.
+func WithLocale(locale string) ApplicationOption {
+	return func(a *Application) {
+		a.locale = locale
+	}
+}
+
+// WithTranslations registers messages for a locale in the i18n catalog. Keys that are not
+// provided fall back to the base language and then to English. Like WithLocale, the
+// translations are process-global and shared by every Application in the process.
+func WithTranslations(locale string, messages map[i18n.MessageKey]string) ApplicationOption {
+	return func(a *Application) {
+		if a.translations == nil {
+			a.translations = make(map[string]map[i18n.MessageKey]string)
+		}
+		if a.translations[locale] == nil {
+			a.translations[locale] = make(map[i18n.MessageKey]string, len(messages))
+		}
+		for k, v := range messages {
+			a.translations[locale][k] = v
+		}
+	}
+}
+
+// WithHeaderSegments sets the initial, ordered header segments.
+func WithHeaderSegments(segments ...themes.HeaderSegment) ApplicationOption {
+	return func(a *Application) {
+		a.segments = segments
+	}
+}
+
+// WithHeaderRenderer replaces the theme's header rendering entirely.
+func WithHeaderRenderer(renderer HeaderRenderer) ApplicationOption {
+	return func(a *Application) {
+		a.headerRenderer = renderer
+	}
+}
+
+// SetHeaderSegment adds the segment to the end of the header or replaces the
+// existing segment with the same name.
+func (a *Application) SetHeaderSegment(segment themes.HeaderSegment) {
+	a.headerMu.Lock()
+	defer a.headerMu.Unlock()
+	for i, seg := range a.segments {
+		if segment.Name != "" && seg.Name == segment.Name {
+			a.segments[i] = segment
+			return
+		}
+	}
+	a.segments = append(a.segments, segment)
+}
+
+// RemoveHeaderSegment removes the segment with the given name.
+func (a *Application) RemoveHeaderSegment(name string) {
+	a.headerMu.Lock()
+	defer a.headerMu.Unlock()
+	for i, seg := range a.segments {
+		if seg.Name == name {
+			a.segments = append(a.segments[:i], a.segments[i+1:]...)
+			return
+		}
+	}
+}
+
+// HeaderSegments returns the application's header segments, starting with the state
+// segment when a state key and value are set.
+func (a *Application) HeaderSegments() []themes.HeaderSegment {
+	a.headerMu.RLock()
+	defer a.headerMu.RUnlock()
+	segments := make([]themes.HeaderSegment, 0, len(a.segments)+1)
+	if a.stateKey != "" && a.stateVal != "" {
+		segments = append(segments, themes.HeaderSegment{
+			Name: stateSegmentName, Label: a.stateKey, Value: a.stateVal, Priority: stateSegmentPriority,
+		})
+	}
+	return append(segments, a.segments...)
+}
+
+const (
+	stateSegmentName = "state"
+	// The legacy state segment is kept as long as possible on narrow terminals.
+	stateSegmentPriority = 100
+)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"sync"
 	"time"
 
@@ -33,6 +34,13 @@ type InputCapturer interface {
 	CapturingInput() bool
 }
 
+// HeaderSegmenter is an optional interface views can implement to contribute
+// contextual header segments while they are the current view. The segments are
+// rendered after the application's own segments.
+type HeaderSegmenter interface {
+	HeaderSegments() []themes.HeaderSegment
+}
+
 type Container struct {
 	ctx      context.Context
 	cancel   context.CancelFunc
@@ -41,10 +49,12 @@ type Container struct {
 	render   *types.RenderState
 	sendFunc func(msg tea.Msg) // Temporary hack for testing
 
-	previousView, currentView, nextView View
-	help                                *overlay.HelpPopup
-	toasts                              *overlay.ToastManager
-	finalizing                          *chan struct{}
+	currentView, nextView View
+	// history holds the views to go back to, the most recent last.
+	history    []View
+	help       *overlay.HelpPopup
+	toasts     *overlay.ToastManager
+	finalizing *chan struct{}
 
 	viewMu  sync.RWMutex
 	stateMu sync.RWMutex
@@ -109,6 +119,7 @@ func (c *Container) Start() error {
 		if err != nil && !errors.Is(err, tea.ErrProgramKilled) {
 			c.HandleError(err)
 		}
+		c.closeViews()
 		c.cancel()
 	}()
 
@@ -162,6 +173,7 @@ func (c *Container) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds := make([]tea.Cmd, 0)
 	switch msg := msg.(type) {
 	case tea.QuitMsg:
+		c.closeViews()
 		return c, tea.Quit
 	case tea.WindowSizeMsg:
 		c.stateMu.Lock()
@@ -179,20 +191,36 @@ func (c *Container) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			fwdMsg = c.render
 		}
 	case types.ReplaceViewMsg:
-		var err error
+		var (
+			cmd tea.Cmd
+			err error
+		)
 		switch {
 		case c.NextView() != nil:
-			err = c.SetView(c.NextView())
+			cmd, err = c.setView(c.NextView())
 		case c.PreviousView() != nil:
-			err = c.SetView(c.PreviousView())
+			cmd = c.back()
 		case c.CurrentView().Type() == views.FormViewType:
-			return c, tea.Quit
+			return c, c.quit()
 		default:
-			err = c.SetView(c.loadingView())
+			cmd, err = c.setView(c.loadingView())
+		}
+		if err != nil {
+			c.HandleError(err)
+		}
+		cmds = append(cmds, cmd)
+	case types.PushViewMsg:
+		fwdMsg = nil
+		v, ok := msg.View.(View)
+		if !ok {
+			c.HandleError(fmt.Errorf("%T can't be shown by the container", msg.View))
+			break
 		}
+		cmd, err := c.setView(v)
 		if err != nil {
 			c.HandleError(err)
 		}
+		cmds = append(cmds, cmd)
 	case tea.KeyPressMsg:
 		if c.help.Visible() {
 			fwdMsg = nil
@@ -212,25 +240,18 @@ func (c *Container) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// handle hard exit keys; forward everything else to the view.
 		if ic, ok := c.CurrentView().(InputCapturer); ok && ic.CapturingInput() {
 			if msg.String() == "ctrl+c" {
-				c.CurrentView().Update(tea.Quit())
-				return c, tea.Quit
+				return c, c.quit()
 			}
 			break
 		}
 		switch msg.String() {
 		case "ctrl+c", "q":
-			c.CurrentView().Update(tea.Quit())
-			return c, tea.Quit
+			return c, c.quit()
 		case "esc", "backspace":
-			if c.PreviousView() == nil || c.CurrentView() == c.PreviousView() {
-				c.CurrentView().Update(tea.Quit())
-				return c, tea.Quit
-			} else {
-				if err := c.SetView(c.PreviousView()); err != nil {
-					c.HandleError(err)
-				}
-				return c, nil
+			if c.PreviousView() == nil {
+				return c, c.quit()
 			}
+			return c, c.back()
 		case "h", "?":
 			fwdMsg = nil
 			c.help.SetViewKeys(c.CurrentView().HelpBindings())
@@ -238,6 +259,8 @@ func (c *Container) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case types.ToastDismissMsg:
 		c.toasts.Dismiss(msg.ID)
+	case types.NoticeMsg:
+		cmds = append(cmds, c.toasts.Push(msg.Text, msg.Level))
 	case types.TickMsg:
 		if c.Ready() && c.CurrentView().Type() == views.LoadingViewType && c.nextView != nil {
 			c.currentView = c.nextView
@@ -262,7 +285,7 @@ func (c *Container) View() tea.View {
 		return c.CurrentView().View()
 	}
 
-	header := c.render.Theme.RenderHeader(c.app.Name, c.app.Version, c.app.stateKey, c.app.stateVal, c.render.Width)
+	header := c.renderHeader()
 	base := lipgloss.JoinVertical(lipgloss.Top, header, c.CurrentView().View().Content)
 
 	// Fast path: no overlays active.
@@ -344,40 +367,90 @@ func (c *Container) ContentWidth() int {
 	return c.render.ContentWidth
 }
 
+// SetView shows v, keeping the current view in the history so that esc returns to
+// it. Loading and form views aren't kept. Views that implement io.Closer are closed
+// when they are left by going back and when the program exits.
 func (c *Container) SetView(v View) error {
+	cmd, err := c.setView(v)
+	if cmd != nil {
+		c.Send(cmd, 0)
+	}
+	return err
+}
+
+// setView shows v and returns its Init command. Unlike SetView, it can be called
+// from Update, where sending the command to the program would block.
+func (c *Container) setView(v View) (tea.Cmd, error) {
 	switch {
 	case v == nil:
-		return errors.New("view not provided")
+		return nil, errors.New("view not provided")
 	case c.program.Suspended():
 		if err := c.program.Resume(); err != nil {
-			return fmt.Errorf("unable to resume program - %w", err)
+			return nil, fmt.Errorf("unable to resume program - %w", err)
 		}
 	}
-
-	switching := c.CurrentView() != nil && c.CurrentView().Type() != v.Type() &&
-		c.CurrentView().Type() != views.LoadingViewType && c.CurrentView().Type() != views.FormViewType
-	switch {
-	case !c.Ready():
+	if !c.Ready() {
 		c.SetNextView(v)
-	case switching:
-		c.viewMu.Lock()
-		c.previousView = c.currentView
-		c.viewMu.Unlock()
-		fallthrough
+		return nil, nil
+	}
+
+	c.viewMu.Lock()
+	switch cur := c.currentView; {
+	case cur == nil || cur == v:
+	case cur.Type() == views.LoadingViewType || cur.Type() == views.FormViewType:
+		closeView(cur)
 	default:
-		c.viewMu.Lock()
-		c.currentView = v
-		if c.currentView == c.nextView {
-			c.nextView = nil
-		}
+		c.history = append(c.history, cur)
+	}
+	c.currentView = v
+	if c.currentView == c.nextView {
+		c.nextView = nil
+	}
+	c.viewMu.Unlock()
+	return v.Init(), nil
+}
+
+// back returns to the most recent view in the history and returns its Init command.
+func (c *Container) back() tea.Cmd {
+	c.viewMu.Lock()
+	if len(c.history) == 0 {
 		c.viewMu.Unlock()
-		cmd := c.CurrentView().Init()
-		if cmd != nil {
-			c.Send(cmd, 0)
-		}
+		return nil
 	}
+	v := c.history[len(c.history)-1]
+	c.history = c.history[:len(c.history)-1]
+	if !slices.Contains(c.history, c.currentView) {
+		closeView(c.currentView)
+	}
+	c.currentView = v
+	c.viewMu.Unlock()
+	return v.Init()
+}
 
-	return nil
+// quit closes the views and returns the command quitting the program.
+func (c *Container) quit() tea.Cmd {
+	c.CurrentView().Update(tea.Quit())
+	c.closeViews()
+	return tea.Quit
+}
+
+// closeViews closes the current view, the views in the history and the next view.
+func (c *Container) closeViews() {
+	c.viewMu.RLock()
+	defer c.viewMu.RUnlock()
+	closeView(c.currentView)
+	for _, v := range c.history {
+		closeView(v)
+	}
+	closeView(c.nextView)
+}
+
+// closeView releases what a view holds, such as running processes, when it
+// implements io.Closer. Views may be closed more than once.
+func closeView(v View) {
+	if closer, ok := v.(io.Closer); ok {
+		_ = closer.Close()
+	}
 }
 
 func (c *Container) SetSendFunc(f func(msg tea.Msg)) {
@@ -411,10 +484,15 @@ func (c *Container) CurrentView() View {
 	return c.currentView
 }
 
+// PreviousView returns the view that going back returns to, or nil at the start of
+// the history.
 func (c *Container) PreviousView() View {
 	c.viewMu.RLock()
 	defer c.viewMu.RUnlock()
-	return c.previousView
+	if len(c.history) == 0 {
+		return nil
+	}
+	return c.history[len(c.history)-1]
 }
 
 func (c *Container) NextView() View {
@@ -443,18 +521,64 @@ func (c *Container) SetNotice(notice string, lvl themes.OutputLevel) {
 }
 
 func (c *Container) SetState(key, val string) {
+	c.app.headerMu.Lock()
+	defer c.app.headerMu.Unlock()
 	c.app.stateKey = key
 	c.app.stateVal = val
 }
 
 func (c *Container) SetStateValue(val string) {
+	c.app.headerMu.Lock()
+	defer c.app.headerMu.Unlock()
 	c.app.stateVal = val
 }
 
 func (c *Container) State() (string, string) {
+	c.app.headerMu.RLock()
+	defer c.app.headerMu.RUnlock()
 	return c.app.stateKey, c.app.stateVal
 }
 
+// SetHeaderSegment adds or replaces (by name) a segment in the application header.
+func (c *Container) SetHeaderSegment(segment themes.HeaderSegment) {
+	c.app.SetHeaderSegment(segment)
+}
+
+// RemoveHeaderSegment removes the named segment from the application header.
+func (c *Container) RemoveHeaderSegment(name string) {
+	c.app.RemoveHeaderSegment(name)
+}
+
+// HeaderSegments returns the segments currently rendered in the header, including
+// those contributed by the current view.
+func (c *Container) HeaderSegments() []themes.HeaderSegment {
+	segments := c.app.HeaderSegments()
+	if hs, ok := c.CurrentView().(HeaderSegmenter); ok {
+		segments = append(segments, hs.HeaderSegments()...)
+	}
+	return segments
+}
+
+func (c *Container) renderHeader() string {
+	segments := c.HeaderSegments()
+	if c.app.headerRenderer != nil {
+		return c.app.headerRenderer(c.render.Theme, c.app, segments, c.render.Width)
+	}
+	if r, ok := c.render.Theme.(themes.HeaderSegmentRenderer); ok {
+		return r.RenderHeaderSegments(c.app.Name, c.app.Version, segments, c.render.Width)
+	}
+	// Themes that only render the legacy header keep rendering it until other
+	// segments are added.
+	switch {
+	case len(segments) == 0:
+		return c.render.Theme.RenderHeader(c.app.Name, c.app.Version, "", "", c.render.Width)
+	case len(segments) == 1 && segments[0].Name == stateSegmentName:
+		state := segments[0]
+		return c.render.Theme.RenderHeader(c.app.Name, c.app.Version, state.Label, state.Value, c.render.Width)
+	}
+	return themes.RenderHeaderSegments(c.render.Theme.ColorPalette(), c.app.Name, c.app.Version, segments, c.render.Width)
+}
+
 func (c *Container) doTick() tea.Cmd {
 	return tea.Tick(tickTime, func(t time.Time) tea.Msg {
 		return types.TickMsg(t)
@@ -3,17 +3,24 @@ package tuikit_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/exp/teatest/v2"
 
 	"github.com/flowexec/tuikit"
+	"github.com/flowexec/tuikit/i18n"
+	"github.com/flowexec/tuikit/io"
 	sampleTypes "github.com/flowexec/tuikit/sample/types"
 	"github.com/flowexec/tuikit/themes"
 	"github.com/flowexec/tuikit/types"
@@ -284,7 +291,8 @@ func TestTableFilterChildren(t *testing.T) {
 		table.Update(tea.KeyPressMsg{Text: string(ch)})
 	}
 
-	content := table.View().Content
+	// Matches are highlighted, which splits "MatchChild" with styling.
+	content := ansi.Strip(table.View().Content)
 	if !strings.Contains(content, "Parent") {
 		t.Error("expected Parent to be visible (has matching child)")
 	}
@@ -392,264 +400,1783 @@ func TestTableFilterCustomFunc(t *testing.T) {
 	}
 }
 
-// --- HelpBindings tests ---
+// --- Table filter query tests ---
+
+func TestParseFilterQuery(t *testing.T) {
+	columns := []views.TableColumn{{Title: "Name"}, {Title: "Status"}, {Title: "Last Tag"}}
+	rows := [][]string{
+		{"build-api", "failed", "test"},
+		{"build-web", "passed", "release"},
+		{"deploy-api", "failed", "release"},
+		{"lint", "passed", "test"},
+	}
+	tests := []struct {
+		query string
+		fuzzy bool
+		want  []string
+	}{
+		{query: "", want: []string{"build-api", "build-web", "deploy-api", "lint"}},
+		{query: "API", want: []string{"build-api", "deploy-api"}},
+		{query: "status:failed", want: []string{"build-api", "deploy-api"}},
+		{query: "status:failed -lasttag:test", want: []string{"deploy-api"}},
+		{query: "/^build/ AND passed", want: []string{"build-web"}},
+		{query: "lint OR deploy", want: []string{"deploy-api", "lint"}},
+		{query: "-(status:passed OR api)", want: nil},
+		{query: `"build-w"`, want: []string{"build-web"}},
+		{query: `name:/^(lint|deploy)/ release`, want: []string{"deploy-api"}},
+		{query: "bldw", fuzzy: true, want: []string{"build-web"}},
+		{query: `"bldw"`, fuzzy: true, want: nil},
+	}
+	for _, tt := range tests {
+		q, err := views.ParseFilterQuery(tt.query, columns, tt.fuzzy)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.query, err)
+		}
+		var got []string
+		for _, row := range rows {
+			if q.Match(row) {
+				got = append(got, row[0])
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%q: expected %v, got %v", tt.query, tt.want, got)
+		}
+	}
 
-func TestHelpBindingsNilViews(t *testing.T) {
-	state := testRenderState()
-	nilViews := map[string]tuikit.View{
-		"loading": views.NewLoadingView("test", state.Theme),
-		"error":   views.NewErrorView(errors.New("err"), state.Theme),
-		"frame":   views.NewFrameView(&sampleTypes.Echo{Content: "x"}),
+	for _, bad := range []string{"/[a-/", "(a OR b", "a OR", "a )", "-v )"} {
+		if _, err := views.ParseFilterQuery(bad, columns, false); err == nil {
+			t.Errorf("%q: expected a parse error", bad)
+		}
 	}
-	for name, v := range nilViews {
-		t.Run(name, func(t *testing.T) {
-			if bindings := v.HelpBindings(); bindings != nil {
-				t.Errorf("expected nil HelpBindings, got %v", bindings)
+}
+
+func TestParseFilterQueryLiteralText(t *testing.T) {
+	columns := []views.TableColumn{{Title: "Name"}, {Title: "Status"}}
+	rows := [][]string{
+		{"backup at 10:30", "ok"},
+		{"ls -v", "ok"},
+		{`say "hi`, "failed"},
+		{"/usr/bin/env", "failed"},
+		{"owner:me", "ok"},
+		{"-status:ok", "failed"},
+	}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "10:30", want: []string{"backup at 10:30"}},
+		{query: "-v", want: []string{"ls -v"}},
+		{query: `"hi`, want: []string{`say "hi`}},
+		{query: "/usr/bin", want: []string{"/usr/bin/env"}},
+		{query: "/usr", want: []string{"/usr/bin/env"}},
+		{query: "owner:me", want: []string{"owner:me"}},
+		{query: "-status:ok", want: []string{"-status:ok"}},
+		{query: "-v ok", want: []string{"backup at 10:30", "owner:me", "-status:ok"}},
+		{query: "-status:ok failed", want: []string{`say "hi`, "/usr/bin/env", "-status:ok"}},
+		{query: "- ok", want: []string{"ls -v", "-status:ok"}},
+	}
+	for _, tt := range tests {
+		q, err := views.ParseFilterQuery(tt.query, columns, false)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.query, err)
+		}
+		var got []string
+		for _, row := range rows {
+			if q.Match(row) {
+				got = append(got, row[0])
 			}
-		})
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%q: expected %v, got %v", tt.query, tt.want, got)
+		}
 	}
 }
 
-func helpKeys(v tuikit.View) map[string]bool {
-	m := make(map[string]bool)
-	for _, b := range v.HelpBindings() {
-		m[b.Key] = true
+func TestFilterQueryHighlight(t *testing.T) {
+	columns := []views.TableColumn{{Title: "Name"}, {Title: "Status"}}
+	q, err := views.ParseFilterQuery("api -status:api status:fail", columns, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	style := lipgloss.NewStyle().Underline(true)
+	name := q.Highlight(0, "build-api", style)
+	if name == "build-api" || ansi.Strip(name) != "build-api" {
+		t.Errorf("expected the match to be styled, got %q", name)
+	}
+	if !strings.HasPrefix(name, "build-") {
+		t.Errorf("expected only the match to be styled, got %q", name)
+	}
+	if got := q.Highlight(0, "failed", style); got != "failed" {
+		t.Errorf("expected column-scoped terms to skip other columns, got %q", got)
+	}
+	if got := q.Highlight(1, "failed", style); got == "failed" {
+		t.Error("expected the status match to be styled")
 	}
-	return m
 }
 
-func TestHelpBindingsTable(t *testing.T) {
-	state := testRenderState()
-	cols := []views.TableColumn{{Title: "X", Percentage: 100}}
-	rows := []views.TableRow{{Data: []string{"a"}}}
-	table := views.NewTable(state, cols, rows, views.TableDisplayFull)
-	for _, key := range []string{"↑/↓/j/k", "enter", "space/tab", "/"} {
-		if !helpKeys(table)[key] {
-			t.Errorf("missing key %q in table help bindings", key)
-		}
+func TestTableFilterQueryErrors(t *testing.T) {
+	columns := []views.TableColumn{{Title: "Name", Percentage: 50}, {Title: "Status", Percentage: 50}}
+	rows := []views.TableRow{
+		{Data: []string{"alpha", "failed"}},
+		{Data: []string{"beta", "passed"}},
+	}
+	table := views.NewTable(testRenderState(), columns, rows, views.TableDisplayFull)
+	table.Update(tea.KeyPressMsg{Text: "/"})
+	for _, ch := range "status:failed" {
+		table.Update(tea.KeyPressMsg{Text: string(ch)})
+	}
+	content := ansi.Strip(table.View().Content)
+	if strings.Contains(content, "beta") {
+		t.Error("expected beta to be filtered out")
 	}
-}
 
-func TestHelpBindingsDetail(t *testing.T) {
-	state := testRenderState()
-	view := views.NewDetailView(state, "body")
-	for _, key := range []string{"j/k", "u/d", "g/G"} {
-		if !helpKeys(view)[key] {
-			t.Errorf("missing key %q in detail help bindings", key)
-		}
+	// An invalid query keeps the last valid results and shows the error.
+	for _, ch := range ` (x` {
+		table.Update(tea.KeyPressMsg{Text: string(ch)})
+	}
+	content = ansi.Strip(table.View().Content)
+	if !strings.Contains(content, "missing )") {
+		t.Errorf("expected the parse error in the filter bar, got %q", content)
+	}
+	if !strings.Contains(content, "alpha") || strings.Contains(content, "beta") {
+		t.Error("expected the last valid filter to stay applied")
 	}
 }
 
-func TestHelpBindingsMarkdown(t *testing.T) {
-	state := testRenderState()
-	view := views.NewMarkdownView(state, "# hi")
-	if !helpKeys(view)["↑/↓"] {
-		t.Error("missing key ↑/↓ in markdown help bindings")
+func TestTableFuzzyFilter(t *testing.T) {
+	columns := []views.TableColumn{{Title: "Name", Percentage: 100}}
+	rows := []views.TableRow{{Data: []string{"deploy-web"}}, {Data: []string{"build"}}}
+	table := views.NewTable(testRenderState(), columns, rows, views.TableDisplayFull)
+	table.FuzzyFilter = true
+	table.Update(tea.KeyPressMsg{Text: "/"})
+	for _, ch := range "dpw" {
+		table.Update(tea.KeyPressMsg{Text: string(ch)})
+	}
+	content := ansi.Strip(table.View().Content)
+	if !strings.Contains(content, "deploy-web") || strings.Contains(content, "build") {
+		t.Errorf("expected only the fuzzy match, got %q", content)
 	}
 }
 
-// --- Theme render tests ---
+// --- Table sort tests ---
+
+// assertOrder checks that the values appear in the rendered content in the given order.
+// Styling is ignored, since filter matches are highlighted within cells.
+func assertOrder(t *testing.T, content string, values ...string) {
+	t.Helper()
+	content = ansi.Strip(content)
+	last := -1
+	for _, v := range values {
+		idx := strings.Index(content, v)
+		if idx < 0 {
+			t.Fatalf("expected %q in content", v)
+		}
+		if idx < last {
+			t.Errorf("expected order %v, got %q", values, content)
+			return
+		}
+		last = idx
+	}
+}
 
-func TestRenderHeader(t *testing.T) {
-	theme := themes.EverforestTheme()
-	header := theme.RenderHeader("MyApp", "v1.0", "Env", "prod", 80)
-	if header == "" {
-		t.Fatal("expected non-empty header")
+func TestTableSortCycle(t *testing.T) {
+	state := testRenderState()
+	columns := []views.TableColumn{
+		{Title: "Name", Percentage: 50},
+		{Title: "Size", Percentage: 50},
 	}
-	if !strings.Contains(header, "MyApp") {
-		t.Error("expected app name in header")
+	rows := []views.TableRow{
+		{Data: []string{"bravo", "10"}},
+		{Data: []string{"alpha", "9"}},
+		{Data: []string{"charlie", "100"}},
 	}
-	if !strings.Contains(header, "help") {
-		t.Error("expected help hint in header")
+	table := views.NewTable(state, columns, rows, views.TableDisplayFull)
+
+	// s cycles the first column: ascending, descending, off.
+	table.Update(tea.KeyPressMsg{Text: "s"})
+	content := table.View().Content
+	if !strings.Contains(content, "Name ▲") {
+		t.Error("expected ascending indicator on Name")
 	}
-	if !strings.Contains(header, "v1.0") {
-		t.Error("expected version in header")
+	assertOrder(t, content, "alpha", "bravo", "charlie")
+
+	table.Update(tea.KeyPressMsg{Text: "s"})
+	content = table.View().Content
+	if !strings.Contains(content, "Name ▼") {
+		t.Error("expected descending indicator on Name")
 	}
-}
+	assertOrder(t, content, "charlie", "bravo", "alpha")
 
-func TestRenderHeaderNoVersion(t *testing.T) {
-	theme := themes.EverforestTheme()
-	header := theme.RenderHeader("MyApp", "", "", "", 80)
-	if !strings.Contains(header, "MyApp") {
-		t.Error("expected app name in header")
+	table.Update(tea.KeyPressMsg{Text: "s"})
+	content = table.View().Content
+	if strings.Contains(content, "▲") || strings.Contains(content, "▼") {
+		t.Error("expected no sort indicator when sorting is off")
 	}
-	if strings.Contains(header, "v1") {
-		t.Error("expected no version in header")
+	assertOrder(t, content, "bravo", "alpha", "charlie")
+
+	// S moves to the next column, which is compared numerically.
+	table.Update(tea.KeyPressMsg{Text: "S"})
+	if col, order := table.Sort(); col != 1 || order != views.SortAscending {
+		t.Fatalf("expected ascending sort on column 1, got %d/%v", col, order)
 	}
+	assertOrder(t, table.View().Content, "alpha", "bravo", "charlie")
 }
 
-func TestRenderHelpPopup(t *testing.T) {
-	theme := themes.EverforestTheme()
-	keys := []themes.HelpKey{
-		{Key: "q", Desc: "quit"},
-		{Key: "enter", Desc: "select"},
+func TestTableSortChildrenAndFilter(t *testing.T) {
+	state := testRenderState()
+	columns := []views.TableColumn{{Title: "Name", Percentage: 100, Compare: views.CompareStrings}}
+	rows := []views.TableRow{
+		{Data: []string{"zeta"}, Expanded: true, Children: []views.TableRow{
+			{Data: []string{"zeta-b"}},
+			{Data: []string{"zeta-a"}},
+		}},
+		{Data: []string{"eta"}},
+		{Data: []string{"beta"}},
 	}
-	out := theme.RenderHelpPopup(keys, 80, 40)
-	if out == "" {
-		t.Fatal("expected non-empty popup")
+	table := views.NewTable(state, columns, rows, views.TableDisplayFull)
+	table.SetSort(0, views.SortAscending)
+	assertOrder(t, table.View().Content, "beta", "eta", "zeta", "zeta-a", "zeta-b")
+
+	// Selection follows the row across sort changes.
+	if got := table.SelectedData()[0]; got != "zeta" {
+		t.Errorf("expected selection to stay on zeta, got %q", got)
+	}
+	table.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	table.SetSort(0, views.SortDescending)
+	if got := table.SelectedData()[0]; got != "zeta-a" {
+		t.Errorf("expected selection to stay on zeta-a, got %q", got)
 	}
-}
 
-func TestRenderHelpPopupEmpty(t *testing.T) {
-	theme := themes.EverforestTheme()
-	out := theme.RenderHelpPopup(nil, 80, 40)
-	if out != "" {
-		t.Fatalf("expected empty string for nil keys, got %q", out)
+	table.Update(tea.KeyPressMsg{Text: "/"})
+	for _, r := range "eta" {
+		table.Update(tea.KeyPressMsg{Text: string(r)})
 	}
+	table.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	assertOrder(t, table.View().Content, "zeta", "zeta-b", "zeta-a", "beta")
 }
 
-func TestRenderToast(t *testing.T) {
-	theme := themes.EverforestTheme()
-	levels := []themes.OutputLevel{
-		themes.OutputLevelSuccess,
-		themes.OutputLevelWarning,
-		themes.OutputLevelError,
-		themes.OutputLevelInfo,
-		themes.OutputLevelNotice,
+func TestTableCompareFuncs(t *testing.T) {
+	if views.CompareNumbers("9", "10") >= 0 {
+		t.Error("expected 9 < 10")
 	}
-	for _, lvl := range levels {
-		out := theme.RenderToast("test message", lvl, 80)
-		if out == "" {
-			t.Errorf("expected non-empty toast for level %s", lvl)
-		}
+	if views.CompareDates("2024-02-01", "2024-10-01") >= 0 {
+		t.Error("expected February before October")
+	}
+	if views.CompareStrings("Apple", "banana") >= 0 {
+		t.Error("expected case-insensitive string comparison")
 	}
 }
 
-// --- Library view tests ---
-
-func testLibrary() *views.Library {
-	state := testRenderState()
-	page0 := views.LibraryPage{
-		Title: "Categories",
-		Factory: func(render *types.RenderState, _ []views.PageSelection) (tea.Model, []types.KeyCallback) {
-			cols := []views.TableColumn{{Title: "Name", Percentage: 50}, {Title: "Count", Percentage: 50}}
-			rows := []views.TableRow{
-				{Data: []string{"Alpha", "3"}},
-				{Data: []string{"Beta", "5"}},
-			}
-			return views.NewTable(render, cols, rows, views.TableDisplayFull), nil
-		},
-	}
-	page1 := views.LibraryPage{
-		Title: "Items",
-		Factory: func(render *types.RenderState, selections []views.PageSelection) (tea.Model, []types.KeyCallback) {
-			cat := selections[0].Data[0]
-			cols := []views.TableColumn{{Title: "Item", Percentage: 100}}
-			rows := []views.TableRow{
-				{Data: []string{cat + "-item-1"}},
-				{Data: []string{cat + "-item-2"}},
+func TestTableSortMixedValues(t *testing.T) {
+	orders := [][]string{{"10", "9a", "9"}, {"9", "10", "9a"}, {"9a", "9", "10"}}
+	var want []string
+	for _, values := range orders {
+		rows := make([]views.TableRow, len(values))
+		for i, v := range values {
+			rows[i] = views.TableRow{Data: []string{v}}
+		}
+		table := views.NewTable(testRenderState(), []views.TableColumn{{Title: "Value", Percentage: 100}}, rows, views.TableDisplayFull)
+		table.SetSort(0, views.SortAscending)
+		var got []string
+		for _, line := range strings.Split(ansi.Strip(table.View().Content), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
 			}
-			keys := []types.KeyCallback{
-				{Key: "x", Label: "action", Callback: func() error { return nil }},
+			if field := fields[len(fields)-1]; field == "10" || field == "9" || field == "9a" {
+				got = append(got, field)
 			}
-			return views.NewTable(render, cols, rows, views.TableDisplayFull), keys
-		},
-	}
-	page2 := views.LibraryPage{
-		Title: "Details",
-		Factory: func(render *types.RenderState, selections []views.PageSelection) (tea.Model, []types.KeyCallback) {
-			item := selections[1].Data[0]
-			return views.NewDetailView(render, "Detail for "+item), nil
-		},
+		}
+		if len(got) != len(values) {
+			t.Fatalf("expected %d rows, got %v", len(values), got)
+		}
+		if want == nil {
+			want = got
+		} else if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("expected the order not to depend on the input, got %v and %v", want, got)
+		}
 	}
-	return views.NewLibrary(state, page0, page1, page2)
 }
 
-func TestLibraryViewType(t *testing.T) {
-	lib := testLibrary()
-	if lib.Type() != views.LibraryViewType {
-		t.Errorf("expected type %q, got %q", views.LibraryViewType, lib.Type())
+// --- Table multi-select tests ---
+
+func testMultiSelectTable() *views.Table {
+	columns := []views.TableColumn{{Title: "Name", Percentage: 100}}
+	rows := []views.TableRow{
+		{Data: []string{"one"}, Children: []views.TableRow{{Data: []string{"one-a"}}}},
+		{Data: []string{"two"}},
+		{Data: []string{"three"}},
 	}
+	table := views.NewTable(testRenderState(), columns, rows, views.TableDisplayFull)
+	table.MultiSelect = true
+	return table
 }
 
-func TestLibraryInitialRender(t *testing.T) {
-	lib := testLibrary()
-	content := lib.View().Content
-	// Breadcrumb should show the first page title
-	if !strings.Contains(content, "Categories") {
-		t.Error("expected breadcrumb with 'Categories'")
-	}
-	// Table content from page 0
-	if !strings.Contains(content, "Alpha") {
-		t.Error("expected page 0 table row 'Alpha'")
-	}
-	if !strings.Contains(content, "Beta") {
-		t.Error("expected page 0 table row 'Beta'")
+func rowNames(rows []views.TableRow) []string {
+	names := make([]string, len(rows))
+	for i, r := range rows {
+		names[i] = r.Data[0]
 	}
+	return names
 }
 
-func TestLibraryNavigateForward(t *testing.T) {
-	lib := testLibrary()
+func TestTableMultiSelectMark(t *testing.T) {
+	table := testMultiSelectTable()
 
-	// Navigate forward from page 0 -> page 1
-	lib.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
-	content := lib.View().Content
+	// space marks the row and moves down; tab still expands.
+	table.Update(tea.KeyPressMsg{Text: "tab", Code: tea.KeyTab})
+	table.Update(tea.KeyPressMsg{Text: " ", Code: tea.KeySpace})
+	table.Update(tea.KeyPressMsg{Text: "m", Code: 'm'})
 
-	// Breadcrumb should show both pages
-	if !strings.Contains(content, "Categories") {
-		t.Error("expected breadcrumb with 'Categories'")
+	if got := strings.Join(rowNames(table.SelectedRows()), ","); got != "one,one-a" {
+		t.Errorf("expected one,one-a to be marked, got %q", got)
 	}
-	if !strings.Contains(content, "Items") {
-		t.Error("expected breadcrumb with 'Items'")
+	content := table.View().Content
+	if !strings.Contains(content, "[x]") {
+		t.Error("expected marked rows to be rendered with a mark")
 	}
-	// Page 1 content derived from page 0 selection ("Alpha")
-	if !strings.Contains(content, "Alpha-item-1") {
-		t.Error("expected page 1 row 'Alpha-item-1'")
+	if !strings.Contains(content, "2 selected") {
+		t.Error("expected selection count in view")
+	}
+	// The cursor row is still reported for Selectable consumers.
+	if got := table.SelectedData()[0]; got != "two" {
+		t.Errorf("expected cursor on two, got %q", got)
 	}
 }
 
-func TestLibraryNavigateBackward(t *testing.T) {
-	lib := testLibrary()
-
-	// Go to page 1
-	lib.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
-	// Go back to page 0
-	lib.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+func TestTableMultiSelectAllAndInvert(t *testing.T) {
+	table := testMultiSelectTable()
 
-	content := lib.View().Content
-	if !strings.Contains(content, "Alpha") {
-		t.Error("expected page 0 content after navigating back")
+	table.Update(tea.KeyPressMsg{Code: 'a', Mod: tea.ModCtrl})
+	if table.MarkedCount() != 3 {
+		t.Errorf("expected all 3 visible rows marked, got %d", table.MarkedCount())
 	}
-	if strings.Contains(content, "Alpha-item-1") {
-		t.Error("should not show page 1 content after navigating back")
+	table.Update(tea.KeyPressMsg{Code: 'a', Mod: tea.ModCtrl})
+	if table.MarkedCount() != 0 {
+		t.Errorf("expected select-all to clear when everything is marked, got %d", table.MarkedCount())
 	}
-}
 
-func TestLibraryCapturingInput(t *testing.T) {
-	lib := testLibrary()
-	ic, ok := any(lib).(tuikit.InputCapturer)
-	if !ok {
-		t.Fatal("Library should implement InputCapturer")
+	table.Update(tea.KeyPressMsg{Text: " ", Code: tea.KeySpace})
+	table.Update(tea.KeyPressMsg{Text: "*", Code: '*'})
+	if got := strings.Join(rowNames(table.SelectedRows()), ","); got != "two,three" {
+		t.Errorf("expected inverted selection two,three, got %q", got)
 	}
+}
 
-	// On page 0, not capturing
-	if ic.CapturingInput() {
-		t.Error("should not be capturing input on page 0")
+func TestTableBulkAction(t *testing.T) {
+	table := testMultiSelectTable()
+	var got []views.TableRow
+	table.BulkActions = []views.TableBulkAction{
+		{Key: "d", Label: "delete", Callback: func(rows []views.TableRow) error {
+			got = rows
+			return nil
+		}},
 	}
 
-	// Navigate to page 1
-	lib.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
-	if !ic.CapturingInput() {
-		t.Error("should be capturing input on page > 0")
+	// Without marks, the cursor row is used.
+	_, cmd := table.Update(tea.KeyPressMsg{Text: "d", Code: 'd'})
+	if cmd == nil {
+		t.Fatal("expected bulk action command")
+	}
+	cmd()
+	if names := strings.Join(rowNames(got), ","); names != "one" {
+		t.Errorf("expected cursor row, got %q", names)
 	}
 
-	// Navigate back to page 0
-	lib.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
-	if ic.CapturingInput() {
-		t.Error("should not be capturing input after returning to page 0")
+	table.Update(tea.KeyPressMsg{Text: "*", Code: '*'})
+	_, cmd = table.Update(tea.KeyPressMsg{Text: "d", Code: 'd'})
+	cmd()
+	if names := strings.Join(rowNames(got), ","); names != "one,two,three" {
+		t.Errorf("expected all marked rows, got %q", names)
+	}
+	if !helpKeys(table)["d"] {
+		t.Error("expected bulk action in help bindings")
 	}
 }
 
-func TestLibraryHelpBindings(t *testing.T) {
-	lib := testLibrary()
+// --- Table styling tests ---
 
-	// Page 0: should have drill-down key but not go-back
-	keys := helpKeys(lib)
-	if !keys["enter/→"] {
-		t.Error("expected 'enter/→' help binding on page 0")
-	}
-	if keys["esc/<-"] {
-		t.Error("should not have 'esc/<-' on page 0")
+// lineWith returns the rendered line containing the text.
+func lineWith(t *testing.T, content, text string) string {
+	t.Helper()
+	for _, line := range strings.Split(content, "\n") {
+		if strings.Contains(ansi.Strip(line), text) {
+			return line
+		}
+	}
+	t.Fatalf("expected %q in content", text)
+	return ""
+}
+
+func TestTableRowAndCellStylers(t *testing.T) {
+	columns := []views.TableColumn{{Title: "Name", Percentage: 50}, {Title: "Status", Percentage: 50}}
+	rows := []views.TableRow{
+		{Data: []string{"first-run", "failed"}},
+		{Data: []string{"second-run", "failed"}},
+		{Data: []string{"third-run", "disabled"}},
+		{Data: []string{"fourth-run", "ok"}},
+	}
+	table := views.NewTable(testRenderState(), columns, rows, views.TableDisplayFull)
+	table.RowStyler = func(row views.TableRow) views.TableStyle {
+		switch row.Data[1] {
+		case "failed":
+			return views.TableStyle{Level: themes.OutputLevelError}
+		case "disabled":
+			return views.TableStyle{Dim: true}
+		}
+		return views.TableStyle{}
+	}
+	table.CellStyler = func(row views.TableRow, column int) views.TableStyle {
+		if column == 1 && row.Data[1] == "ok" {
+			return views.TableStyle{Level: themes.OutputLevelSuccess, Bold: true}
+		}
+		return views.TableStyle{}
+	}
+
+	cp := testRenderState().Theme.ColorPalette()
+	// colorSeq returns the SGR parameters of the level's foreground color.
+	colorSeq := func(lvl themes.OutputLevel) string {
+		seq := strings.Split(lipgloss.NewStyle().Foreground(cp.LevelColor(lvl)).Render("x"), "x")[0]
+		return strings.TrimSuffix(strings.TrimPrefix(seq, "\x1b["), "m")
+	}
+	content := table.View().Content
+	if line := lineWith(t, content, "first-run"); strings.Contains(line, colorSeq(themes.OutputLevelError)) {
+		t.Errorf("expected the selected row style to win, got %q", line)
+	}
+	if line := lineWith(t, content, "second-run"); !strings.Contains(line, colorSeq(themes.OutputLevelError)) {
+		t.Errorf("expected the failed row to use the error color, got %q", line)
+	}
+	if line := lineWith(t, content, "third-run"); !strings.Contains(line, colorSeq("")) {
+		t.Errorf("expected the disabled row to be dimmed, got %q", line)
+	}
+	line := lineWith(t, content, "fourth-run")
+	if strings.Contains(strings.Split(line, "fourth-run")[0], colorSeq(themes.OutputLevelSuccess)) {
+		t.Errorf("expected only the status cell to be styled, got %q", line)
+	}
+	if !strings.Contains(line, colorSeq(themes.OutputLevelSuccess)) {
+		t.Errorf("expected the status cell to use the success color, got %q", line)
+	}
+}
+
+// --- Table column sizing tests ---
+
+func TestTableColumnSizing(t *testing.T) {
+	state := testRenderState()
+	columns := []views.TableColumn{
+		{Title: "Name", AutoWidth: true},
+		{Title: "Size", Width: 10, Align: views.AlignRight, Format: views.FormatBytes},
+		{Title: "Took", MinWidth: 6, Format: views.FormatDuration},
+	}
+	rows := []views.TableRow{
+		{Data: []string{"build", "1536", "90s"}},
+		{Data: []string{"test", "12", "250ms"}},
+	}
+	table := views.NewTable(state, columns, rows, views.TableDisplayFull)
+	content := table.View().Content
+	for _, want := range []string{"1.5 KiB", "12 B", "1m30s", "250ms"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected formatted value %q in %q", want, content)
+		}
+	}
+	// Right-aligned values end at the same column.
+	var ends []int
+	for _, line := range strings.Split(content, "\n") {
+		for _, v := range []string{"1.5 KiB", "12 B"} {
+			if i := strings.Index(line, v); i >= 0 {
+				ends = append(ends, lipgloss.Width(line[:i])+len(v))
+			}
+		}
+	}
+	if len(ends) != 2 || ends[0] != ends[1] {
+		t.Errorf("expected right-aligned size column, got ends %v", ends)
+	}
+}
+
+func TestTableAutoWidthCached(t *testing.T) {
+	calls := 0
+	columns := []views.TableColumn{
+		{Title: "Name", AutoWidth: true, Format: func(value string, _ themes.Theme) string {
+			calls++
+			return value
+		}},
+		{Title: "Other", Percentage: 100},
+	}
+	rows := make([]views.TableRow, 200)
+	for i := range rows {
+		rows[i] = views.TableRow{Key: fmt.Sprint(i), Data: []string{fmt.Sprintf("row %d", i), "x"}}
+	}
+	table := views.NewTable(testRenderState(), columns, rows, views.TableDisplayFull)
+	otherAt := func() int {
+		header := ansi.Strip(firstLine(table.View().Content))
+		return strings.Index(header, "Other")
+	}
+	before := otherAt()
+	calls = 0
+	table.View()
+	if calls >= len(rows) {
+		t.Errorf("expected the column width to be cached, formatted %d cells", calls)
+	}
+
+	table.UpsertRows(views.TableRow{Key: "wide", Data: []string{"a much wider row name", "x"}})
+	if after := otherAt(); after <= before {
+		t.Errorf("expected the column to widen after an upsert, moved from %d to %d", before, after)
+	}
+}
+
+func TestTableColumnsDegrade(t *testing.T) {
+	state := testRenderState()
+	state.ContentWidth = 30
+	columns := []views.TableColumn{
+		{Title: "Name", MinWidth: 10},
+		{Title: "Owner", MinWidth: 10},
+		{Title: "Extra", MinWidth: 10},
+	}
+	rows := []views.TableRow{{Data: []string{"alpha", "bob", "hidden"}}}
+	table := views.NewTable(state, columns, rows, views.TableDisplayFull)
+	content := table.View().Content
+	if !strings.Contains(content, "alpha") || !strings.Contains(content, "Owner") {
+		t.Errorf("expected leading columns to be shown, got %q", content)
+	}
+	if strings.Contains(content, "Extra") {
+		t.Errorf("expected trailing column to be hidden, got %q", content)
+	}
+	for _, line := range strings.Split(content, "\n") {
+		if w := lipgloss.Width(line); w > state.ContentWidth {
+			t.Errorf("line exceeds width %d: %q", w, line)
+		}
+	}
+}
+
+func TestTableCellFormatters(t *testing.T) {
+	theme := themes.EverforestTheme()
+	if got := views.FormatDuration("2h5m", theme); got != "2h05m" {
+		t.Errorf("unexpected duration %q", got)
+	}
+	if got := views.FormatBytes("3145728", theme); got != "3.0 MiB" {
+		t.Errorf("unexpected byte size %q", got)
+	}
+	if got := views.FormatTimestamp("2006-01-02")("2024-03-04T05:06:07Z", theme); !strings.HasPrefix(got, "2024-03-0") {
+		t.Errorf("unexpected timestamp %q", got)
+	}
+	badge := views.FormatStatusBadge(map[string]themes.OutputLevel{"failed": themes.OutputLevelError})
+	if got := badge("FAILED", theme); !strings.Contains(got, "FAILED") {
+		t.Errorf("unexpected badge %q", got)
+	}
+	if got := views.FormatBytes("n/a", theme); got != "n/a" {
+		t.Errorf("expected unparseable value unchanged, got %q", got)
+	}
+}
+
+// --- Table horizontal scrolling tests ---
+
+func testWideTable() *views.Table {
+	state := testRenderState()
+	state.ContentWidth = 40
+	columns := []views.TableColumn{
+		{Title: "Name", Width: 10},
+		{Title: "ColB", Width: 10},
+		{Title: "ColC", Width: 10},
+		{Title: "ColD", Width: 10},
+		{Title: "ColE", Width: 10},
+	}
+	rows := []views.TableRow{{Data: []string{"alpha", "b-val", "c-val", "d-val", "e-val"}}}
+	table := views.NewTable(state, columns, rows, views.TableDisplayFull)
+	table.FrozenColumns = 1
+	return table
+}
+
+func TestTableHorizontalScroll(t *testing.T) {
+	table := testWideTable()
+	assertColumns := func(shown, hidden []string, left, right bool) {
+		t.Helper()
+		content := ansi.Strip(table.View().Content)
+		for _, s := range shown {
+			if !strings.Contains(content, s) {
+				t.Errorf("expected %q to be shown in %q", s, content)
+			}
+		}
+		for _, s := range hidden {
+			if strings.Contains(content, s) {
+				t.Errorf("expected %q to be hidden in %q", s, content)
+			}
+		}
+		if strings.Contains(content, "‹") != left || strings.Contains(content, "›") != right {
+			t.Errorf("expected indicators left=%v right=%v in %q", left, right, content)
+		}
+		for _, line := range strings.Split(content, "\n") {
+			if w := lipgloss.Width(line); w > 40 {
+				t.Errorf("line exceeds width 40: %q", line)
+			}
+		}
+	}
+
+	assertColumns([]string{"Name", "alpha", "ColB", "ColC"}, []string{"ColD", "ColE"}, false, true)
+	table.Update(tea.KeyPressMsg{Text: ">", Code: '>'})
+	assertColumns([]string{"Name", "alpha", "ColC", "ColD"}, []string{"ColB", "ColE"}, true, true)
+	table.Update(tea.KeyPressMsg{Text: ">", Code: '>'})
+	assertColumns([]string{"Name", "ColD", "ColE", "e-val"}, []string{"ColB", "ColC"}, true, false)
+	table.Update(tea.KeyPressMsg{Text: ">", Code: '>'})
+	assertColumns([]string{"Name", "ColD", "ColE"}, []string{"ColB", "ColC"}, true, false)
+	table.Update(tea.KeyPressMsg{Text: "<", Code: '<'})
+	table.Update(tea.KeyPressMsg{Text: "<", Code: '<'})
+	assertColumns([]string{"Name", "ColB", "ColC"}, []string{"ColD", "ColE"}, false, true)
+}
+
+func TestTableColumnChooser(t *testing.T) {
+	table := testWideTable()
+	table.Update(tea.KeyPressMsg{Text: "C", Code: 'C'})
+	if !table.CapturingInput() {
+		t.Fatal("expected the column chooser to capture input")
+	}
+	if content := table.View().Content; !strings.Contains(content, i18n.T(i18n.MsgColumnsTitle)) {
+		t.Errorf("expected the column chooser overlay, got %q", content)
+	}
+
+	// Hide ColB, then move ColE above ColD.
+	table.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	table.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	for range 3 {
+		table.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	}
+	table.Update(tea.KeyPressMsg{Text: "K", Code: 'K'})
+	table.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if table.CapturingInput() {
+		t.Error("expected esc to close the column chooser")
+	}
+	if got := fmt.Sprint(table.VisibleColumns()); got != "[0 2 4 3]" {
+		t.Errorf("expected visible columns [0 2 4 3], got %s", got)
+	}
+	assertOrder(t, table.View().Content, "Name", "ColC", "ColE")
+
+	var buf bytes.Buffer
+	if err := table.Export(&buf, views.ExportOptions{Format: views.ExportCSV}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Name,ColC,ColE,ColD\nalpha,c-val,e-val,d-val\n"; buf.String() != want {
+		t.Errorf("expected export to follow the visible columns, got %q", buf.String())
+	}
+
+	// The last visible column can't be hidden.
+	for i := range 5 {
+		table.SetColumnVisible(i, false)
+	}
+	if len(table.VisibleColumns()) != 1 {
+		t.Errorf("expected one column to stay visible, got %v", table.VisibleColumns())
+	}
+}
+
+func TestTableColumnChooserNoColumns(t *testing.T) {
+	table := views.NewTable(testRenderState(), nil, nil, views.TableDisplayFull)
+	table.Update(tea.KeyPressMsg{Text: "C", Code: 'C'})
+	if table.CapturingInput() {
+		t.Error("expected the column chooser to stay closed without columns")
+	}
+	table.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	_ = table.View()
+}
+
+// --- Table display width tests ---
+
+func TestTableTruncatesWideCharacters(t *testing.T) {
+	state := testRenderState()
+	state.ContentWidth = 40
+	columns := []views.TableColumn{
+		{Title: "名前", Width: 12},
+		{Title: "Status"},
+	}
+	styled := lipgloss.NewStyle().Bold(true).Render("styled-status-value-that-is-long")
+	rows := []views.TableRow{
+		{Data: []string{"日本語のとても長い名前です", "ok"}},
+		{Data: []string{"🚀🚀🚀🚀🚀🚀🚀🚀🚀🚀", styled}},
+	}
+	table := views.NewTable(state, columns, rows, views.TableDisplayFull)
+	content := table.View().Content
+	if !utf8.ValidString(content) {
+		t.Fatal("expected valid UTF-8 after truncation")
+	}
+	if !strings.Contains(content, "...") {
+		t.Error("expected an ellipsis for truncated cells")
+	}
+	for _, line := range strings.Split(content, "\n") {
+		if w := lipgloss.Width(line); w > state.ContentWidth {
+			t.Errorf("line exceeds width %d (%d): %q", state.ContentWidth, w, line)
+		}
+	}
+}
+
+func TestTableWrapColumn(t *testing.T) {
+	state := testRenderState()
+	columns := []views.TableColumn{
+		{Title: "Name", Width: 10},
+		{Title: "Description", Width: 12, Wrap: true},
+	}
+	rows := []views.TableRow{
+		{Data: []string{"alpha", "一二三四五六七八九十 wraps onto lines"}},
+		{Data: []string{"beta", "short"}},
+	}
+	table := views.NewTable(state, columns, rows, views.TableDisplayFull)
+	content := table.View().Content
+	if strings.Contains(content, "...") {
+		t.Error("wrapped columns should not be truncated")
+	}
+	for _, word := range []string{"一二三四五", "wraps", "lines", "beta"} {
+		if !strings.Contains(content, word) {
+			t.Errorf("expected %q in wrapped output %q", word, content)
+		}
+	}
+	table.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	if got := table.SelectedData()[0]; got != "beta" {
+		t.Errorf("expected navigation across wrapped rows, got %q", got)
+	}
+}
+
+func TestDetailViewWideKeys(t *testing.T) {
+	state := testRenderState()
+	view := views.NewDetailView(state, "body",
+		views.DetailField{Key: "名前", Value: "alpha"},
+		views.DetailField{Key: "Owner", Value: "bob"},
+	)
+	var seps []int
+	for _, line := range strings.Split(view.View().Content, "\n") {
+		if i := strings.Index(line, "│"); i >= 0 && (strings.Contains(line, "alpha") || strings.Contains(line, "bob")) {
+			seps = append(seps, lipgloss.Width(line[:i]))
+		}
+	}
+	if len(seps) != 2 || seps[0] != seps[1] {
+		t.Errorf("expected aligned separators, got %v", seps)
+	}
+}
+
+// --- Table tree tests ---
+
+func testTreeTable() *views.Table {
+	columns := []views.TableColumn{{Title: "Name", Percentage: 100}}
+	rows := []views.TableRow{
+		{Data: []string{"root-a"}, Children: []views.TableRow{
+			{Data: []string{"mid-a1"}, Children: []views.TableRow{
+				{Data: []string{"leaf-deep"}},
+			}},
+			{Data: []string{"mid-a2"}},
+		}},
+		{Data: []string{"root-b"}, Children: []views.TableRow{
+			{Data: []string{"mid-b1"}},
+		}},
+	}
+	return views.NewTable(testRenderState(), columns, rows, views.TableDisplayFull)
+}
+
+func TestTableTreeExpandAll(t *testing.T) {
+	table := testTreeTable()
+
+	table.Update(tea.KeyPressMsg{Text: "+", Code: '+'})
+	content := table.View().Content
+	assertOrder(t, content, "root-a", "mid-a1", "leaf-deep", "mid-a2", "root-b", "mid-b1")
+	for _, guide := range []string{"├─ ", "└─ ", "│  └─ "} {
+		if !strings.Contains(content, guide) {
+			t.Errorf("expected tree guide %q in %q", guide, content)
+		}
+	}
+
+	table.Update(tea.KeyPressMsg{Text: "-", Code: '-'})
+	content = table.View().Content
+	if strings.Contains(content, "mid-a1") || strings.Contains(content, "mid-b1") {
+		t.Error("expected all rows to be collapsed")
+	}
+}
+
+func TestTableTreeMultipleBranches(t *testing.T) {
+	table := testTreeTable()
+
+	// Expand root-a, its first child, then root-b; earlier branches stay open.
+	table.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	table.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	table.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	for range 3 {
+		table.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	}
+	if got := table.SelectedData()[0]; got != "root-b" {
+		t.Fatalf("expected cursor on root-b, got %q", got)
+	}
+	table.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	assertOrder(t, table.View().Content, "root-a", "mid-a1", "leaf-deep", "mid-a2", "root-b", "mid-b1")
+}
+
+func TestTableTreeFilterKeepsAncestors(t *testing.T) {
+	table := testTreeTable()
+	table.Update(tea.KeyPressMsg{Text: "/"})
+	for _, ch := range "deep" {
+		table.Update(tea.KeyPressMsg{Text: string(ch)})
+	}
+	content := table.View().Content
+	assertOrder(t, content, "root-a", "mid-a1", "leaf-deep")
+	for _, hidden := range []string{"mid-a2", "root-b"} {
+		if strings.Contains(content, hidden) {
+			t.Errorf("expected %q to be filtered out", hidden)
+		}
+	}
+}
+
+func TestTableTreeLazyChildren(t *testing.T) {
+	columns := []views.TableColumn{{Title: "Name", Percentage: 100}}
+	rows := []views.TableRow{{Data: []string{"remote"}, Lazy: true}}
+	table := views.NewTable(testRenderState(), columns, rows, views.TableDisplayFull)
+	calls := 0
+	table.LoadChildren = func(row views.TableRow) ([]views.TableRow, error) {
+		calls++
+		return []views.TableRow{{Data: []string{row.Data[0] + "-child"}}}, nil
+	}
+
+	_, cmd := table.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	if cmd == nil {
+		t.Fatal("expected a load command")
+	}
+	if strings.Contains(table.View().Content, "remote-child") {
+		t.Error("children should not be shown before they are loaded")
+	}
+	// Run the batched commands and feed the loaded children back to the table.
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		t.Fatalf("expected batch of load and spinner commands, got %T", msg)
+	}
+	for _, c := range batch {
+		table.Update(c())
+	}
+	if calls != 1 {
+		t.Errorf("expected loader to be called once, got %d", calls)
+	}
+	if !strings.Contains(table.View().Content, "remote-child") {
+		t.Error("expected loaded children to be shown")
+	}
+
+	// Collapsing and expanding again uses the loaded children.
+	table.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	table.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	if calls != 1 {
+		t.Errorf("expected children to be loaded only once, got %d calls", calls)
+	}
+}
+
+// --- Table data source tests ---
+
+type testRowSource struct {
+	rows    []string
+	fetches int
+}
+
+func (s *testRowSource) SupportsFilter() bool { return true }
+func (s *testRowSource) SupportsSort() bool   { return false }
+
+func (s *testRowSource) matching(query views.TableQuery) []string {
+	if query.Filter == "" {
+		return s.rows
+	}
+	var out []string
+	for _, r := range s.rows {
+		if strings.Contains(r, query.Filter) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (s *testRowSource) Count(query views.TableQuery) (int, error) {
+	return len(s.matching(query)), nil
+}
+
+func (s *testRowSource) Fetch(query views.TableQuery, offset, limit int) ([]views.TableRow, error) {
+	s.fetches++
+	rows := s.matching(query)
+	end := min(offset+limit, len(rows))
+	out := make([]views.TableRow, 0, end-offset)
+	for _, r := range rows[offset:end] {
+		out = append(out, views.TableRow{Data: []string{r}})
+	}
+	return out, nil
+}
+
+// drain runs a command and feeds the resulting messages back to the model until no
+// commands are left.
+func drain(m tea.Model, cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	switch msg := cmd().(type) {
+	case nil:
+	case tea.BatchMsg:
+		for _, c := range msg {
+			drain(m, c)
+		}
+	default:
+		_, next := m.Update(msg)
+		drain(m, next)
+	}
+}
+
+func TestTableDataSource(t *testing.T) {
+	source := &testRowSource{}
+	for i := range 100_000 {
+		source.rows = append(source.rows, fmt.Sprintf("exec-%06d", i))
+	}
+	columns := []views.TableColumn{{Title: "Execution", Percentage: 100}}
+	table := views.NewTableFromSource(testRenderState(), columns, source, 50, views.TableDisplayFull)
+
+	if !strings.Contains(table.View().Content, "loading") {
+		t.Error("expected loading message before the count is known")
+	}
+	drain(table, table.Init())
+	content := table.View().Content
+	if !strings.Contains(content, "exec-000000") {
+		t.Errorf("expected first page to be rendered, got %q", content)
+	}
+	if !strings.Contains(content, "99964 more") {
+		t.Errorf("expected remaining count for 100k rows, got %q", content)
+	}
+	if source.fetches > 2 {
+		t.Errorf("expected only the visible pages to be fetched, got %d fetches", source.fetches)
+	}
+
+	// Moving past the cached pages fetches the next ones.
+	for range 120 {
+		_, cmd := table.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		drain(table, cmd)
+	}
+	if got := table.SelectedData(); len(got) == 0 || got[0] != "exec-000120" {
+		t.Errorf("expected cursor on exec-000120, got %v", got)
+	}
+}
+
+func TestTableDataSourceFilter(t *testing.T) {
+	source := &testRowSource{rows: []string{"build-1", "test-1", "build-2"}}
+	columns := []views.TableColumn{{Title: "Execution", Percentage: 100}}
+	table := views.NewTableFromSource(testRenderState(), columns, source, 0, views.TableDisplayFull)
+	drain(table, table.Init())
+
+	table.Update(tea.KeyPressMsg{Text: "/"})
+	// Live input is debounced; earlier keystrokes' queries are superseded.
+	var cmd tea.Cmd
+	for _, ch := range "test" {
+		_, cmd = table.Update(tea.KeyPressMsg{Text: string(ch)})
+	}
+	drain(table, cmd)
+	content := table.View().Content
+	if !strings.Contains(content, "test-1") || strings.Contains(content, "build-1") {
+		t.Errorf("expected source-side filter to be applied, got %q", content)
+	}
+}
+
+func TestTableDataSourceMarks(t *testing.T) {
+	source := &testRowSource{}
+	for i := range 1000 {
+		source.rows = append(source.rows, fmt.Sprintf("exec-%04d", i))
+	}
+	columns := []views.TableColumn{{Title: "Execution", Percentage: 100}}
+	table := views.NewTableFromSource(testRenderState(), columns, source, 10, views.TableDisplayFull)
+	table.MultiSelect = true
+	drain(table, table.Init())
+
+	table.Update(tea.KeyPressMsg{Text: "m", Code: 'm'})
+	// Moving far down evicts the page of the marked row.
+	for range 200 {
+		_, cmd := table.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		drain(table, cmd)
+	}
+	table.Update(tea.KeyPressMsg{Text: "m", Code: 'm'})
+	if got := rowNames(table.SelectedRows()); strings.Join(got, ",") != "exec-0000,exec-0201" {
+		t.Errorf("expected both marked rows, got %v", got)
+	}
+
+	// Selecting all or inverting would mark rows that are not loaded.
+	table.Update(tea.KeyPressMsg{Code: 'a', Mod: tea.ModCtrl})
+	table.Update(tea.KeyPressMsg{Text: "*", Code: '*'})
+	if table.MarkedCount() != 2 {
+		t.Errorf("expected select all and invert to be disabled, got %d marks", table.MarkedCount())
+	}
+	if help := helpKeys(table); help["ctrl+a"] || help["*"] {
+		t.Error("expected no select all or invert help for a data source")
+	}
+}
+
+// --- Table live update tests ---
+
+func testLiveTable() *views.Table {
+	columns := []views.TableColumn{
+		{Title: "Execution", Percentage: 50},
+		{Title: "Status", Percentage: 50},
+	}
+	rows := []views.TableRow{
+		{Key: "1", Data: []string{"build", "running"}},
+		{Key: "2", Data: []string{"test", "running"}},
+		{Key: "3", Data: []string{"deploy", "pending"}},
+	}
+	return views.NewTable(testRenderState(), columns, rows, views.TableDisplayFull)
+}
+
+func TestTableUpsertPreservesSelection(t *testing.T) {
+	table := testLiveTable()
+	table.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	table.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+
+	_, cmd := table.Update(views.TableUpsertMsg{Rows: []views.TableRow{
+		{Key: "3", Data: []string{"deploy", "running"}},
+		{Key: "4", Data: []string{"notify", "pending"}},
+	}})
+	if cmd == nil {
+		t.Error("expected a command to clear highlights")
+	}
+	content := table.View().Content
+	assertOrder(t, content, "build", "test", "deploy", "notify")
+	if got := table.SelectedData(); got[0] != "deploy" || got[1] != "running" {
+		t.Errorf("expected selection to stay on the updated row, got %v", got)
+	}
+	if !table.IsHighlighted("3", 1) || table.IsHighlighted("3", 0) {
+		t.Error("expected only the changed status cell to be highlighted")
+	}
+	if !table.IsHighlighted("4", 0) {
+		t.Error("expected inserted rows to be highlighted")
+	}
+}
+
+func TestTableDeletePreservesSelectionAndFilter(t *testing.T) {
+	table := testLiveTable()
+	table.Update(tea.KeyPressMsg{Text: "/"})
+	for _, ch := range "running" {
+		table.Update(tea.KeyPressMsg{Text: string(ch)})
+	}
+	table.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	table.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	if got := table.SelectedData()[0]; got != "test" {
+		t.Fatalf("expected cursor on test, got %q", got)
+	}
+
+	table.Update(views.TableDeleteMsg{Keys: []string{"1"}})
+	if got := table.SelectedData()[0]; got != "test" {
+		t.Errorf("expected selection to follow the row after delete, got %q", got)
+	}
+	content := table.View().Content
+	if strings.Contains(content, "build") {
+		t.Error("expected deleted row to be removed")
+	}
+	if strings.Contains(content, "deploy") {
+		t.Error("expected filter to be preserved")
+	}
+
+	// Rows that start matching the filter appear without clearing it.
+	table.Update(views.TableUpsertMsg{Rows: []views.TableRow{{Key: "3", Data: []string{"deploy", "running"}}}})
+	if !strings.Contains(table.View().Content, "deploy") {
+		t.Error("expected updated row to match the active filter")
+	}
+}
+
+func TestTableHighlightExpires(t *testing.T) {
+	table := testLiveTable()
+	table.HighlightDuration = time.Millisecond
+	cmd := table.UpsertRows(views.TableRow{Key: "2", Data: []string{"test", "passed"}})
+	table.Update(cmd())
+	if table.IsHighlighted("2", 1) {
+		t.Error("expected highlight to expire")
+	}
+}
+
+func TestTableUpsertKeepsUnkeyedMarks(t *testing.T) {
+	columns := []views.TableColumn{{Title: "Name", Percentage: 100}}
+	rows := []views.TableRow{{Data: []string{"alpha"}}, {Data: []string{"beta"}}}
+	table := views.NewTable(testRenderState(), columns, rows, views.TableDisplayFull)
+	table.MultiSelect = true
+	table.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	table.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+
+	table.UpsertRows(views.TableRow{Key: "new", Data: []string{"gamma"}})
+	if got := rowNames(table.SelectedRows()); len(got) != 1 || got[0] != "beta" {
+		t.Errorf("expected the unkeyed row to stay marked, got %v", got)
+	}
+}
+
+func TestTableLiveUpdateDuringChildLoad(t *testing.T) {
+	loadFor := func(table *views.Table, name string) tea.Msg {
+		t.Helper()
+		table.LoadChildren = func(row views.TableRow) ([]views.TableRow, error) {
+			return []views.TableRow{{Data: []string{row.Data[0] + "-child"}}}, nil
+		}
+		for table.SelectedData()[0] != name {
+			table.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		}
+		_, cmd := table.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+		if cmd == nil {
+			t.Fatal("expected a load command")
+		}
+		return cmd().(tea.BatchMsg)[0]()
+	}
+	columns := []views.TableColumn{{Title: "Name", Percentage: 100}}
+	newTable := func() *views.Table {
+		return views.NewTable(testRenderState(), columns, []views.TableRow{
+			{Key: "a", Data: []string{"alpha"}},
+			{Key: "b", Data: []string{"beta"}, Lazy: true},
+			{Key: "c", Data: []string{"gamma"}, Lazy: true},
+		}, views.TableDisplayFull)
+	}
+
+	// The loading row moves up when an earlier row is deleted.
+	table := newTable()
+	msg := loadFor(table, "gamma")
+	table.DeleteRows("a")
+	table.Update(msg)
+	assertOrder(t, table.View().Content, "beta", "gamma", "gamma-child")
+	if strings.Contains(table.View().Content, "beta-child") {
+		t.Error("expected the children to stay with the row that loaded them")
+	}
+
+	// Children of a deleted row are dropped.
+	table = newTable()
+	msg = loadFor(table, "beta")
+	table.DeleteRows("b")
+	table.Update(msg)
+	if content := table.View().Content; strings.Contains(content, "-child") {
+		t.Errorf("expected the children of the deleted row to be dropped, got %q", content)
+	}
+}
+
+// --- Export tests ---
+
+func TestExportRowsFormats(t *testing.T) {
+	columns := []views.TableColumn{
+		{Title: "Name"},
+		{Title: "Size", Align: views.AlignRight, Format: views.FormatBytes},
+	}
+	rows := []views.TableRow{
+		{Data: []string{"a|b", "2048"}, Children: []views.TableRow{
+			{Data: []string{"child", "1"}},
+		}},
+		{Data: []string{"c, d", "10"}},
+	}
+
+	tests := []struct {
+		opts views.ExportOptions
+		want string
+	}{
+		{
+			opts: views.ExportOptions{Format: views.ExportCSV},
+			want: "Name,Size\na|b,2.0 KiB\n\"c, d\",10 B\n",
+		},
+		{
+			opts: views.ExportOptions{Format: views.ExportTSV, IncludeChildren: true, Raw: true},
+			want: "Name\tSize\na|b\t2048\nchild\t1\nc, d\t10\n",
+		},
+		{
+			opts: views.ExportOptions{Format: views.ExportMarkdown},
+			want: "| Name | Size |\n| --- | ---: |\n| a\\|b | 2.0 KiB |\n| c, d | 10 B |\n",
+		},
+		{
+			opts: views.ExportOptions{Format: views.ExportJSON, IncludeChildren: true, Raw: true},
+			want: `[
+  {
+    "Name": "a|b",
+    "Size": "2048",
+    "children": [
+      {
+        "Name": "child",
+        "Size": "1"
+      }
+    ]
+  },
+  {
+    "Name": "c, d",
+    "Size": "10"
+  }
+]
+`,
+		},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := views.ExportRows(&buf, columns, rows, tt.opts); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.opts.Format, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.opts.Format, tt.want, buf.String())
+		}
+	}
+
+	if _, err := views.ParseExportFormat("xml"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+	if f, err := views.ParseExportFormat(".md"); err != nil || f != views.ExportMarkdown {
+		t.Errorf("expected markdown for .md, got %q (%v)", f, err)
+	}
+}
+
+func TestTableExportPassesTheme(t *testing.T) {
+	var themed []themes.Theme
+	columns := []views.TableColumn{{Title: "Name", Percentage: 100, Format: func(value string, theme themes.Theme) string {
+		themed = append(themed, theme)
+		return theme.RenderLevel(value, themes.OutputLevelError)
+	}}}
+	render := testRenderState()
+	table := views.NewTable(render, columns, []views.TableRow{{Data: []string{"failed"}}}, views.TableDisplayFull)
+
+	var buf bytes.Buffer
+	if err := table.Export(&buf, views.ExportOptions{Format: views.ExportCSV}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "Name\nfailed\n" {
+		t.Errorf("expected styling to be removed, got %q", buf.String())
+	}
+	if len(themed) != 1 || themed[0] == nil || themed[0].ColorPalette() != render.Theme.ColorPalette() {
+		t.Errorf("expected the formatter to get the table's theme, got %v", themed)
+	}
+
+	buf.Reset()
+	err := views.ExportRows(&buf, columns, []views.TableRow{{Data: []string{"failed"}}}, views.ExportOptions{Format: views.ExportCSV})
+	if err != nil || buf.String() != "Name\nfailed\n" {
+		t.Errorf("expected a default theme without one in the options, got %q (%v)", buf.String(), err)
+	}
+}
+
+func TestTableExportFilterAndSort(t *testing.T) {
+	table := testTreeTable()
+	table.SetSort(0, views.SortDescending)
+	table.Update(tea.KeyPressMsg{Text: "/"})
+	for _, ch := range "mid-a" {
+		table.Update(tea.KeyPressMsg{Text: string(ch)})
+	}
+	table.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	var buf bytes.Buffer
+	if err := table.Export(&buf, views.ExportOptions{Format: views.ExportCSV}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "Name\nroot-a\n" {
+		t.Errorf("expected only the matching top-level row, got %q", buf.String())
+	}
+
+	buf.Reset()
+	opts := views.ExportOptions{Format: views.ExportCSV, IncludeChildren: true}
+	if err := table.Export(&buf, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "Name\nroot-a\nmid-a2\nmid-a1\n" {
+		t.Errorf("expected matching children in sorted order, got %q", buf.String())
+	}
+}
+
+func TestTableExportDataSource(t *testing.T) {
+	source := &testRowSource{}
+	for i := range 250 {
+		source.rows = append(source.rows, fmt.Sprintf("row-%03d", i))
+	}
+	columns := []views.TableColumn{{Title: "Name", Percentage: 100}}
+	table := views.NewTableFromSource(testRenderState(), columns, source, 50, views.TableDisplayFull)
+	drain(table, table.Init())
+
+	var buf bytes.Buffer
+	if err := table.Export(&buf, views.ExportOptions{Format: views.ExportTSV}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 251 || lines[250] != "row-249" {
+		t.Errorf("expected every source row to be exported, got %d lines", len(lines))
+	}
+}
+
+func TestCollectionViewExport(t *testing.T) {
+	collection := sampleTypes.NewThingList("Color",
+		&types.EntityInfo{ID: "red", Header: "Red", SubHeader: "Primary Color"},
+		&types.EntityInfo{ID: "green", Header: "Green", Desc: "Grass"},
+	)
+	view := views.NewCollectionView(testRenderState(), collection, types.CollectionFormatList, nil)
+
+	var buf bytes.Buffer
+	if err := view.Export(&buf, views.ExportOptions{Format: views.ExportCSV}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "ID,Header,SubHeader,Description\ngreen,Green,,Grass\nred,Red,Primary Color,\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestTableExportPrompt(t *testing.T) {
+	table := testMultiSelectTable()
+	path := filepath.Join(t.TempDir(), "rows.json")
+
+	table.Update(tea.KeyPressMsg{Code: 's', Mod: tea.ModCtrl})
+	if !table.CapturingInput() {
+		t.Fatal("expected the export prompt to capture input")
+	}
+	for _, ch := range path {
+		table.Update(tea.KeyPressMsg{Text: string(ch)})
+	}
+	if !strings.Contains(table.View().Content, i18n.T(i18n.MsgExportPrompt)) {
+		t.Error("expected the export prompt to be rendered")
+	}
+	_, cmd := table.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if table.CapturingInput() {
+		t.Error("expected the export prompt to close on enter")
+	}
+	if cmd == nil {
+		t.Fatal("expected an export command")
+	}
+	notice, ok := cmd().(types.NoticeMsg)
+	if !ok || notice.Level != themes.OutputLevelSuccess {
+		t.Fatalf("expected a success notice, got %#v", notice)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected the export file to be written: %v", err)
+	}
+	if !strings.Contains(string(data), `"Name": "one-a"`) {
+		t.Errorf("expected exported rows in %q", data)
+	}
+
+	table.Update(tea.KeyPressMsg{Code: 's', Mod: tea.ModCtrl})
+	for _, ch := range "csv" {
+		table.Update(tea.KeyPressMsg{Text: string(ch)})
+	}
+	_, cmd = table.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if batch, ok := cmd().(tea.BatchMsg); !ok || len(batch) != 2 {
+		t.Errorf("expected a clipboard copy and a notice, got %#v", batch)
+	}
+}
+
+func TestTableExportPromptWithoutChildren(t *testing.T) {
+	table := testMultiSelectTable()
+	path := filepath.Join(t.TempDir(), "rows.csv")
+
+	table.Update(tea.KeyPressMsg{Code: 's', Mod: tea.ModCtrl})
+	if !strings.Contains(table.View().Content, i18n.T(i18n.MsgExportChildren)) {
+		t.Error("expected the prompt to offer leaving out child rows")
+	}
+	table.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	if !strings.Contains(table.View().Content, i18n.T(i18n.MsgExportNoChildren)) {
+		t.Error("expected tab to toggle child rows off")
+	}
+	for _, ch := range path {
+		table.Update(tea.KeyPressMsg{Text: string(ch)})
+	}
+	_, cmd := table.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected an export command")
+	}
+	cmd()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected the export file to be written: %v", err)
+	}
+	if strings.Contains(string(data), "one-a") || !strings.Contains(string(data), "three") {
+		t.Errorf("expected only top-level rows in %q", data)
+	}
+}
+
+// --- HelpBindings tests ---
+
+func TestHelpBindingsNilViews(t *testing.T) {
+	state := testRenderState()
+	nilViews := map[string]tuikit.View{
+		"loading": views.NewLoadingView("test", state.Theme),
+		"error":   views.NewErrorView(errors.New("err"), state.Theme),
+		"frame":   views.NewFrameView(&sampleTypes.Echo{Content: "x"}),
+	}
+	for name, v := range nilViews {
+		t.Run(name, func(t *testing.T) {
+			if bindings := v.HelpBindings(); bindings != nil {
+				t.Errorf("expected nil HelpBindings, got %v", bindings)
+			}
+		})
+	}
+}
+
+func helpKeys(v tuikit.View) map[string]bool {
+	m := make(map[string]bool)
+	for _, b := range v.HelpBindings() {
+		m[b.Key] = true
+	}
+	return m
+}
+
+func TestHelpBindingsTable(t *testing.T) {
+	state := testRenderState()
+	cols := []views.TableColumn{{Title: "X", Percentage: 100}}
+	rows := []views.TableRow{{Data: []string{"a"}}}
+	table := views.NewTable(state, cols, rows, views.TableDisplayFull)
+	for _, key := range []string{"↑/↓/j/k", "enter", "space/tab", "/"} {
+		if !helpKeys(table)[key] {
+			t.Errorf("missing key %q in table help bindings", key)
+		}
+	}
+}
+
+func TestHelpBindingsDetail(t *testing.T) {
+	state := testRenderState()
+	view := views.NewDetailView(state, "body")
+	for _, key := range []string{"j/k", "u/d", "g/G"} {
+		if !helpKeys(view)[key] {
+			t.Errorf("missing key %q in detail help bindings", key)
+		}
+	}
+}
+
+func TestHelpBindingsMarkdown(t *testing.T) {
+	state := testRenderState()
+	view := views.NewMarkdownView(state, "# hi")
+	if !helpKeys(view)["↑/↓"] {
+		t.Error("missing key ↑/↓ in markdown help bindings")
+	}
+}
+
+// --- Theme render tests ---
+
+func TestRenderHeader(t *testing.T) {
+	theme := themes.EverforestTheme()
+	header := theme.RenderHeader("MyApp", "v1.0", "Env", "prod", 80)
+	if header == "" {
+		t.Fatal("expected non-empty header")
+	}
+	if !strings.Contains(header, "MyApp") {
+		t.Error("expected app name in header")
+	}
+	if !strings.Contains(header, "help") {
+		t.Error("expected help hint in header")
+	}
+	if !strings.Contains(header, "v1.0") {
+		t.Error("expected version in header")
+	}
+}
+
+func TestRenderHeaderNoVersion(t *testing.T) {
+	theme := themes.EverforestTheme()
+	header := theme.RenderHeader("MyApp", "", "", "", 80)
+	if !strings.Contains(header, "MyApp") {
+		t.Error("expected app name in header")
+	}
+	if strings.Contains(header, "v1") {
+		t.Error("expected no version in header")
+	}
+}
+
+func TestRenderHeaderSegments(t *testing.T) {
+	theme := themes.EverforestTheme()
+	segments := []themes.HeaderSegment{
+		{Name: "ws", Label: "ws", Value: "flow", Priority: 10},
+		{Name: "tasks", Label: "tasks", Value: "3", Level: themes.OutputLevelWarning, Priority: 5},
+		{Name: "clock", ValueFunc: func() string { return "12:00" }, Priority: 1},
+	}
+	renderer, ok := theme.(themes.HeaderSegmentRenderer)
+	if !ok {
+		t.Fatal("expected the built-in themes to render header segments")
+	}
+	header := renderer.RenderHeaderSegments("MyApp", "v1.0", segments, 120)
+	for _, want := range []string{"ws:flow", "tasks:3", "12:00"} {
+		if !strings.Contains(header, want) {
+			t.Errorf("expected %q in header, got %q", want, header)
+		}
+	}
+
+	narrow := renderer.RenderHeaderSegments("MyApp", "v1.0", segments, 50)
+	if strings.Contains(narrow, "12:00") {
+		t.Error("expected lowest priority segment to be dropped on narrow width")
+	}
+	if !strings.Contains(narrow, "ws:flow") {
+		t.Errorf("expected highest priority segment to be kept, got %q", narrow)
+	}
+
+	// Themes without their own renderer get the built-in layout in their colors.
+	fallback := themes.RenderHeaderSegments(theme.ColorPalette(), "MyApp", "v1.0", segments, 120)
+	if fallback != header {
+		t.Errorf("expected the fallback to match the built-in header, got %q", fallback)
+	}
+}
+
+type segmentView struct {
+	*views.FrameView
+}
+
+func (v segmentView) HeaderSegments() []themes.HeaderSegment {
+	return []themes.HeaderSegment{{Name: "view", Label: "mode", Value: "edit"}}
+}
+
+func TestContainerHeaderSegments(t *testing.T) {
+	app := tuikit.NewApplication("tuikit-test",
+		tuikit.WithState("env", "prod"),
+		tuikit.WithHeaderSegments(themes.HeaderSegment{Name: "ws", Value: "flow"}),
+	)
+	container, err := tuikit.NewContainer(t.Context(), app, tuikit.WithInitialTermSize(100, 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	container.SetSendFunc(func(tea.Msg) {})
+	if err := container.SetView(segmentView{views.NewFrameView(&sampleTypes.Echo{})}); err != nil {
+		t.Fatal(err)
+	}
+	container.SetHeaderSegment(themes.HeaderSegment{Name: "ws", Value: "home-lab"})
+
+	names := make([]string, 0)
+	for _, seg := range container.HeaderSegments() {
+		names = append(names, seg.Name+"="+seg.Text())
+	}
+	want := "state=prod,ws=home-lab,view=edit"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("expected segments %q, got %q", want, got)
+	}
+
+	container.RemoveHeaderSegment("ws")
+	if got := len(container.HeaderSegments()); got != 2 {
+		t.Errorf("expected 2 segments after removal, got %d", got)
+	}
+}
+
+func TestContainerHeaderRenderer(t *testing.T) {
+	app := tuikit.NewApplication("tuikit-test",
+		tuikit.WithHeaderRenderer(func(_ themes.Theme, a *tuikit.Application, _ []themes.HeaderSegment, _ int) string {
+			return "custom " + a.Name + "\n"
+		}),
+	)
+	container, err := tuikit.NewContainer(t.Context(), app, tuikit.WithInitialTermSize(80, 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	container.SetSendFunc(func(tea.Msg) {})
+	if err := container.SetView(views.NewMarkdownView(container.RenderState(), "body")); err != nil {
+		t.Fatal(err)
+	}
+	if content := container.View().Content; !strings.HasPrefix(content, "custom tuikit-test") {
+		t.Errorf("expected custom header, got %q", content)
+	}
+}
+
+// legacyHeaderTheme only overrides the header of the Theme interface.
+type legacyHeaderTheme struct {
+	themes.Theme
+}
+
+func (legacyHeaderTheme) RenderHeader(appName, _, stateKey, stateVal string, _ int) string {
+	return "legacy " + appName + " " + stateKey + "=" + stateVal + "\n"
+}
+
+func TestContainerLegacyThemeHeader(t *testing.T) {
+	app := tuikit.NewApplication("tuikit-test", tuikit.WithState("env", "prod"))
+	container, err := tuikit.NewContainer(t.Context(), app,
+		tuikit.WithInitialTermSize(80, 20),
+		tuikit.WithTheme(legacyHeaderTheme{themes.EverforestTheme()}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	container.SetSendFunc(func(tea.Msg) {})
+	if err := container.SetView(views.NewMarkdownView(container.RenderState(), "body")); err != nil {
+		t.Fatal(err)
+	}
+	if content := container.View().Content; !strings.HasPrefix(content, "legacy tuikit-test env=prod") {
+		t.Errorf("expected the theme's header, got %q", content)
+	}
+
+	container.SetHeaderSegment(themes.HeaderSegment{Name: "ws", Label: "ws", Value: "flow"})
+	if content := ansi.Strip(container.View().Content); !strings.Contains(content, "ws:flow") {
+		t.Errorf("expected the segments to be rendered once added, got %q", content)
+	}
+}
+
+func TestRenderHelpPopup(t *testing.T) {
+	theme := themes.EverforestTheme()
+	keys := []themes.HelpKey{
+		{Key: "q", Desc: "quit"},
+		{Key: "enter", Desc: "select"},
+	}
+	out := theme.RenderHelpPopup(keys, 80, 40)
+	if out == "" {
+		t.Fatal("expected non-empty popup")
+	}
+}
+
+func TestRenderHelpPopupEmpty(t *testing.T) {
+	theme := themes.EverforestTheme()
+	out := theme.RenderHelpPopup(nil, 80, 40)
+	if out != "" {
+		t.Fatalf("expected empty string for nil keys, got %q", out)
+	}
+}
+
+func TestRenderToast(t *testing.T) {
+	theme := themes.EverforestTheme()
+	levels := []themes.OutputLevel{
+		themes.OutputLevelSuccess,
+		themes.OutputLevelWarning,
+		themes.OutputLevelError,
+		themes.OutputLevelInfo,
+		themes.OutputLevelNotice,
+	}
+	for _, lvl := range levels {
+		out := theme.RenderToast("test message", lvl, 80)
+		if out == "" {
+			t.Errorf("expected non-empty toast for level %s", lvl)
+		}
+	}
+}
+
+// --- Localization tests ---
+
+func TestApplicationLocale(t *testing.T) {
+	t.Cleanup(func() { i18n.SetLocale(i18n.DefaultLocale) })
+	app := tuikit.NewApplication("tuikit-test",
+		tuikit.WithTranslations("es", map[i18n.MessageKey]string{
+			i18n.MsgLoading:   "cargando...",
+			i18n.MsgNoMatches: "Sin resultados",
+		}),
+		tuikit.WithLocale("es"),
+	)
+	if app.Locale() != "es" {
+		t.Errorf("expected locale 'es', got %q", app.Locale())
+	}
+
+	state := testRenderState()
+	content := views.NewLoadingView("", state.Theme).View().Content
+	if !strings.Contains(content, "cargando...") {
+		t.Errorf("expected translated loading message, got %q", content)
+	}
+
+	table := views.NewTable(state, []views.TableColumn{{Title: "Item", Percentage: 100}},
+		[]views.TableRow{{Data: []string{"Alpha"}}}, views.TableDisplayFull)
+	table.Update(tea.KeyPressMsg{Text: "/"})
+	table.Update(tea.KeyPressMsg{Text: "z"})
+	if content := table.View().Content; !strings.Contains(content, "Sin resultados") {
+		t.Errorf("expected translated no-match message, got %q", content)
+	}
+}
+
+// --- Library view tests ---
+
+func testLibrary() *views.Library {
+	state := testRenderState()
+	page0 := views.LibraryPage{
+		Title: "Categories",
+		Factory: func(render *types.RenderState, _ []views.PageSelection) (tea.Model, []types.KeyCallback) {
+			cols := []views.TableColumn{{Title: "Name", Percentage: 50}, {Title: "Count", Percentage: 50}}
+			rows := []views.TableRow{
+				{Data: []string{"Alpha", "3"}},
+				{Data: []string{"Beta", "5"}},
+			}
+			return views.NewTable(render, cols, rows, views.TableDisplayFull), nil
+		},
+	}
+	page1 := views.LibraryPage{
+		Title: "Items",
+		Factory: func(render *types.RenderState, selections []views.PageSelection) (tea.Model, []types.KeyCallback) {
+			cat := selections[0].Data[0]
+			cols := []views.TableColumn{{Title: "Item", Percentage: 100}}
+			rows := []views.TableRow{
+				{Data: []string{cat + "-item-1"}},
+				{Data: []string{cat + "-item-2"}},
+			}
+			keys := []types.KeyCallback{
+				{Key: "x", Label: "action", Callback: func() error { return nil }},
+			}
+			return views.NewTable(render, cols, rows, views.TableDisplayFull), keys
+		},
+	}
+	page2 := views.LibraryPage{
+		Title: "Details",
+		Factory: func(render *types.RenderState, selections []views.PageSelection) (tea.Model, []types.KeyCallback) {
+			item := selections[1].Data[0]
+			return views.NewDetailView(render, "Detail for "+item), nil
+		},
+	}
+	return views.NewLibrary(state, page0, page1, page2)
+}
+
+func TestLibraryViewType(t *testing.T) {
+	lib := testLibrary()
+	if lib.Type() != views.LibraryViewType {
+		t.Errorf("expected type %q, got %q", views.LibraryViewType, lib.Type())
+	}
+}
+
+func TestLibraryInitialRender(t *testing.T) {
+	lib := testLibrary()
+	content := lib.View().Content
+	// Breadcrumb should show the first page title
+	if !strings.Contains(content, "Categories") {
+		t.Error("expected breadcrumb with 'Categories'")
+	}
+	// Table content from page 0
+	if !strings.Contains(content, "Alpha") {
+		t.Error("expected page 0 table row 'Alpha'")
+	}
+	if !strings.Contains(content, "Beta") {
+		t.Error("expected page 0 table row 'Beta'")
+	}
+}
+
+func TestLibraryNavigateForward(t *testing.T) {
+	lib := testLibrary()
+
+	// Navigate forward from page 0 -> page 1
+	lib.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	content := lib.View().Content
+
+	// Breadcrumb should show both pages
+	if !strings.Contains(content, "Categories") {
+		t.Error("expected breadcrumb with 'Categories'")
+	}
+	if !strings.Contains(content, "Items") {
+		t.Error("expected breadcrumb with 'Items'")
+	}
+	// Page 1 content derived from page 0 selection ("Alpha")
+	if !strings.Contains(content, "Alpha-item-1") {
+		t.Error("expected page 1 row 'Alpha-item-1'")
+	}
+}
+
+func TestLibraryNavigateBackward(t *testing.T) {
+	lib := testLibrary()
+
+	// Go to page 1
+	lib.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	// Go back to page 0
+	lib.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+
+	content := lib.View().Content
+	if !strings.Contains(content, "Alpha") {
+		t.Error("expected page 0 content after navigating back")
+	}
+	if strings.Contains(content, "Alpha-item-1") {
+		t.Error("should not show page 1 content after navigating back")
+	}
+}
+
+func TestLibraryCapturingInput(t *testing.T) {
+	lib := testLibrary()
+	ic, ok := any(lib).(tuikit.InputCapturer)
+	if !ok {
+		t.Fatal("Library should implement InputCapturer")
+	}
+
+	// On page 0, not capturing
+	if ic.CapturingInput() {
+		t.Error("should not be capturing input on page 0")
+	}
+
+	// Navigate to page 1
+	lib.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if !ic.CapturingInput() {
+		t.Error("should be capturing input on page > 0")
+	}
+
+	// Navigate back to page 0
+	lib.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if ic.CapturingInput() {
+		t.Error("should not be capturing input after returning to page 0")
+	}
+}
+
+func TestLibraryHelpBindings(t *testing.T) {
+	lib := testLibrary()
+
+	// Page 0: should have drill-down key but not go-back
+	keys := helpKeys(lib)
+	if !keys["enter/→"] {
+		t.Error("expected 'enter/→' help binding on page 0")
+	}
+	if keys["esc/<-"] {
+		t.Error("should not have 'esc/<-' on page 0")
 	}
 
 	// Navigate to page 1 (middle page): should have both
@@ -658,54 +2185,1644 @@ func TestLibraryHelpBindings(t *testing.T) {
 	if !keys["enter/→"] {
 		t.Error("expected 'enter/→' on middle page")
 	}
-	if !keys["esc/←"] {
-		t.Error("expected 'esc/←' on middle page")
+	if !keys["esc/←"] {
+		t.Error("expected 'esc/←' on middle page")
+	}
+	// Domain callback key from page 1
+	if !keys["x"] {
+		t.Error("expected domain key 'x' on page 1")
+	}
+}
+
+func TestLibraryLastPageForwardsEnter(t *testing.T) {
+	lib := testLibrary()
+
+	// Navigate to page 1, then page 2 (last page)
+	lib.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	lib.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	content := lib.View().Content
+	if !strings.Contains(content, "Detail for Alpha-item-1") {
+		t.Errorf("expected detail page content, got %q", content)
+	}
+
+	// Help should not have drill-down on last page
+	keys := helpKeys(lib)
+	if keys["enter/→"] {
+		t.Error("should not have 'enter/→' on last page")
+	}
+	if !keys["esc/←"] {
+		t.Error("expected 'esc/←' on last page")
+	}
+}
+
+func TestLibrarySubviewFilterCapture(t *testing.T) {
+	lib := testLibrary()
+	ic, ok := any(lib).(tuikit.InputCapturer)
+	if !ok {
+		t.Fatal("Library should implement InputCapturer")
+	}
+
+	// Activate table filter on page 0
+	lib.Update(tea.KeyPressMsg{Text: "/"})
+	if !ic.CapturingInput() {
+		t.Error("should be capturing input when sub-view filter is active")
+	}
+
+	// Esc should close filter, not propagate
+	lib.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if ic.CapturingInput() {
+		t.Error("should stop capturing after filter closed")
+	}
+}
+
+// --- Collection grouping tests ---
+
+func testGroupedCollection() *views.CollectionView {
+	collection := sampleTypes.NewThingList("Author",
+		&types.EntityInfo{ID: "william", Header: "William Shakespeare", SubHeader: "Playwright"},
+		&types.EntityInfo{ID: "jane", Header: "Jane Austen", SubHeader: "Novelist", Desc: "Wrote Emma"},
+		&types.EntityInfo{ID: "mary", Header: "Mary Shelley", SubHeader: "Novelist"},
+		&types.EntityInfo{ID: "anon", Header: "Anonymous"},
+	)
+	view := views.NewCollectionView(testRenderState(), collection, types.CollectionFormatList, nil)
+	view.SetGroupBy(views.GroupBySubHeader)
+	return view
+}
+
+func TestCollectionViewGroups(t *testing.T) {
+	view := testGroupedCollection()
+	content := ansi.Strip(view.View().Content)
+	assertOrder(t, content, "Novelist (2)", "Jane Austen", "Mary Shelley", "Playwright (1)", "William", "Other (1)", "Anonymous")
+	if !strings.Contains(content, "Wrote Emma") {
+		t.Errorf("expected item description, got %q", content)
+	}
+
+	// The selection follows Anonymous into its section; the first item is the
+	// Novelist section.
+	view.Update(tea.KeyPressMsg{Code: tea.KeyHome})
+	view.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	content = ansi.Strip(view.View().Content)
+	if !strings.Contains(content, "▸ Novelist (2)") || strings.Contains(content, "Jane Austen") {
+		t.Errorf("expected collapsed Novelist section, got %q", content)
+	}
+	if view.SelectedData() != nil {
+		t.Error("expected no selected data on a section")
+	}
+	var buf bytes.Buffer
+	if err := view.Export(&buf, views.ExportOptions{Format: views.ExportCSV}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "jane") {
+		t.Errorf("expected export to include collapsed items, got %q", buf.String())
+	}
+
+	view.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if content := ansi.Strip(view.View().Content); !strings.Contains(content, "Jane Austen") {
+		t.Errorf("expected expanded Novelist section, got %q", content)
+	}
+	view.Update(tea.KeyPressMsg{Text: "+", Code: '+'})
+	content = ansi.Strip(view.View().Content)
+	if strings.Contains(content, "Jane Austen") || strings.Contains(content, "William") {
+		t.Errorf("expected all sections collapsed, got %q", content)
+	}
+}
+
+func TestCollectionViewSortOrder(t *testing.T) {
+	collection := sampleTypes.NewThingList("Author",
+		&types.EntityInfo{ID: "a-twain", Header: "Mark Twain"},
+		&types.EntityInfo{ID: "b-austen", Header: "Jane Austen"},
+	)
+	view := views.NewCollectionView(testRenderState(), collection, types.CollectionFormatList, nil)
+	assertOrder(t, ansi.Strip(view.View().Content), "Mark Twain", "Jane Austen")
+
+	// Without sort orders, "o" isn't bound.
+	view.Update(tea.KeyPressMsg{Text: "o", Code: 'o'})
+	assertOrder(t, ansi.Strip(view.View().Content), "Mark Twain", "Jane Austen")
+	if helpKeys(view)["o/O"] {
+		t.Error("expected no sort help without sort orders")
+	}
+
+	view.SetSortOrders(views.DefaultCollectionSortOrders...)
+	_, cmd := view.Update(tea.KeyPressMsg{Text: "o", Code: 'o'})
+	assertOrder(t, ansi.Strip(view.View().Content), "Jane Austen", "Mark Twain")
+	if got := view.SelectedData(); len(got) == 0 || got[0] != "a-twain" {
+		t.Errorf("expected selection to follow the item, got %v", got)
+	}
+	if msg, ok := cmd().(types.NoticeMsg); !ok || msg.Text != "Sorted by name" {
+		t.Errorf("expected sort order notice, got %+v", msg)
+	}
+
+	view.Update(tea.KeyPressMsg{Text: "O", Code: 'O'})
+	assertOrder(t, ansi.Strip(view.View().Content), "Mark Twain", "Jane Austen")
+}
+
+func TestCollectionViewCallbacksOverrideKeys(t *testing.T) {
+	collection := sampleTypes.NewThingList("Author",
+		&types.EntityInfo{ID: "a-twain", Header: "Mark Twain", Desc: "Wrote Tom Sawyer"},
+		&types.EntityInfo{ID: "b-austen", Header: "Jane Austen"},
+	)
+	var pressed []string
+	var keys []types.KeyCallback
+	for _, key := range []string{"o", "d", "space"} {
+		keys = append(keys, types.KeyCallback{Key: key, Label: key, Callback: func() error {
+			pressed = append(pressed, key)
+			return nil
+		}})
+	}
+	view := views.NewCollectionView(testRenderState(), collection, types.CollectionFormatList, nil, keys...)
+	view.SetSortOrders(views.DefaultCollectionSortOrders...)
+	view.SetGroupBy(views.GroupBySubHeader)
+
+	view.Update(tea.KeyPressMsg{Text: "o", Code: 'o'})
+	view.Update(tea.KeyPressMsg{Text: "d", Code: 'd'})
+	view.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	if strings.Join(pressed, ",") != "o,d,space" {
+		t.Errorf("expected the callbacks to run, got %v", pressed)
+	}
+	content := ansi.Strip(view.View().Content)
+	assertOrder(t, content, "Mark Twain", "Jane Austen")
+	if !strings.Contains(content, "Wrote Tom Sawyer") {
+		t.Errorf("expected descriptions to stay shown, got %q", content)
+	}
+}
+
+func TestCollectionViewUngroupedKeys(t *testing.T) {
+	collection := sampleTypes.NewThingList("Author",
+		&types.EntityInfo{ID: "a-twain", Header: "Mark Twain"},
+		&types.EntityInfo{ID: "b-austen", Header: "Jane Austen"},
+	)
+	view := views.NewCollectionView(testRenderState(), collection, types.CollectionFormatList, nil)
+	if help := helpKeys(view); help["space/tab"] || help["+"] {
+		t.Errorf("expected no section keys without grouping, got %v", help)
+	}
+	view.Update(tea.KeyPressMsg{Text: "+", Code: '+'})
+	view.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	if content := ansi.Strip(view.View().Content); !strings.Contains(content, "Mark Twain") {
+		t.Errorf("expected the list to be unchanged, got %q", content)
+	}
+}
+
+// --- Entity badge tests ---
+
+func testTaggedEntities() []*types.EntityInfo {
+	return []*types.EntityInfo{
+		{ID: "emma", Header: "Emma", Desc: "A comedy of manners", Tags: []string{"classic", "romance"}, Icon: "★"},
+		{ID: "dune", Header: "Dune", Desc: "Desert planet", Tags: []string{"scifi"}, Status: themes.OutputLevelError},
+		{ID: "persuasion", Header: "Persuasion", Tags: []string{"classic"}},
+	}
+}
+
+func TestCollectionViewSelectTaggedEntity(t *testing.T) {
+	var selected []string
+	collection := sampleTypes.NewThingList("Book", testTaggedEntities()...)
+	view := views.NewCollectionView(testRenderState(), collection, types.CollectionFormatList,
+		func(id string) error {
+			selected = append(selected, id)
+			return nil
+		})
+	view.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if len(selected) != 1 || selected[0] != "dune" {
+		t.Errorf("expected the selected item's ID, got %q", selected)
+	}
+}
+
+func TestCollectionViewTagFilterUsesTags(t *testing.T) {
+	collection := sampleTypes.NewThingList("Job",
+		&types.EntityInfo{ID: "notes", Header: "Notes", Desc: "Run before #deploy"},
+		&types.EntityInfo{ID: "release", Header: "Release", Tags: []string{"deploy", "needs review"}},
+	)
+	view := views.NewCollectionView(testRenderState(), collection, types.CollectionFormatList, nil)
+
+	view.SetFilter("#deploy")
+	content := ansi.Strip(view.View().Content)
+	if !strings.Contains(content, "Release") || strings.Contains(content, "Notes") {
+		t.Errorf("expected only the tagged item, got %q", content)
+	}
+	view.SetFilter(`tag:"needs review"`)
+	if content := ansi.Strip(view.View().Content); !strings.Contains(content, "Release") {
+		t.Errorf("expected a quoted tag with a space to match, got %q", content)
+	}
+}
+
+func TestEntityInfoFilterValue(t *testing.T) {
+	info := testTaggedEntities()[0]
+	value := info.FilterValue()
+	if !strings.HasPrefix(value, info.Title()) {
+		t.Errorf("expected filter value to start with the title, got %q", value)
+	}
+	for _, want := range []string{"★ Emma", "emma", "comedy", "#classic", "#romance"} {
+		if !strings.Contains(value, want) {
+			t.Errorf("expected %q in filter value %q", want, value)
+		}
+	}
+	if !info.HasTag("Classic") || info.HasTag("scifi") {
+		t.Error("expected case-insensitive tag lookup")
+	}
+}
+
+func TestCollectionViewBadgesAndTagFilter(t *testing.T) {
+	collection := sampleTypes.NewThingList("Book", testTaggedEntities()...)
+	view := views.NewCollectionView(testRenderState(), collection, types.CollectionFormatList, nil)
+	content := ansi.Strip(view.View().Content)
+	for _, want := range []string{"★ Emma", "classic", "romance", "● error", "Desert planet"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in collection, got %q", want, content)
+		}
+	}
+
+	filter := func(query string) string {
+		view.SetFilter(query)
+		return ansi.Strip(view.View().Content)
+	}
+	content = filter("tag:classic")
+	if !strings.Contains(content, "Emma") || !strings.Contains(content, "Persuasion") || strings.Contains(content, "Dune") {
+		t.Errorf("expected only classic books, got %q", content)
+	}
+	content = filter("#classic comedy")
+	if !strings.Contains(content, "Emma") || strings.Contains(content, "Persuasion") {
+		t.Errorf("expected tag and text filters to combine, got %q", content)
+	}
+	content = filter("desert")
+	if !strings.Contains(content, "Dune") || strings.Contains(content, "Emma") {
+		t.Errorf("expected description to be filtered on, got %q", content)
+	}
+	if content := filter(""); !strings.Contains(content, "Persuasion") {
+		t.Errorf("expected the filter to be cleared, got %q", content)
+	}
+}
+
+func TestEntityTable(t *testing.T) {
+	table := views.NewTable(testRenderState(), views.EntityTableColumns(),
+		views.EntityTableRows(testTaggedEntities()), views.TableDisplayFull)
+	content := ansi.Strip(table.View().Content)
+	for _, want := range []string{"★ Emma", "classic", "● error"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in table, got %q", want, content)
+		}
+	}
+	table.Update(tea.KeyPressMsg{Text: "/"})
+	for _, ch := range "tags:scifi" {
+		table.Update(tea.KeyPressMsg{Text: string(ch)})
+	}
+	content = ansi.Strip(table.View().Content)
+	if !strings.Contains(content, "Dune") || strings.Contains(content, "Emma") {
+		t.Errorf("expected tag filter, got %q", content)
+	}
+}
+
+// --- Master-detail tests ---
+
+func testMasterDetail(loads *[]string) *views.MasterDetailView {
+	columns := []views.TableColumn{{Title: "Name", Percentage: 100}}
+	rows := []views.TableRow{{Data: []string{"alpha"}}, {Data: []string{"beta"}}}
+	table := views.NewTable(testRenderState(), columns, rows, views.TableDisplayFull)
+	view := views.NewMasterDetailView(testRenderState(), table,
+		func(selections []views.PageSelection) (views.ScreenData, error) {
+			name := selections[0].Data[0]
+			*loads = append(*loads, name)
+			return views.ScreenData{Content: name + "-details"}, nil
+		})
+	view.Debounce = time.Millisecond
+	return view
+}
+
+func TestMasterDetailPreview(t *testing.T) {
+	var loads []string
+	view := testMasterDetail(&loads)
+	if view.Type() != views.MasterDetailViewType {
+		t.Errorf("expected type %q, got %q", views.MasterDetailViewType, view.Type())
+	}
+	drain(view, view.Init())
+	content := ansi.Strip(view.View().Content)
+	if !strings.Contains(content, "alpha") || !strings.Contains(content, "alpha-details") {
+		t.Errorf("expected master rows and preview of alpha, got %q", content)
+	}
+
+	_, cmd := view.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	drain(view, cmd)
+	if content := ansi.Strip(view.View().Content); !strings.Contains(content, "beta-details") {
+		t.Errorf("expected preview of beta, got %q", content)
+	}
+	if len(loads) != 2 {
+		t.Errorf("expected one load per hovered row, got %v", loads)
+	}
+}
+
+func TestMasterDetailDebounce(t *testing.T) {
+	var loads []string
+	view := testMasterDetail(&loads)
+	drain(view, view.Init())
+
+	// Moving quickly across rows only loads the row the cursor rests on.
+	_, down := view.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	_, up := view.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+	drain(view, down)
+	drain(view, up)
+	if len(loads) != 2 || loads[1] != "alpha" {
+		t.Errorf("expected the preview of beta to be skipped, got loads %v", loads)
+	}
+}
+
+func TestMasterDetailPaneKeys(t *testing.T) {
+	var loads []string
+	view := testMasterDetail(&loads)
+	drain(view, view.Init())
+
+	view.Update(tea.KeyPressMsg{Text: "p", Code: 'p'})
+	if content := ansi.Strip(view.View().Content); strings.Contains(content, "alpha-details") {
+		t.Errorf("expected preview pane to be collapsed, got %q", content)
+	}
+	_, cmd := view.Update(tea.KeyPressMsg{Text: "p", Code: 'p'})
+	drain(view, cmd)
+	if content := ansi.Strip(view.View().Content); !strings.Contains(content, "alpha-details") {
+		t.Errorf("expected preview pane to be restored, got %q", content)
+	}
+
+	view.Update(tea.KeyPressMsg{Text: "P", Code: 'P'})
+	if view.Position() != views.PaneBottom {
+		t.Fatal("expected preview pane to move to the bottom")
+	}
+	lines := strings.Split(ansi.Strip(view.View().Content), "\n")
+	if strings.Contains(lines[0], "alpha-details") {
+		t.Error("expected preview below the master view")
+	}
+	if !helpKeys(view)["p"] || !helpKeys(view)["P"] {
+		t.Error("expected preview help bindings")
+	}
+}
+
+func TestMasterDetailSetPaneSize(t *testing.T) {
+	var loads []string
+	view := testMasterDetail(&loads)
+	drain(view, view.Init())
+	state := testRenderState()
+
+	drain(view, view.SetPaneSize(75))
+	first, _, _ := strings.Cut(ansi.Strip(view.View().Content), "\n")
+	if border := strings.Index(first, "│"); border != state.ContentWidth/4 {
+		t.Errorf("expected the pane border at column %d, got %d in %q", state.ContentWidth/4, border, first)
+	}
+	if view.PaneSize() != 75 {
+		t.Errorf("expected pane size 75, got %d", view.PaneSize())
+	}
+
+	drain(view, view.SetPosition(views.PaneBottom))
+	lines := strings.Split(ansi.Strip(view.View().Content), "\n")
+	if strings.Contains(lines[0], "alpha-details") || !strings.Contains(lines[0], "Name") {
+		t.Errorf("expected preview below the master view, got %q", lines[0])
+	}
+}
+
+func TestMasterDetailScrollsPreview(t *testing.T) {
+	columns := []views.TableColumn{{Title: "Name", Percentage: 100}}
+	table := views.NewTable(testRenderState(), columns, []views.TableRow{{Data: []string{"alpha"}}}, views.TableDisplayFull)
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("detail line %d", i+1)
+	}
+	view := views.NewMasterDetailView(testRenderState(), table,
+		func([]views.PageSelection) (views.ScreenData, error) {
+			return views.ScreenData{Content: strings.Join(lines, "\n\n")}, nil
+		})
+	view.Debounce = time.Millisecond
+	drain(view, view.Init())
+	if content := ansi.Strip(view.View().Content); !strings.Contains(content, "detail line 1 ") {
+		t.Fatalf("expected the top of the preview, got %q", content)
+	}
+
+	_, cmd := view.Update(tea.KeyPressMsg{Code: tea.KeyPgDown})
+	drain(view, cmd)
+	content := ansi.Strip(view.View().Content)
+	if strings.Contains(content, "detail line 1 ") {
+		t.Errorf("expected page down to scroll the preview, got %q", content)
+	}
+	if !strings.Contains(content, "alpha") {
+		t.Error("expected the master view to stay in place")
+	}
+}
+
+func TestCollectionViewSelection(t *testing.T) {
+	collection := sampleTypes.NewThingList("Color",
+		&types.EntityInfo{ID: "red", Header: "Red"},
+		&types.EntityInfo{ID: "green", Header: "Green"},
+	)
+	view := views.NewCollectionView(testRenderState(), collection, types.CollectionFormatList, nil)
+	// Items are sorted, so the second item is red.
+	view.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	if view.SelectedIndex() != 1 {
+		t.Errorf("expected index 1, got %d", view.SelectedIndex())
+	}
+	if got := view.SelectedData(); len(got) < 2 || got[0] != "red" || got[1] != "Red" {
+		t.Errorf("expected selected item 'red', got %v", got)
+	}
+}
+
+func TestBuildScreenMasterDetail(t *testing.T) {
+	def, err := views.LoadViewDefinition([]byte(`
+type: masterdetail
+mode: bottom
+provider: preview
+pages:
+  - type: table
+    provider: categories
+    columns:
+      - title: Name
+        percentage: 100
+    actions:
+      - key: x
+        label: run
+        action: run
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ran []views.PageSelection
+	bindings := testScreenBindings(&ran)
+	bindings.Providers["preview"] = func(selections []views.PageSelection) (views.ScreenData, error) {
+		return views.ScreenData{Content: "preview of " + selections[0].Data[0]}, nil
+	}
+	model, err := views.BuildScreen(testRenderState(), def, bindings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	view, ok := model.(*views.MasterDetailView)
+	if !ok {
+		t.Fatalf("expected master-detail view, got %T", model)
+	}
+	if view.Position() != views.PaneBottom {
+		t.Error("expected mode 'bottom' to place the pane at the bottom")
+	}
+	view.Debounce = time.Millisecond
+	drain(view, view.Init())
+	if content := ansi.Strip(view.View().Content); !strings.Contains(content, "preview of Alpha") {
+		t.Errorf("expected preview from the preview provider, got %q", content)
+	}
+
+	// The master page's actions are kept as keys of the master-detail view.
+	if !helpKeys(view)["x"] {
+		t.Error("expected the master page's action in the help bindings")
+	}
+	_, cmd := view.Update(tea.KeyPressMsg{Text: "x", Code: 'x'})
+	drain(view, cmd)
+	if len(ran) != 1 || ran[0].Data[0] != "Alpha" {
+		t.Errorf("expected the action to run with the selected row, got %v", ran)
+	}
+
+	def.Pages = nil
+	if _, err := views.BuildScreen(testRenderState(), def, bindings); err == nil {
+		t.Error("expected error for masterdetail without a master page")
+	}
+}
+
+// --- Diff view tests ---
+
+// numberedLines returns "line 1" to "line n", with the given lines replaced.
+func numberedLines(n int, replaced map[int]string) string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+		if r, ok := replaced[i+1]; ok {
+			lines[i] = r
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestDiffViewUnified(t *testing.T) {
+	view := views.NewDiffView(testRenderState(), "old", "a\nb\nc", "new", "a\nB\nc\nd", "")
+	view.SetMode(views.DiffUnified)
+	if view.Type() != views.DiffViewType {
+		t.Errorf("expected type %q, got %q", views.DiffViewType, view.Type())
+	}
+	content := ansi.Strip(view.View().Content)
+	assertOrder(t, content, "--- old", "+++ new", "+2 -1", "1 1   a", "2   - b", "  2 + B", "3 3   c", "  4 + d")
+}
+
+func TestDiffViewSideBySide(t *testing.T) {
+	view := views.NewDiffView(testRenderState(), "old", "a\nb\nc", "new", "a\nB\nc\nd", "")
+	lines := strings.Split(ansi.Strip(view.View().Content), "\n")
+	var changed string
+	for _, line := range lines {
+		if strings.Contains(line, "- b") {
+			changed = line
+		}
+	}
+	if !strings.Contains(changed, "+ B") {
+		t.Errorf("expected the changed lines side by side, got %q", lines)
+	}
+
+	view.Update(tea.KeyPressMsg{Text: "u", Code: 'u'})
+	if view.Mode() != views.DiffUnified {
+		t.Error("expected u to switch to the unified mode")
+	}
+}
+
+func TestDiffViewFolding(t *testing.T) {
+	oldText := numberedLines(30, nil)
+	newText := numberedLines(30, map[int]string{15: "changed"})
+	view := views.NewDiffView(testRenderState(), "old", oldText, "new", newText, "")
+	content := ansi.Strip(view.View().Content)
+	if !strings.Contains(content, "11 unchanged lines") || !strings.Contains(content, "12 unchanged lines") {
+		t.Errorf("expected unchanged regions to be folded, got %q", content)
+	}
+	assertOrder(t, content, "line 12", "line 14", "- line 15", "+ changed", "line 16", "line 18")
+	if strings.Contains(content, "line 11 ") {
+		t.Errorf("expected only 3 lines of context, got %q", content)
+	}
+
+	view.Update(tea.KeyPressMsg{Text: "f", Code: 'f'})
+	content = ansi.Strip(view.View().Content)
+	if strings.Contains(content, "unchanged lines") || !strings.Contains(content, "line 1 ") {
+		t.Errorf("expected unfolded diff, got %q", content)
+	}
+}
+
+func TestDiffViewHunkNavigation(t *testing.T) {
+	oldText := numberedLines(200, nil)
+	newText := numberedLines(200, map[int]string{10: "first change", 150: "second change"})
+	view := views.NewDiffView(testRenderState(), "old", oldText, "new", newText, "")
+	view.SetFolded(false, -1)
+
+	view.Update(tea.KeyPressMsg{Text: "n", Code: 'n'})
+	view.Update(tea.KeyPressMsg{Text: "n", Code: 'n'})
+	content := ansi.Strip(view.View().Content)
+	if !strings.Contains(content, "second change") || strings.Contains(content, "first change") {
+		t.Errorf("expected the second change to be shown, got %q", content)
+	}
+	view.Update(tea.KeyPressMsg{Text: "N", Code: 'N'})
+	if content := ansi.Strip(view.View().Content); !strings.Contains(content, "first change") {
+		t.Errorf("expected the first change to be shown, got %q", content)
+	}
+}
+
+func TestEntityDiffView(t *testing.T) {
+	view := views.NewEntityDiffView(testRenderState(),
+		"a", &sampleTypes.Thing{Name: "Green", Type: "Color"},
+		"b", &sampleTypes.Thing{Name: "Green", Type: "Shade"},
+		types.EntityFormatYAML,
+	)
+	view.SetMode(views.DiffUnified)
+	content := ansi.Strip(view.View().Content)
+	assertOrder(t, content, "name: Green", "- type: Color", "+ type: Shade")
+	if content == view.View().Content {
+		t.Error("expected the YAML to be syntax colored")
+	}
+}
+
+// --- Search tests ---
+
+func searchView(t *testing.T, m tea.Model, query string) {
+	t.Helper()
+	m.Update(tea.KeyPressMsg{Text: "/"})
+	for _, r := range query {
+		m.Update(tea.KeyPressMsg{Text: string(r)})
+	}
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+}
+
+func TestMarkdownViewSearch(t *testing.T) {
+	var md strings.Builder
+	md.WriteString("# Notes\n\n")
+	for i := range 100 {
+		fmt.Fprintf(&md, "line %d\n\n", i)
+	}
+	md.WriteString("the **needle** is here\n\nanother needle\n")
+	view := views.NewMarkdownView(testRenderState(), md.String())
+	view.View()
+
+	searchView(t, view, "needle")
+	content := view.View().Content
+	plain := ansi.Strip(content)
+	if !strings.Contains(plain, "the needle is here") || !strings.Contains(plain, "1/2") {
+		t.Errorf("expected the first match to be shown with a counter, got %q", plain)
+	}
+	if strings.Contains(plain, "line 5\n") {
+		t.Errorf("expected the view to scroll to the match, got %q", plain)
+	}
+	view.Update(tea.KeyPressMsg{Text: "n", Code: 'n'})
+	if plain := ansi.Strip(view.View().Content); !strings.Contains(plain, "2/2") {
+		t.Errorf("expected the second match to be current, got %q", plain)
+	}
+	view.Update(tea.KeyPressMsg{Text: "n", Code: 'n'})
+	if plain := ansi.Strip(view.View().Content); !strings.Contains(plain, "1/2") {
+		t.Errorf("expected the search to wrap around, got %q", plain)
+	}
+}
+
+func TestDetailViewSearchToggles(t *testing.T) {
+	view := views.NewDetailView(testRenderState(), "Foo foo FOO fxo")
+	view.Update(tea.KeyPressMsg{Text: "/"})
+	if !view.CapturingInput() {
+		t.Fatal("expected the search prompt to capture input")
+	}
+	for _, r := range "foo" {
+		view.Update(tea.KeyPressMsg{Text: string(r)})
+	}
+	if plain := ansi.Strip(view.View().Content); !strings.Contains(plain, "1/3") {
+		t.Errorf("expected 3 case-insensitive matches, got %q", plain)
+	}
+	view.Update(tea.KeyPressMsg{Code: 'c', Mod: tea.ModAlt})
+	if plain := ansi.Strip(view.View().Content); !strings.Contains(plain, "1/1") {
+		t.Errorf("expected 1 case-sensitive match, got %q", plain)
+	}
+
+	view.Update(tea.KeyPressMsg{Code: 'c', Mod: tea.ModAlt})
+	view.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	view.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	view.Update(tea.KeyPressMsg{Text: "."})
+	view.Update(tea.KeyPressMsg{Text: "o"})
+	if plain := ansi.Strip(view.View().Content); !strings.Contains(plain, "No matches") {
+		t.Errorf("expected no literal matches for f.o, got %q", plain)
+	}
+	view.Update(tea.KeyPressMsg{Code: 'r', Mod: tea.ModAlt})
+	if plain := ansi.Strip(view.View().Content); !strings.Contains(plain, "1/4") {
+		t.Errorf("expected 4 regex matches for f.o, got %q", plain)
+	}
+	view.Update(tea.KeyPressMsg{Text: "("})
+	if plain := ansi.Strip(view.View().Content); !strings.Contains(plain, "missing closing )") {
+		t.Errorf("expected the regex error to be shown, got %q", plain)
+	}
+
+	view.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if view.CapturingInput() {
+		t.Error("expected esc to close the search prompt")
+	}
+	if plain := ansi.Strip(view.View().Content); strings.Contains(plain, "Search:") {
+		t.Errorf("expected esc to clear the search, got %q", plain)
+	}
+}
+
+func testArchiveEntry(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	f, err := io.CreateArchiveLogFile(dir, "build")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = f.WriteString(content)
+	_ = f.Close()
+	return dir
+}
+
+func TestLogArchiveViewSearchAfterTick(t *testing.T) {
+	dir := testArchiveEntry(t, "starting\nfound the needle\ndone\n")
+	view := views.NewLogArchiveView(testRenderState(), dir, false)
+	view.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if plain := ansi.Strip(view.View().Content); !strings.Contains(plain, "starting") {
+		t.Fatalf("expected the entry to be opened, got %q", plain)
+	}
+
+	if _, cmd := view.Update(types.TickMsg(time.Now())); cmd != nil {
+		t.Fatalf("expected a tick not to quit an opened entry, got %T", cmd())
+	}
+	searchView(t, view, "needle")
+	if plain := ansi.Strip(view.View().Content); !strings.Contains(plain, "1/1") {
+		t.Errorf("expected the search to find the match, got %q", plain)
+	}
+}
+
+func TestLogArchiveViewLastEntryQuits(t *testing.T) {
+	dir := testArchiveEntry(t, "done\n")
+	view := views.NewLogArchiveView(testRenderState(), dir, true)
+	start := time.Now()
+	_, cmd := view.Update(types.TickMsg(start))
+	if cmd == nil || time.Since(start) > 100*time.Millisecond {
+		t.Fatal("expected the last entry mode to schedule a quit without blocking")
+	}
+	if _, again := view.Update(types.TickMsg(start)); again != nil {
+		t.Error("expected the quit to be scheduled once")
+	}
+}
+
+func TestSearchKeepsStyles(t *testing.T) {
+	view := views.NewEntityView(testRenderState(), &sampleTypes.Thing{Name: "Green", Type: "Color"}, types.EntityFormatYAML)
+	before := strings.Split(view.View().Content, "\n")
+	searchView(t, view, "green")
+	after := strings.Split(view.View().Content, "\n")
+	if bar := ansi.Strip(after[len(after)-1]); !strings.Contains(bar, "1/1") {
+		t.Errorf("expected a match, got %q", bar)
+	}
+	// The search bar takes the last line; the text above it is unchanged, and only the
+	// line with the match gains the highlight.
+	changed := 0
+	for i, line := range after[:len(after)-1] {
+		if ansi.Strip(line) != ansi.Strip(before[i]) {
+			t.Errorf("expected line %d to keep its text, got %q", i, ansi.Strip(line))
+		}
+		if line != before[i] {
+			changed++
+			if ansi.Strip(line) == line {
+				t.Errorf("expected line %d to stay styled, got %q", i, line)
+			}
+		}
+	}
+	if changed != 1 {
+		t.Errorf("expected only the matching line to change, got %d lines", changed)
+	}
+}
+
+// --- Markdown render cache tests ---
+
+func TestMarkdownViewRerendersOnResize(t *testing.T) {
+	state := testRenderState()
+	view := views.NewMarkdownView(state, strings.Repeat("word ", 30))
+	wide := ansi.Strip(view.View().Content)
+	if wide != ansi.Strip(view.View().Content) {
+		t.Fatal("expected the same content across frames")
+	}
+
+	narrow := *state
+	narrow.ContentWidth = 40
+	view.Update(&narrow)
+	for _, line := range strings.Split(ansi.Strip(view.View().Content), "\n") {
+		if w := ansi.StringWidth(strings.TrimRight(line, " ")); w > 40 {
+			t.Fatalf("expected the document to be wrapped at the new width, got a line of %d cells", w)
+		}
+	}
+	if wide == ansi.Strip(view.View().Content) {
+		t.Error("expected the document to be rendered again")
+	}
+}
+
+func TestEntityViewRerendersOnFormatChange(t *testing.T) {
+	view := views.NewEntityView(testRenderState(), &sampleTypes.Thing{Name: "Green", Type: "Color"}, types.EntityFormatDocument)
+	view.View()
+	view.Update(tea.KeyPressMsg{Text: "y", Code: 'y'})
+	if content := ansi.Strip(view.View().Content); !strings.Contains(content, "name: Green") {
+		t.Errorf("expected the YAML document, got %q", content)
+	}
+}
+
+// --- Markdown navigation tests ---
+
+func longMarkdown(sections ...string) string {
+	var md strings.Builder
+	for _, section := range sections {
+		fmt.Fprintf(&md, "## %s\n\n", section)
+		for i := range 60 {
+			fmt.Fprintf(&md, "%s line %d\n\n", strings.ToLower(section), i)
+		}
+	}
+	return md.String()
+}
+
+func firstLine(content string) string {
+	for _, line := range strings.Split(ansi.Strip(content), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+func TestMarkdownViewHeadingJumps(t *testing.T) {
+	view := views.NewMarkdownView(testRenderState(), longMarkdown("Intro", "Usage", "API"))
+	view.View()
+	view.Update(tea.KeyPressMsg{Text: "]", Code: ']'})
+	if line := firstLine(view.View().Content); !strings.HasSuffix(line, "Usage") {
+		t.Errorf("expected to jump to Usage, got %q", line)
+	}
+	view.Update(tea.KeyPressMsg{Text: "]", Code: ']'})
+	if line := firstLine(view.View().Content); !strings.HasSuffix(line, "API") {
+		t.Errorf("expected to jump to API, got %q", line)
+	}
+	view.Update(tea.KeyPressMsg{Text: "[", Code: '['})
+	if line := firstLine(view.View().Content); !strings.HasSuffix(line, "Usage") {
+		t.Errorf("expected to jump back to Usage, got %q", line)
+	}
+
+	view.Update(tea.KeyPressMsg{Text: "t", Code: 't'})
+	var sidebar, document strings.Builder
+	for _, line := range strings.Split(ansi.Strip(view.View().Content), "\n") {
+		left, right, _ := strings.Cut(line, "│")
+		sidebar.WriteString(left + "\n")
+		document.WriteString(right + "\n")
+	}
+	assertOrder(t, sidebar.String(), "Contents", "Intro", "Usage", "API")
+	if !strings.Contains(document.String(), "usage line 0") {
+		t.Errorf("expected the document next to the contents, got %q", document.String())
+	}
+}
+
+func TestMarkdownViewLinks(t *testing.T) {
+	dir := t.TempDir()
+	guide := filepath.Join(dir, "guide.md")
+	if err := os.WriteFile(guide, []byte("# Guide\n\nguide body\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	md := fmt.Sprintf("See [the guide](%s) or <https://example.com>.\n\n", filepath.ToSlash(guide)) +
+		longMarkdown("Intro")
+	view := views.NewMarkdownView(testRenderState(), md)
+	if content := view.View().Content; !strings.Contains(content, ansi.SetHyperlink("https://example.com")) {
+		t.Errorf("expected the URL to be emitted as a hyperlink, got %q", content)
+	}
+
+	view.Update(tea.KeyPressMsg{Text: "shift+tab", Code: tea.KeyTab, Mod: tea.ModShift})
+	_, cmd := view.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected the URL to be copied")
+	}
+	var notice string
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(types.NoticeMsg); ok {
+			notice = msg.Text
+		}
+	}
+	if !strings.Contains(notice, "https://example.com") {
+		t.Errorf("expected a notice about the copied URL, got %q", notice)
+	}
+
+	view.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	_, cmd = view.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	push, ok := cmd().(types.PushViewMsg)
+	if !ok {
+		t.Fatalf("expected the linked document to be pushed, got %T", cmd())
+	}
+	if content := ansi.Strip(push.View.View().Content); !strings.Contains(content, "guide body") {
+		t.Errorf("expected the linked document, got %q", content)
 	}
-	// Domain callback key from page 1
-	if !keys["x"] {
-		t.Error("expected domain key 'x' on page 1")
+}
+
+func TestMarkdownViewLinksBaseDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "guide.md"), []byte("# Guide\n\nguide body\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	view := views.NewMarkdownView(testRenderState(), "See [the guide](guide.md).\n")
+	view.SetBaseDir(dir)
+	view.View()
+	view.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	_, cmd := view.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	push, ok := cmd().(types.PushViewMsg)
+	if !ok {
+		t.Fatalf("expected the link to resolve against the base directory, got %#v", cmd())
+	}
+	if content := ansi.Strip(push.View.View().Content); !strings.Contains(content, "guide body") {
+		t.Errorf("expected the linked document, got %q", content)
 	}
 }
 
-func TestLibraryLastPageForwardsEnter(t *testing.T) {
-	lib := testLibrary()
+func TestContainerMarkdownLinkNavigation(t *testing.T) {
+	dir := t.TempDir()
+	for name, md := range map[string]string{
+		"one.md": "# One\n\nfirst body, see [two](two.md)\n",
+		"two.md": "# Two\n\nsecond body, see [one](one.md)\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(md), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	app := tuikit.NewApplication("tuikit-test")
+	container, err := tuikit.NewContainer(t.Context(), app, tuikit.WithInitialTermSize(100, 30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	container.SetSendFunc(func(tea.Msg) {})
+	if err := container.SetView(views.NewMarkdownViewFromFile(container.RenderState(), filepath.Join(dir, "one.md"))); err != nil {
+		t.Fatal(err)
+	}
+	follow := func() {
+		container.View()
+		container.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+		_, cmd := container.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+		if cmd == nil {
+			t.Fatal("expected the link to be followed")
+		}
+		msgs := []tea.Msg{cmd()}
+		if batch, ok := msgs[0].(tea.BatchMsg); ok {
+			msgs = msgs[:0]
+			for _, c := range batch {
+				if c != nil {
+					msgs = append(msgs, c())
+				}
+			}
+		}
+		for _, msg := range msgs {
+			if push, ok := msg.(types.PushViewMsg); ok {
+				container.Update(push)
+			}
+		}
+	}
+	shows := func(want string) {
+		t.Helper()
+		if content := ansi.Strip(container.View().Content); !strings.Contains(content, want) {
+			t.Fatalf("expected %q to be shown, got %q", want, content)
+		}
+	}
 
-	// Navigate to page 1, then page 2 (last page)
-	lib.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
-	lib.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	follow()
+	shows("second body")
+	follow()
+	shows("first body")
+	container.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	shows("second body")
+	container.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	shows("first body")
+	if container.PreviousView() != nil {
+		t.Error("expected the history to be empty")
+	}
+	if _, cmd := container.Update(tea.KeyPressMsg{Code: tea.KeyEscape}); cmd == nil {
+		t.Error("expected esc to quit at the start of the history")
+	}
+}
 
-	content := lib.View().Content
-	if !strings.Contains(content, "Detail for Alpha-item-1") {
-		t.Errorf("expected detail page content, got %q", content)
+func numberedMarkdown(replace map[int]string) string {
+	var md strings.Builder
+	for i := range 100 {
+		line, ok := replace[i]
+		if !ok {
+			line = fmt.Sprintf("line %d", i)
+		}
+		fmt.Fprintf(&md, "%s\n\n", line)
 	}
+	return md.String()
+}
 
-	// Help should not have drill-down on last page
-	keys := helpKeys(lib)
-	if keys["enter/→"] {
-		t.Error("should not have 'enter/→' on last page")
+func TestMarkdownViewFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "README.md")
+	if err := os.WriteFile(path, []byte(numberedMarkdown(nil)), 0o600); err != nil {
+		t.Fatal(err)
 	}
-	if !keys["esc/←"] {
-		t.Error("expected 'esc/←' on last page")
+	view := views.NewMarkdownViewFromFile(testRenderState(), path)
+	view.SetPollInterval(time.Millisecond)
+	poll := view.Init()
+	view.View()
+	for range 5 {
+		view.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	}
+	top := firstLine(view.View().Content)
+
+	if err := os.WriteFile(path, []byte(numberedMarkdown(map[int]string{20: "line twenty"})), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, poll = view.Update(poll())
+	content := view.View().Content
+	if !strings.Contains(ansi.Strip(content), "line twenty") {
+		t.Errorf("expected the changed file to be shown, got %q", ansi.Strip(content))
+	}
+	if line := firstLine(content); line != top {
+		t.Errorf("expected the scroll position to be kept at %q, got %q", top, line)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	_, poll = view.Update(poll())
+	content = ansi.Strip(view.View().Content)
+	if !strings.Contains(content, "Unable to read the document") || !strings.Contains(content, "line twenty") {
+		t.Errorf("expected an error banner above the last content, got %q", content)
+	}
+
+	if err := os.WriteFile(path, []byte("# Back\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	view.Update(poll())
+	content = ansi.Strip(view.View().Content)
+	if strings.Contains(content, "Unable to read") || !strings.Contains(content, "Back") {
+		t.Errorf("expected the file to be read again, got %q", content)
 	}
 }
 
-func TestLibrarySubviewFilterCapture(t *testing.T) {
-	lib := testLibrary()
-	ic, ok := any(lib).(tuikit.InputCapturer)
+// --- Code view tests ---
+
+func goSource(lines int) string {
+	var src strings.Builder
+	src.WriteString("package main\n\nfunc main() {\n")
+	for i := 4; i < lines; i++ {
+		fmt.Fprintf(&src, "\tprintln(%d)\n", i)
+	}
+	src.WriteString("}\n")
+	return src.String()
+}
+
+func TestCodeView(t *testing.T) {
+	view := views.NewCodeView(testRenderState(), "main.go", goSource(200), "go")
+	content := view.View().Content
+	plain := ansi.Strip(content)
+	if !strings.Contains(plain, "  1 │package main") || !strings.Contains(plain, "  4 │    println(4)") {
+		t.Errorf("expected numbered lines with expanded tabs, got %q", plain)
+	}
+	if content == plain {
+		t.Error("expected the code to be syntax colored")
+	}
+	if !strings.Contains(plain, "main.go") || !strings.Contains(plain, "Ln 1/200") {
+		t.Errorf("expected a status line, got %q", plain)
+	}
+
+	view.Update(tea.KeyPressMsg{Text: ":"})
+	if !view.CapturingInput() {
+		t.Fatal("expected the go-to-line prompt to capture input")
+	}
+	for _, r := range "150" {
+		view.Update(tea.KeyPressMsg{Text: string(r)})
+	}
+	view.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	plain = ansi.Strip(view.View().Content)
+	if view.CurrentLine() != 150 || !strings.Contains(plain, "Ln 150/200") || !strings.Contains(plain, "println(150)") {
+		t.Errorf("expected to go to line 150, got line %d: %q", view.CurrentLine(), plain)
+	}
+
+	view.SetHighlightRange(10, 12)
+	plain = ansi.Strip(view.View().Content)
+	if got := strings.Count(plain, "▌"); got != 3 || view.CurrentLine() != 10 {
+		t.Errorf("expected lines 10 to 12 to be highlighted at the cursor, got %d markers at line %d", got, view.CurrentLine())
+	}
+}
+
+func TestCodeViewCRLF(t *testing.T) {
+	src := strings.ReplaceAll(goSource(10), "\n", "\r\n")
+	for _, language := range []string{"go", ""} {
+		view := views.NewCodeView(testRenderState(), "main.go", src, language)
+		content := view.View().Content
+		if strings.Contains(content, "\r") {
+			t.Errorf("expected carriage returns to be dropped for %q, got %q", language, content)
+		}
+		if plain := ansi.Strip(content); !strings.Contains(plain, "Ln 1/10") {
+			t.Errorf("expected 10 lines for %q, got %q", language, plain)
+		}
+	}
+}
+
+func TestCodeViewWrap(t *testing.T) {
+	long := "x := \"" + strings.Repeat("a", 100) + "END\""
+	view := views.NewCodeView(testRenderState(), "", "package main\n\n"+long+"\n", "go")
+	if plain := ansi.Strip(view.View().Content); strings.Contains(plain, "END") {
+		t.Errorf("expected the long line to be truncated, got %q", plain)
+	}
+	view.Update(tea.KeyPressMsg{Text: "w", Code: 'w'})
+	plain := ansi.Strip(view.View().Content)
+	if !strings.Contains(plain, "END") || !strings.Contains(plain, "wrap") {
+		t.Errorf("expected the long line to be wrapped, got %q", plain)
+	}
+	for _, line := range strings.Split(plain, "\n") {
+		if w := ansi.StringWidth(line); w > 80 {
+			t.Errorf("expected rows to fit the width, got %d cells: %q", w, line)
+		}
+	}
+}
+
+// --- Stream view tests ---
+
+// flushStream waits for the stream view to be notified of new lines and shows them.
+func flushStream(view *views.StreamView, listen tea.Cmd) tea.Cmd {
+	_, next := view.Update(listen())
+	return next
+}
+
+func TestStreamViewBufferAndFollow(t *testing.T) {
+	view := views.NewStreamView(testRenderState())
+	view.SetBufferSize(50)
+	view.SetShowTimestamps(false)
+	w := view.Writer("out")
+	for i := range 100 {
+		fmt.Fprintf(w, "line %d\n", i)
+	}
+	fmt.Fprint(w, "partial")
+	listen := flushStream(view, view.Init())
+
+	content := ansi.Strip(view.View().Content)
+	if !strings.Contains(content, "out line 99") || strings.Contains(content, "line 49\n") {
+		t.Errorf("expected the last 50 lines to be kept, got %q", content)
+	}
+	if strings.Contains(content, "partial") {
+		t.Error("expected the partial line to be held")
+	}
+	if !strings.Contains(content, "following") || !strings.Contains(content, "50 lines") {
+		t.Errorf("expected the view to follow, got %q", content)
+	}
+
+	view.Update(tea.KeyPressMsg{Code: tea.KeyUp})
+	if view.Following() {
+		t.Fatal("expected scrolling up to pause following")
+	}
+	before := ansi.Strip(view.View().Content)
+	fmt.Fprint(w, " done\nline 100\n")
+	flushStream(view, listen)
+	content = ansi.Strip(view.View().Content)
+	if !strings.Contains(content, "2 new lines") {
+		t.Errorf("expected the new lines to be counted, got %q", content)
+	}
+	if firstLine(content) != firstLine(before) {
+		t.Errorf("expected the view to stay at %q, got %q", firstLine(before), firstLine(content))
+	}
+
+	view.Update(tea.KeyPressMsg{Text: "G", Code: 'G'})
+	content = ansi.Strip(view.View().Content)
+	if !view.Following() || !strings.Contains(content, "partial done") || !strings.Contains(content, "line 100") {
+		t.Errorf("expected G to resume following, got %q", content)
+	}
+}
+
+func TestStreamViewColors(t *testing.T) {
+	theme := themes.EverforestTheme()
+	view := views.NewStreamView(testRenderState())
+	view.SetShowTimestamps(false)
+	view.SetShowLabels(false)
+	view.Append("app", `level=error msg="boom"`)
+	view.Append("app", `{"level":"warn","msg":"careful"}`)
+	view.Append("app", "\x1b[31mred\x1b[0m text")
+	view.Append("app", "plain")
+	flushStream(view, view.Init())
+
+	content := view.View().Content
+	for _, want := range []string{
+		theme.RenderLevel(`level=error msg="boom"`, themes.OutputLevelError),
+		theme.RenderLevel(`{"level":"warn","msg":"careful"}`, themes.OutputLevelWarning),
+		"\x1b[31mred\x1b[0m text",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in %q", want, content)
+		}
+	}
+}
+
+func TestStreamViewReader(t *testing.T) {
+	view := views.NewStreamView(testRenderState())
+	view.AddReader("stderr", strings.NewReader("first\nsecond\n"))
+	listen := view.Init()
+	for range 3 {
+		if strings.Contains(ansi.Strip(view.View().Content), "second") {
+			break
+		}
+		listen = flushStream(view, listen)
+	}
+	content := ansi.Strip(view.View().Content)
+	assertOrder(t, content, "stderr first", "stderr second")
+}
+
+func TestStreamViewLongLines(t *testing.T) {
+	const max = 1024 * 1024
+	view := views.NewStreamView(testRenderState())
+	view.SetShowTimestamps(false)
+	view.AddReader("out", strings.NewReader(strings.Repeat("a", max+5)+"\nend\n"))
+	listen := view.Init()
+	for range 5 {
+		if strings.Contains(ansi.Strip(view.View().Content), "out end") {
+			break
+		}
+		listen = flushStream(view, listen)
+	}
+	content := ansi.Strip(view.View().Content)
+	if !strings.Contains(content, "out end") || !strings.Contains(content, "3 lines") {
+		t.Errorf("expected the long line to be split and reading to go on, got %q", content)
+	}
+	if !strings.Contains(content, "out aaaaa\n") {
+		t.Errorf("expected the rest of the long line on its own line, got %q", content)
+	}
+
+	view.Clear()
+	w := view.Writer("w")
+	fmt.Fprint(w, strings.Repeat("b", max+5))
+	flushStream(view, listen)
+	if content := ansi.Strip(view.View().Content); !strings.Contains(content, "1 lines") {
+		t.Errorf("expected the writer to emit a full line of a long partial line, got %q", content)
+	}
+}
+
+func TestStreamViewSingleListener(t *testing.T) {
+	view := views.NewStreamView(testRenderState())
+	view.SetShowTimestamps(false)
+	listen := view.Init()
+	if view.Init() != nil {
+		t.Fatal("expected Init not to start a second listener")
+	}
+
+	// The update is dropped, as when another view is shown.
+	view.Append("out", "missed")
+	listen()
+	view.Append("out", "later")
+
+	listen = view.Init()
+	if listen == nil {
+		t.Fatal("expected Init to listen again once the listener has returned")
+	}
+	flushStream(view, listen)
+	content := ansi.Strip(view.View().Content)
+	assertOrder(t, content, "out missed", "out later")
+}
+
+func TestStreamViewClose(t *testing.T) {
+	view := views.NewStreamView(testRenderState())
+	listen := view.Init()
+	done := make(chan tea.Msg)
+	go func() { done <- listen() }()
+
+	if err := view.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case msg := <-done:
+		if _, next := view.Update(msg); next != nil {
+			t.Error("expected a closed view not to listen again")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Close to unblock the listener")
+	}
+	if view.Init() == nil {
+		t.Error("expected Init to listen again")
+	}
+}
+
+// --- Run view tests ---
+
+// runView drives a run view like the program would, until the condition holds.
+type runView struct {
+	t    *testing.T
+	view *views.RunView
+	msgs chan tea.Msg
+}
+
+func newRunView(t *testing.T, view *views.RunView) *runView {
+	r := &runView{t: t, view: view, msgs: make(chan tea.Msg, 64)}
+	r.exec(view.Init())
+	return r
+}
+
+func (r *runView) exec(cmd tea.Cmd) {
+	if cmd != nil {
+		go func() { r.msgs <- cmd() }()
+	}
+}
+
+func (r *runView) until(cond func() bool) {
+	r.t.Helper()
+	deadline := time.After(10 * time.Second)
+	for !cond() {
+		select {
+		case msg := <-r.msgs:
+			if batch, ok := msg.(tea.BatchMsg); ok {
+				for _, cmd := range batch {
+					r.exec(cmd)
+				}
+				continue
+			}
+			_, cmd := r.view.Update(msg)
+			r.exec(cmd)
+		case <-deadline:
+			r.t.Fatalf("timed out, showing %q", ansi.Strip(r.view.View().Content))
+		}
+	}
+}
+
+// away drops the messages sent to the view for a while, as the container does while
+// another view is shown, then shows the view again.
+func (r *runView) away(d time.Duration) {
+	deadline := time.After(d)
+	for {
+		select {
+		case msg := <-r.msgs:
+			if batch, ok := msg.(tea.BatchMsg); ok {
+				for _, cmd := range batch {
+					r.exec(cmd)
+				}
+			}
+		case <-deadline:
+			r.exec(r.view.Init())
+			return
+		}
+	}
+}
+
+func TestRunViewSerial(t *testing.T) {
+	dir := t.TempDir()
+	view := views.NewRunView(testRenderState(), views.RunSerial,
+		exec.Command("sh", "-c", "echo one; echo oops >&2; exit 3"),
+		exec.Command("sh", "-c", "echo two"),
+	)
+	view.SetArchive(dir, "build")
+	var results []views.RunResult
+	view.SetOnDone(func(r []views.RunResult) { results = r })
+
+	run := newRunView(t, view)
+	run.until(func() bool { return results != nil })
+	if results[0].Status != views.RunFailed || results[0].ExitCode != 3 || results[0].Err == nil {
+		t.Errorf("expected the first command to fail with exit code 3, got %+v", results[0])
+	}
+	if results[1].Status != views.RunSkipped || results[1].Name != "sh#2" {
+		t.Errorf("expected the second command to be skipped, got %+v", results[1])
+	}
+	content := ansi.Strip(view.View().Content)
+	for _, want := range []string{"✘ sh    failed", "exit 3", "– sh#2  skipped", "sh one", "sh oops"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in %q", want, content)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 || !strings.HasPrefix(entries[0].Name(), "build__") {
+		t.Fatalf("expected an archive entry, got %v, %v", entries, err)
+	}
+	archived, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertOrder(t, string(archived), "sh | $ sh -c", "sh | one", "sh | failed (exit 3)")
+
+	results = nil
+	run.exec(view.Rerun())
+	run.until(func() bool { return results != nil })
+	if results[0].Status != views.RunFailed || strings.Count(ansi.Strip(view.View().Content), "sh one") != 1 {
+		t.Errorf("expected the rerun to replace the output, got %q", ansi.Strip(view.View().Content))
+	}
+}
+
+func TestRunViewCancel(t *testing.T) {
+	view := views.NewRunView(testRenderState(), views.RunParallel,
+		exec.Command("sh", "-c", "trap '' INT; echo ready; exec sleep 10"),
+		exec.Command("sh", "-c", "echo fast"),
+	)
+	view.SetKillDelay(100 * time.Millisecond)
+	var results []views.RunResult
+	view.SetOnDone(func(r []views.RunResult) { results = r })
+
+	run := newRunView(t, view)
+	run.until(func() bool {
+		content := ansi.Strip(view.View().Content)
+		return strings.Contains(content, "ready") && strings.Contains(content, "done")
+	})
+	if !view.Running() {
+		t.Fatal("expected the first command to be running")
+	}
+	_, cmd := view.Update(tea.KeyPressMsg{Text: "c", Code: 'c'})
+	run.exec(cmd)
+	run.until(func() bool { return results != nil })
+
+	if results[0].Status != views.RunCanceled || results[0].ExitCode != -1 || results[0].Duration > 5*time.Second {
+		t.Errorf("expected the ignored interrupt to be followed by a kill, got %+v", results[0])
+	}
+	if results[1].Status != views.RunSucceeded || results[1].ExitCode != 0 {
+		t.Errorf("expected the second command to succeed, got %+v", results[1])
+	}
+	if content := ansi.Strip(view.View().Content); !strings.Contains(content, "canceled") {
+		t.Errorf("expected the canceled status, got %q", content)
+	}
+}
+
+func TestRunViewCancelReachesChildren(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "beats")
+	view := views.NewRunView(testRenderState(), views.RunSerial, exec.Command("sh", "-c",
+		`sh -c 'while true; do echo x >> "$0"; sleep 0.05; done' "$0"; echo after`, file))
+	view.SetKillDelay(100 * time.Millisecond)
+	var results []views.RunResult
+	view.SetOnDone(func(r []views.RunResult) { results = r })
+
+	run := newRunView(t, view)
+	run.until(func() bool {
+		_, err := os.Stat(file)
+		return err == nil
+	})
+	_, cmd := view.Update(tea.KeyPressMsg{Text: "c", Code: 'c'})
+	run.exec(cmd)
+	run.until(func() bool { return results != nil })
+
+	before, _ := os.ReadFile(file)
+	time.Sleep(300 * time.Millisecond)
+	if after, _ := os.ReadFile(file); len(after) != len(before) {
+		t.Error("expected canceling to stop the processes started by the command")
+	}
+}
+
+func TestRunViewNavigateAway(t *testing.T) {
+	view := views.NewRunView(testRenderState(), views.RunSerial,
+		exec.Command("sh", "-c", "echo one; sleep 0.2; echo two"),
+		exec.Command("sh", "-c", "echo three"),
+	)
+	var results []views.RunResult
+	view.SetOnDone(func(r []views.RunResult) { results = r })
+
+	run := newRunView(t, view)
+	run.until(func() bool { return strings.Contains(ansi.Strip(view.View().Content), "sh one") })
+	run.away(time.Second)
+	run.until(func() bool { return results != nil })
+	if results[0].Status != views.RunSucceeded || results[1].Status != views.RunSucceeded {
+		t.Errorf("expected both commands to succeed, got %+v", results)
+	}
+	content := ansi.Strip(view.View().Content)
+	assertOrder(t, content, "sh   one", "sh   two", "sh#2 three")
+}
+
+func TestContainerClosesRunView(t *testing.T) {
+	view := views.NewRunView(testRenderState(), views.RunSerial, exec.Command("sh", "-c", "exec sleep 10"))
+	var results []views.RunResult
+	view.SetOnDone(func(r []views.RunResult) { results = r })
+
+	app := tuikit.NewApplication("tuikit-test")
+	container, err := tuikit.NewContainer(t.Context(), app, tuikit.WithInitialTermSize(100, 30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := make(chan tea.Msg, 16)
+	var run func(cmd tea.Cmd)
+	run = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		go func() {
+			msg := cmd()
+			if batch, ok := msg.(tea.BatchMsg); ok {
+				for _, c := range batch {
+					run(c)
+				}
+				return
+			}
+			msgs <- msg
+		}()
+	}
+	container.SetSendFunc(func(msg tea.Msg) {
+		if cmd, ok := msg.(tea.Cmd); ok {
+			run(cmd)
+		}
+	})
+	if err := container.SetView(view); err != nil {
+		t.Fatal(err)
+	}
+	if !view.Running() {
+		t.Fatal("expected the command to be running")
+	}
+	// The run view is left running in the history.
+	if err := container.SetView(views.NewMarkdownView(container.RenderState(), "# Other")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, cmd := container.Update(tea.KeyPressMsg{Text: "q", Code: 'q'}); cmd == nil {
+		t.Fatal("expected q to quit")
+	}
+	// The container has quit; the view handles the exit of its killed process.
+	deadline := time.After(5 * time.Second)
+	for results == nil {
+		select {
+		case msg := <-msgs:
+			_, cmd := view.Update(msg)
+			run(cmd)
+		case <-deadline:
+			t.Fatal("expected quitting to kill the running process")
+		}
+	}
+	if results[0].Status != views.RunFailed || results[0].Duration > 5*time.Second {
+		t.Errorf("expected the process to be killed, got %+v", results[0])
+	}
+}
+
+// --- Declarative screen tests ---
+
+const testScreenYAML = `
+type: library
+title: Screen
+pages:
+  - type: table
+    title: Categories
+    provider: categories
+    columns:
+      - title: Name
+        percentage: 100
+  - type: table
+    title: Items
+    provider: items
+    columns:
+      - title: Item
+        percentage: 100
+    actions:
+      - key: x
+        label: run
+        action: run
+`
+
+func testScreenBindings(ran *[]views.PageSelection) *views.ScreenBindings {
+	return &views.ScreenBindings{
+		Providers: map[string]views.DataProvider{
+			"categories": func(_ []views.PageSelection) (views.ScreenData, error) {
+				return views.ScreenData{Rows: []views.TableRow{{Data: []string{"Alpha"}}, {Data: []string{"Beta"}}}}, nil
+			},
+			"items": func(selections []views.PageSelection) (views.ScreenData, error) {
+				cat := selections[0].Data[0]
+				return views.ScreenData{Rows: []views.TableRow{{Data: []string{cat + "-item-1"}}}}, nil
+			},
+		},
+		Actions: map[string]views.ActionFunc{
+			"run": func(ctx views.ActionContext) error {
+				*ran = ctx.Selections
+				return nil
+			},
+		},
+	}
+}
+
+func TestLoadViewDefinition(t *testing.T) {
+	def, err := views.LoadViewDefinition([]byte(testScreenYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if def.Type != "library" || len(def.Pages) != 2 {
+		t.Fatalf("unexpected definition: %+v", def)
+	}
+	if len(def.Pages[1].Actions) != 1 || def.Pages[1].Actions[0].Key != "x" {
+		t.Errorf("expected action 'x' on page 1, got %+v", def.Pages[1].Actions)
+	}
+
+	if _, err := views.LoadViewDefinition([]byte(`title: missing type`)); err == nil {
+		t.Error("expected error for definition without a type")
+	}
+
+	form, err := views.LoadViewDefinition([]byte(`{"type": "form", "fields": [{"key": "name", "type": "masked"}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error parsing JSON: %v", err)
+	}
+	if form.Fields[0].Type != views.PromptTypeMasked {
+		t.Errorf("expected masked field type, got %v", form.Fields[0].Type)
+	}
+}
+
+func TestBuildScreenLibrary(t *testing.T) {
+	def, err := views.LoadViewDefinition([]byte(testScreenYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ran []views.PageSelection
+	model, err := views.BuildScreen(testRenderState(), def, testScreenBindings(&ran))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lib, ok := model.(*views.Library)
 	if !ok {
-		t.Fatal("Library should implement InputCapturer")
+		t.Fatalf("expected library, got %T", model)
+	}
+	if !strings.Contains(lib.View().Content, "Alpha") {
+		t.Error("expected page 0 row 'Alpha'")
 	}
 
-	// Activate table filter on page 0
-	lib.Update(tea.KeyPressMsg{Text: "/"})
-	if !ic.CapturingInput() {
-		t.Error("should be capturing input when sub-view filter is active")
+	lib.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if !strings.Contains(lib.View().Content, "Alpha-item-1") {
+		t.Error("expected page 1 row 'Alpha-item-1'")
 	}
 
-	// Esc should close filter, not propagate
-	lib.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
-	if ic.CapturingInput() {
-		t.Error("should stop capturing after filter closed")
+	_, cmd := lib.Update(tea.KeyPressMsg{Text: "x", Code: 'x'})
+	if cmd == nil {
+		t.Fatal("expected command from action key")
+	}
+	cmd()
+	if len(ran) != 2 || ran[1].Data[0] != "Alpha-item-1" {
+		t.Errorf("expected action to receive both selections, got %+v", ran)
+	}
+}
+
+func TestBuildScreenErrors(t *testing.T) {
+	state := testRenderState()
+	if _, err := views.BuildScreen(state, views.ViewDefinition{Type: "unknown"}, nil); err == nil {
+		t.Error("expected error for unknown view type")
+	}
+	def := views.ViewDefinition{Type: "table", Provider: "missing"}
+	if _, err := views.BuildScreen(state, def, &views.ScreenBindings{}); err == nil {
+		t.Error("expected error for unbound provider")
+	}
+}
+
+func TestBuildScreenActionsBuildOnce(t *testing.T) {
+	var ran []views.PageSelection
+	bindings := testScreenBindings(&ran)
+	calls := 0
+	bindings.Providers["counted"] = func(_ []views.PageSelection) (views.ScreenData, error) {
+		calls++
+		return views.ScreenData{Rows: []views.TableRow{{Data: []string{"Alpha"}}}}, nil
+	}
+	def := views.ViewDefinition{
+		Type:     "table",
+		Title:    "Counted",
+		Provider: "counted",
+		Columns:  []views.TableColumn{{Title: "Name", Percentage: 100}},
+		Actions:  []views.ActionDefinition{{Key: "x", Action: "run"}},
+	}
+	model, err := views.BuildScreen(testRenderState(), def, bindings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := model.(*views.Library); !ok {
+		t.Fatalf("expected a library, got %T", model)
+	}
+	if calls != 1 {
+		t.Errorf("expected the provider to run once, ran %d times", calls)
+	}
+	_, cmd := model.Update(tea.KeyPressMsg{Text: "x", Code: 'x'})
+	if cmd == nil {
+		t.Fatal("expected command from action key")
+	}
+	cmd()
+	if len(ran) != 1 || ran[0].Data[0] != "Alpha" {
+		t.Errorf("expected the action to receive the selection, got %+v", ran)
+	}
+}
+
+func TestViewRegistryCustomFactory(t *testing.T) {
+	registry := views.NewViewRegistry()
+	registry.Register("banner", func(ctx views.BuildContext) (tea.Model, error) {
+		return views.NewDetailView(ctx.Render, "banner: "+ctx.Definition.Title), nil
+	})
+	model, err := registry.BuildScreen(testRenderState(), views.ViewDefinition{Type: "banner", Title: "hello"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(model.View().Content, "banner: hello") {
+		t.Errorf("expected custom view content, got %q", model.View().Content)
 	}
 }
 
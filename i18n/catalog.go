@@ -0,0 +1,138 @@
+// Package i18n provides the message catalog used for tuikit's built-in UI strings.
+//
+// The catalog ships with English messages. Applications can register translations for
+// additional locales and select the active locale (see tuikit.WithLocale and tuikit.WithTranslations).
+// Lookups fall back from a regional locale (e.g. "de-AT") to its base language ("de") and then
+// to English, so partial translations are supported.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const DefaultLocale = "en"
+
+// MessageKey identifies a translatable message in the catalog.
+type MessageKey string
+
+// Catalog holds the registered translations and the active locale.
+type Catalog struct {
+	locale       string
+	translations map[string]map[MessageKey]string
+	mu           sync.RWMutex
+}
+
+// NewCatalog creates a catalog with the default English messages registered and selected.
+func NewCatalog() *Catalog {
+	c := &Catalog{
+		locale:       DefaultLocale,
+		translations: make(map[string]map[MessageKey]string),
+	}
+	c.Register(DefaultLocale, english)
+	return c
+}
+
+// Register adds the messages for a locale. Messages are merged with any previously
+// registered messages for the same locale.
+func (c *Catalog) Register(locale string, messages map[MessageKey]string) {
+	locale = normalizeLocale(locale)
+	if locale == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.translations[locale] == nil {
+		c.translations[locale] = make(map[MessageKey]string, len(messages))
+	}
+	for k, v := range messages {
+		c.translations[locale][k] = v
+	}
+}
+
+// SetLocale selects the active locale. An empty locale resets to DefaultLocale.
+func (c *Catalog) SetLocale(locale string) {
+	locale = normalizeLocale(locale)
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.locale = locale
+}
+
+func (c *Catalog) Locale() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.locale
+}
+
+// Get returns the message for the key in the active locale. If the key is not translated,
+// the base language and then English are tried. Unknown keys are returned as-is.
+func (c *Catalog) Get(key MessageKey) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, locale := range fallbackChain(c.locale) {
+		if msg, ok := c.translations[locale][key]; ok {
+			return msg
+		}
+	}
+	return string(key)
+}
+
+// Getf formats the message for the key with the given arguments.
+func (c *Catalog) Getf(key MessageKey, args ...any) string {
+	return fmt.Sprintf(c.Get(key), args...)
+}
+
+func fallbackChain(locale string) []string {
+	chain := []string{locale}
+	if base, _, found := strings.Cut(locale, "-"); found {
+		chain = append(chain, base)
+	}
+	if locale != DefaultLocale {
+		chain = append(chain, DefaultLocale)
+	}
+	return chain
+}
+
+func normalizeLocale(locale string) string {
+	locale = strings.TrimSpace(strings.ToLower(locale))
+	locale = strings.ReplaceAll(locale, "_", "-")
+	// Drop encoding suffixes such as "en_US.UTF-8".
+	locale, _, _ = strings.Cut(locale, ".")
+	return locale
+}
+
+var defaultCatalog = NewCatalog()
+
+// Default returns the process-wide catalog used by tuikit's views and themes.
+func Default() *Catalog {
+	return defaultCatalog
+}
+
+// Register adds messages for a locale to the default catalog.
+func Register(locale string, messages map[MessageKey]string) {
+	defaultCatalog.Register(locale, messages)
+}
+
+// SetLocale selects the active locale of the default catalog.
+func SetLocale(locale string) {
+	defaultCatalog.SetLocale(locale)
+}
+
+// Locale returns the active locale of the default catalog.
+func Locale() string {
+	return defaultCatalog.Locale()
+}
+
+// T returns the message for the key from the default catalog.
+func T(key MessageKey) string {
+	return defaultCatalog.Get(key)
+}
+
+// Tf formats the message for the key from the default catalog.
+func Tf(key MessageKey, args ...any) string {
+	return defaultCatalog.Getf(key, args...)
+}
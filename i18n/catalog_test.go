@@ -0,0 +1,60 @@
+package i18n_test
+
+import (
+	"testing"
+
+	"github.com/flowexec/tuikit/i18n"
+)
+
+func TestCatalogDefaultsToEnglish(t *testing.T) {
+	c := i18n.NewCatalog()
+	if c.Locale() != i18n.DefaultLocale {
+		t.Fatalf("expected default locale %q, got %q", i18n.DefaultLocale, c.Locale())
+	}
+	if got := c.Get(i18n.MsgNoMatches); got != "No matches" {
+		t.Errorf("expected English message, got %q", got)
+	}
+}
+
+func TestCatalogFallbackChain(t *testing.T) {
+	c := i18n.NewCatalog()
+	c.Register("de", map[i18n.MessageKey]string{
+		i18n.MsgNoMatches: "Keine Treffer",
+	})
+	c.SetLocale("de_AT.UTF-8")
+
+	if got := c.Get(i18n.MsgNoMatches); got != "Keine Treffer" {
+		t.Errorf("expected base language translation, got %q", got)
+	}
+	if got := c.Get(i18n.MsgNoData); got != "no data" {
+		t.Errorf("expected English fallback for untranslated key, got %q", got)
+	}
+	if got := c.Get("unknown.key"); got != "unknown.key" {
+		t.Errorf("expected unknown key to be returned as-is, got %q", got)
+	}
+}
+
+func TestCatalogFormat(t *testing.T) {
+	c := i18n.NewCatalog()
+	c.Register("fr", map[i18n.MessageKey]string{
+		i18n.MsgMoreBelow: "↓ %d de plus",
+	})
+	c.SetLocale("fr")
+	if got := c.Getf(i18n.MsgMoreBelow, 3); got != "↓ 3 de plus" {
+		t.Errorf("unexpected formatted message %q", got)
+	}
+}
+
+func TestCatalogKeepsBaselineStrings(t *testing.T) {
+	c := i18n.NewCatalog()
+	for key, want := range map[i18n.MessageKey]string{
+		i18n.MsgNoRows:   "No data",
+		i18n.MsgNoData:   "no data",
+		i18n.MsgLoading:  "loading...",
+		i18n.MsgHelpHint: "? help",
+	} {
+		if got := c.Get(key); got != want {
+			t.Errorf("expected %q for %s, got %q", want, key, got)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package i18n
+
+// Built-in message keys. Messages containing fmt verbs are rendered with Tf.
+const (
+	MsgLoading           MessageKey = "loading"
+	MsgNoData            MessageKey = "no_data"
+	MsgNoRows            MessageKey = "table.no_data"
+	MsgNoMatches         MessageKey = "no_matches"
+	MsgUnsupportedFormat MessageKey = "unsupported_format"
+	MsgEncounteredError  MessageKey = "encountered_error"
+	MsgFilterPrompt      MessageKey = "filter.prompt"
+	MsgFilterPlaceholder MessageKey = "filter.placeholder"
+	MsgFilterSummary     MessageKey = "filter.summary"
+	MsgMoreAbove         MessageKey = "scroll.more_above"
+	MsgMoreBelow         MessageKey = "scroll.more_below"
+	MsgNoLogEntries      MessageKey = "archive.no_entries"
+	MsgEmptyLogEntry     MessageKey = "archive.empty_entry"
+	MsgHelpTitle         MessageKey = "help.title"
+	MsgHelpHint          MessageKey = "help.hint"
+	MsgSelectedCount     MessageKey = "table.selected_count"
+	MsgExportPrompt      MessageKey = "export.prompt"
+	MsgExportPlaceholder MessageKey = "export.placeholder"
+	MsgExported          MessageKey = "export.exported"
+	MsgExportCopied      MessageKey = "export.copied"
+	MsgExportChildren    MessageKey = "export.children"
+	MsgExportNoChildren  MessageKey = "export.no_children"
+	MsgColumnsTitle      MessageKey = "table.columns_title"
+	MsgColumnsHint       MessageKey = "table.columns_hint"
+	MsgSortedBy          MessageKey = "collection.sorted_by"
+	MsgUngrouped         MessageKey = "collection.ungrouped"
+	MsgFoldedLines       MessageKey = "diff.folded_lines"
+	MsgNoDifferences     MessageKey = "diff.no_differences"
+	MsgSearchPrompt      MessageKey = "search.prompt"
+	MsgSearchPlaceholder MessageKey = "search.placeholder"
+	MsgSearchMatch       MessageKey = "search.match"
+	MsgContents          MessageKey = "markdown.contents"
+	MsgLinkCopied        MessageKey = "markdown.link_copied"
+	MsgReloadFailed      MessageKey = "markdown.reload_failed"
+	MsgGotoLinePrompt    MessageKey = "code.goto_line_prompt"
+	MsgLinePosition      MessageKey = "code.line_position"
+	MsgSoftWrap          MessageKey = "code.soft_wrap"
+	MsgStreamFailed      MessageKey = "stream.failed"
+	MsgFollowing         MessageKey = "stream.following"
+	MsgPaused            MessageKey = "stream.paused"
+	MsgLineCount         MessageKey = "stream.line_count"
+	MsgRunPending        MessageKey = "run.pending"
+	MsgRunRunning        MessageKey = "run.running"
+	MsgRunSucceeded      MessageKey = "run.succeeded"
+	MsgRunFailed         MessageKey = "run.failed"
+	MsgRunCanceled       MessageKey = "run.canceled"
+	MsgRunSkipped        MessageKey = "run.skipped"
+	MsgExitCode          MessageKey = "run.exit_code"
+	MsgArchiveFailed     MessageKey = "run.archive_failed"
+
+	HelpQuit            MessageKey = "help.quit"
+	HelpBack            MessageKey = "help.back"
+	HelpToggleHelp      MessageKey = "help.toggle_help"
+	HelpNavigate        MessageKey = "help.navigate"
+	HelpSelect          MessageKey = "help.select"
+	HelpExpandCollapse  MessageKey = "help.expand_collapse"
+	HelpFilter          MessageKey = "help.filter"
+	HelpScroll          MessageKey = "help.scroll"
+	HelpHalfPage        MessageKey = "help.half_page"
+	HelpTopBottom       MessageKey = "help.top_bottom"
+	HelpList            MessageKey = "help.list"
+	HelpYAML            MessageKey = "help.yaml"
+	HelpJSON            MessageKey = "help.json"
+	HelpDocument        MessageKey = "help.document"
+	HelpDeleteSelected  MessageKey = "help.delete_selected"
+	HelpDeleteAll       MessageKey = "help.delete_all"
+	HelpDrillDown       MessageKey = "help.drill_down"
+	HelpGoBack          MessageKey = "help.go_back"
+	HelpSort            MessageKey = "help.sort"
+	HelpSortColumn      MessageKey = "help.sort_column"
+	HelpExpandAll       MessageKey = "help.expand_all"
+	HelpMark            MessageKey = "help.mark"
+	HelpExport          MessageKey = "help.export"
+	HelpScrollColumns   MessageKey = "help.scroll_columns"
+	HelpColumns         MessageKey = "help.columns"
+	HelpTogglePreview   MessageKey = "help.toggle_preview"
+	HelpPreviewPosition MessageKey = "help.preview_position"
+	HelpScrollPreview   MessageKey = "help.scroll_preview"
+	HelpSelectAll       MessageKey = "help.select_all"
+	HelpInvert          MessageKey = "help.invert_selection"
+	HelpSortOrder       MessageKey = "help.sort_order"
+	HelpDescriptions    MessageKey = "help.descriptions"
+	HelpNextChange      MessageKey = "help.next_change"
+	HelpDiffMode        MessageKey = "help.diff_mode"
+	HelpFoldUnchanged   MessageKey = "help.fold_unchanged"
+	HelpSearch          MessageKey = "help.search"
+	HelpNextMatch       MessageKey = "help.next_match"
+	HelpContents        MessageKey = "help.contents"
+	HelpNextHeading     MessageKey = "help.next_heading"
+	HelpNextLink        MessageKey = "help.next_link"
+	HelpOpenLink        MessageKey = "help.open_link"
+	HelpGotoLine        MessageKey = "help.goto_line"
+	HelpSoftWrap        MessageKey = "help.soft_wrap"
+	HelpFollow          MessageKey = "help.follow"
+	HelpTimestamps      MessageKey = "help.timestamps"
+	HelpLabels          MessageKey = "help.labels"
+	HelpCancel          MessageKey = "help.cancel"
+	HelpRerun           MessageKey = "help.rerun"
+
+	// LayoutArchiveTime is the time.Format layout used when describing log archive entries.
+	LayoutArchiveTime MessageKey = "layout.archive_time"
+)
+
+var english = map[MessageKey]string{
+	MsgLoading:           "loading...",
+	MsgNoData:            "no data",
+	MsgNoRows:            "No data",
+	MsgNoMatches:         "No matches",
+	MsgUnsupportedFormat: "unsupported format",
+	MsgEncounteredError:  "!! encountered error !!",
+	MsgFilterPrompt:      "Filter: ",
+	MsgFilterPlaceholder: "filter...",
+	MsgFilterSummary:     "Filter: %s  (/ to edit, esc to clear)",
+	MsgMoreAbove:         "↑ %d more",
+	MsgMoreBelow:         "↓ %d more",
+	MsgNoLogEntries:      "no log entries found",
+	MsgEmptyLogEntry:     "no data found in log entry",
+	MsgHelpTitle:         "Help",
+	MsgHelpHint:          "? help",
+	MsgSelectedCount:     "%d selected",
+	MsgExportPrompt:      "Export to: ",
+	MsgExportPlaceholder: "file.csv, .tsv, .json or .md - or a format name to copy",
+	MsgExported:          "Exported %d rows to %s",
+	MsgExportCopied:      "Copied %d rows to the clipboard",
+	MsgExportChildren:    "tab: with child rows",
+	MsgExportNoChildren:  "tab: without child rows",
+	MsgColumnsTitle:      "Columns",
+	MsgColumnsHint:       "space: show/hide  J/K: move  esc: close",
+	MsgSortedBy:          "Sorted by %s",
+	MsgUngrouped:         "Other",
+	MsgFoldedLines:       "%d unchanged lines",
+	MsgNoDifferences:     "no differences",
+	MsgSearchPrompt:      "Search: ",
+	MsgSearchPlaceholder: "text - alt+c: match case, alt+r: regex",
+	MsgSearchMatch:       "%d/%d",
+	MsgContents:          "Contents",
+	MsgLinkCopied:        "Copied %s to the clipboard",
+	MsgReloadFailed:      "Unable to read the document: %v",
+	MsgGotoLinePrompt:    "Go to line: ",
+	MsgLinePosition:      "Ln %d/%d",
+	MsgSoftWrap:          "wrap",
+	MsgStreamFailed:      "read failed: %v",
+	MsgFollowing:         "following",
+	MsgPaused:            "paused, %d new lines (f to follow)",
+	MsgLineCount:         "%d lines",
+	MsgRunPending:        "pending",
+	MsgRunRunning:        "running",
+	MsgRunSucceeded:      "done",
+	MsgRunFailed:         "failed",
+	MsgRunCanceled:       "canceled",
+	MsgRunSkipped:        "skipped",
+	MsgExitCode:          "exit %d",
+	MsgArchiveFailed:     "Unable to write the log archive: %v",
+
+	HelpQuit:            "quit",
+	HelpBack:            "back",
+	HelpToggleHelp:      "toggle help",
+	HelpNavigate:        "navigate",
+	HelpSelect:          "select",
+	HelpExpandCollapse:  "expand/collapse",
+	HelpFilter:          "filter",
+	HelpScroll:          "scroll",
+	HelpHalfPage:        "half-page",
+	HelpTopBottom:       "top/bottom",
+	HelpList:            "list",
+	HelpYAML:            "yaml",
+	HelpJSON:            "json",
+	HelpDocument:        "document",
+	HelpDeleteSelected:  "delete selected",
+	HelpDeleteAll:       "delete all",
+	HelpDrillDown:       "drill down",
+	HelpGoBack:          "go back",
+	HelpSort:            "sort asc/desc/off",
+	HelpSortColumn:      "next sort column",
+	HelpExpandAll:       "expand/collapse all",
+	HelpMark:            "mark row",
+	HelpExport:          "export",
+	HelpScrollColumns:   "scroll columns",
+	HelpColumns:         "choose columns",
+	HelpTogglePreview:   "toggle preview",
+	HelpPreviewPosition: "move preview",
+	HelpScrollPreview:   "scroll preview",
+	HelpSelectAll:       "select all/none",
+	HelpInvert:          "invert selection",
+	HelpSortOrder:       "sort order/reverse",
+	HelpDescriptions:    "show/hide descriptions",
+	HelpNextChange:      "next/prev change",
+	HelpDiffMode:        "unified/side-by-side",
+	HelpFoldUnchanged:   "fold unchanged lines",
+	HelpSearch:          "search",
+	HelpNextMatch:       "next/prev match",
+	HelpContents:        "table of contents",
+	HelpNextHeading:     "next/prev heading",
+	HelpNextLink:        "next/prev link",
+	HelpOpenLink:        "open link",
+	HelpGotoLine:        "go to line",
+	HelpSoftWrap:        "soft wrap",
+	HelpFollow:          "follow/pause",
+	HelpTimestamps:      "show/hide timestamps",
+	HelpLabels:          "show/hide labels",
+	HelpCancel:          "cancel (twice to kill)",
+	HelpRerun:           "rerun",
+
+	LayoutArchiveTime: "03:04PM 01/02/2006",
+}
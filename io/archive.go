@@ -9,6 +9,8 @@ import (
 	"slices"
 	"strings"
 	"time"
+
+	"github.com/flowexec/tuikit/i18n"
 )
 
 const (
@@ -26,7 +28,7 @@ func (e ArchiveEntry) Title() string {
 	return e.ID
 }
 func (e ArchiveEntry) Description() string {
-	return e.Time.Format("03:04PM 01/02/2006")
+	return e.Time.Format(i18n.T(i18n.LayoutArchiveTime))
 }
 func (e ArchiveEntry) FilterValue() string {
 	return e.Title() + " " + e.Description()
@@ -44,19 +46,31 @@ func (e ArchiveEntry) Read() (string, error) {
 }
 
 func NewArchiveLogFile(archiveDir, id string) *os.File {
+	writer, err := CreateArchiveLogFile(archiveDir, id)
+	if err != nil {
+		panic(err)
+	}
+	return writer
+}
+
+// CreateArchiveLogFile creates a log file for a new archive entry, creating the archive
+// directory when needed. Unlike NewArchiveLogFile, it returns failures as errors.
+func CreateArchiveLogFile(archiveDir, id string) (*os.File, error) {
 	if dir, err := os.Stat(archiveDir); os.IsNotExist(err) {
 		err := os.MkdirAll(archiveDir, 0750)
 		if err != nil {
-			panic(fmt.Errorf("failed to create archive directory: %w", err))
+			return nil, fmt.Errorf("failed to create archive directory: %w", err)
 		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read archive directory: %w", err)
 	} else if !dir.IsDir() {
-		panic(fmt.Errorf("archive directory is not a directory"))
+		return nil, fmt.Errorf("archive directory is not a directory")
 	}
 	writer, err := os.Create(filepath.Clean(filepath.Join(archiveDir, NewArchiveFileName(id))))
 	if err != nil {
-		panic(fmt.Errorf("failed to create archive log file: %w", err))
+		return nil, fmt.Errorf("failed to create archive log file: %w", err)
 	}
-	return writer
+	return writer, nil
 }
 
 func RotateArchive(archiveDir string) {
@@ -1,6 +1,7 @@
 package overlay
 
 import (
+	"github.com/flowexec/tuikit/i18n"
 	"github.com/flowexec/tuikit/themes"
 )
 
@@ -18,9 +19,9 @@ func NewHelpPopup(theme themes.Theme) *HelpPopup {
 	return &HelpPopup{
 		theme: theme,
 		globalKeys: []themes.HelpKey{
-			{Key: "q", Desc: "quit"},
-			{Key: "esc/bksp", Desc: "back"},
-			{Key: "?/h", Desc: "toggle help"},
+			{Key: "q", Desc: i18n.T(i18n.HelpQuit)},
+			{Key: "esc/bksp", Desc: i18n.T(i18n.HelpBack)},
+			{Key: "?/h", Desc: i18n.T(i18n.HelpToggleHelp)},
 		},
 	}
 }
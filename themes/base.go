@@ -3,6 +3,7 @@ package themes
 import (
 	_ "embed"
 	"fmt"
+	"image/color"
 	"strings"
 	"text/template"
 
@@ -11,6 +12,8 @@ import (
 	"charm.land/huh/v2"
 	"charm.land/lipgloss/v2"
 	"charm.land/log/v2"
+
+	"github.com/flowexec/tuikit/i18n"
 )
 
 const (
@@ -113,8 +116,16 @@ func (t baseTheme) RenderLevel(str string, lvl OutputLevel) string {
 }
 
 func (t baseTheme) RenderHeader(appName, version, stateKey, stateVal string, width int) string {
+	var segments []HeaderSegment
+	if stateKey != "" && stateVal != "" {
+		segments = append(segments, HeaderSegment{Name: "state", Label: stateKey, Value: stateVal})
+	}
+	return t.RenderHeaderSegments(appName, version, segments, width)
+}
+
+func (t baseTheme) RenderHeaderSegments(appName, version string, segments []HeaderSegment, width int) string {
 	if width == 0 {
-		return t.renderShortHeader(appName, version, stateKey, stateVal)
+		return t.renderShortHeader(appName, version, segments)
 	}
 
 	pad := 1 // left/right padding
@@ -128,14 +139,6 @@ func (t baseTheme) RenderHeader(appName, version, stateKey, stateVal string, wid
 		Foreground(lipgloss.Color(t.Colors.Border))
 
 	left := strings.Repeat(" ", pad) + appNameStyle.Render(appName)
-	if stateKey != "" && stateVal != "" {
-		ctxStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color(t.Colors.Secondary)).
-			Italic(true)
-		left += sepStyle.Render(" · ") +
-			ctxStyle.Render(fmt.Sprintf("%s:", stateKey)) +
-			ctxStyle.Render(stateVal)
-	}
 
 	// Right side: optional version + help hint.
 	hintStyle := lipgloss.NewStyle().
@@ -146,7 +149,17 @@ func (t baseTheme) RenderHeader(appName, version, stateKey, stateVal string, wid
 	if version != "" {
 		right = versionStyle.Render(version) + sepStyle.Render(" · ")
 	}
-	right += hintStyle.Render("? help") + strings.Repeat(" ", pad)
+	right += hintStyle.Render(i18n.T(i18n.MsgHelpHint)) + strings.Repeat(" ", pad)
+
+	// Drop the lowest priority segments until the remaining ones fit. At least one dot
+	// and its surrounding spaces are always kept between the left and right side.
+	available := width - lipgloss.Width(left) - lipgloss.Width(right) - 3
+	rendered := t.renderHeaderSegments(segments)
+	for len(segments) > 0 && lipgloss.Width(rendered) > available {
+		segments = dropLowestPriority(segments)
+		rendered = t.renderHeaderSegments(segments)
+	}
+	left += rendered
 
 	// Fill the gap with faint dots.
 	gapLen := width - lipgloss.Width(left) - lipgloss.Width(right) - 2 // 2 for spaces around dots
@@ -157,7 +170,49 @@ func (t baseTheme) RenderHeader(appName, version, stateKey, stateVal string, wid
 	return left + border + right + "\n"
 }
 
-func (t baseTheme) renderShortHeader(appName, version, ctxKey, ctxVal string) string {
+func (t baseTheme) renderHeaderSegments(segments []HeaderSegment) string {
+	sepStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(t.Colors.Border))
+	var b strings.Builder
+	for _, seg := range segments {
+		val := seg.Text()
+		if val == "" {
+			continue
+		}
+		style := lipgloss.NewStyle().Italic(true)
+		if seg.Level == "" {
+			style = style.Foreground(lipgloss.Color(t.Colors.Secondary))
+		} else {
+			style = style.Foreground(t.levelColor(seg.Level))
+		}
+		if seg.Label != "" {
+			val = fmt.Sprintf("%s:%s", seg.Label, val)
+		}
+		b.WriteString(sepStyle.Render(" · "))
+		b.WriteString(style.Render(val))
+	}
+	return b.String()
+}
+
+// dropLowestPriority removes the segment with the lowest priority. When several segments
+// share the lowest priority, the last one is removed.
+func dropLowestPriority(segments []HeaderSegment) []HeaderSegment {
+	idx := len(segments) - 1
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i].Priority < segments[idx].Priority {
+			idx = i
+		}
+	}
+	remaining := make([]HeaderSegment, 0, len(segments)-1)
+	remaining = append(remaining, segments[:idx]...)
+	return append(remaining, segments[idx+1:]...)
+}
+
+func (t baseTheme) levelColor(lvl OutputLevel) color.Color {
+	return t.Colors.LevelColor(lvl)
+}
+
+func (t baseTheme) renderShortHeader(appName, version string, segments []HeaderSegment) string {
 	headerStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(t.Colors.Primary)).
 		Bold(true)
@@ -165,8 +220,16 @@ func (t baseTheme) renderShortHeader(appName, version, ctxKey, ctxVal string) st
 	if version != "" {
 		parts += " " + version
 	}
-	if ctxKey != "" {
-		parts += fmt.Sprintf(" %s:%s", ctxKey, ctxVal)
+	for _, seg := range segments {
+		val := seg.Text()
+		switch {
+		case val == "":
+			continue
+		case seg.Label != "":
+			parts += fmt.Sprintf(" %s:%s", seg.Label, val)
+		default:
+			parts += " " + val
+		}
 	}
 	return headerStyle.Render(parts)
 }
@@ -211,7 +274,7 @@ func (t baseTheme) RenderHelpPopup(keys []HelpKey, width, height int) string {
 		Align(lipgloss.Center)
 
 	lines := make([]string, 0, len(keys)+2)
-	lines = append(lines, titleStyle.Render("Help"))
+	lines = append(lines, titleStyle.Render(i18n.T(i18n.MsgHelpTitle)))
 	lines = append(lines, "")
 	for _, k := range keys {
 		line := lipgloss.JoinHorizontal(lipgloss.Top,
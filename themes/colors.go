@@ -168,3 +168,21 @@ func (cp ColorPalette) GrayColor() color.Color {
 func (cp ColorPalette) AppNameColor() color.Color {
 	return lipgloss.Color(cp.AppName)
 }
+
+// LevelColor returns the color used for the output level. Unknown levels use the gray color.
+func (cp ColorPalette) LevelColor(lvl OutputLevel) color.Color {
+	switch lvl {
+	case OutputLevelSuccess:
+		return cp.SuccessColor()
+	case OutputLevelNotice:
+		return cp.EmphasisColor()
+	case OutputLevelInfo:
+		return cp.InfoColor()
+	case OutputLevelWarning:
+		return cp.WarningColor()
+	case OutputLevelError:
+		return cp.ErrorColor()
+	default:
+		return cp.GrayColor()
+	}
+}
@@ -41,3 +41,16 @@ type Theme interface {
 	GlamourMarkdownStyleJSON() (string, error)
 	HuhTheme() huh.Theme
 }
+
+// HeaderSegmentRenderer is implemented by themes that render the header with its
+// segments. Other themes render the header with RenderHeader while the application
+// only has its state segment, and with RenderHeaderSegments once it has others.
+type HeaderSegmentRenderer interface {
+	RenderHeaderSegments(appName, version string, segments []HeaderSegment, width int) string
+}
+
+// RenderHeaderSegments renders the header with the built-in layout, in the colors of the
+// palette.
+func RenderHeaderSegments(cp *ColorPalette, appName, version string, segments []HeaderSegment, width int) string {
+	return baseTheme{Colors: cp}.RenderHeaderSegments(appName, version, segments, width)
+}
@@ -17,6 +17,31 @@ const (
 	LogNoticeLevel = log.InfoLevel + 1
 )
 
+// HeaderSegment is a single piece of context rendered in the application header,
+// e.g. the current workspace or the number of active tasks.
+type HeaderSegment struct {
+	// Name identifies the segment so it can be updated or removed. It is not rendered.
+	Name string
+	// Label is an optional prefix rendered as "label:" before the value.
+	Label string
+	Value string
+	// ValueFunc is evaluated on every render when set, e.g. for a clock. It takes precedence over Value.
+	ValueFunc func() string
+	// Level selects the style of the segment. When empty, the default context style is used.
+	Level OutputLevel
+	// Priority decides which segments are dropped when the header is too narrow.
+	// Segments with the lowest priority are dropped first.
+	Priority int
+}
+
+// Text returns the segment's current value.
+func (s HeaderSegment) Text() string {
+	if s.ValueFunc != nil {
+		return s.ValueFunc()
+	}
+	return s.Value
+}
+
 // HelpKey represents a single keybinding with its description for display in help overlays.
 type HelpKey struct {
 	Key  string
@@ -1,6 +1,11 @@
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flowexec/tuikit/themes"
+)
 
 const (
 	EntityFormatJSON     Format = "json"
@@ -19,10 +24,21 @@ type EntityInfo struct {
 	SubHeader string
 	Desc      string
 	ID        string
+
+	// Tags are short labels shown as badges. Lists can be filtered by tag with
+	// tag:name or #name.
+	Tags []string
+	// Status is shown as a badge in the color of its output level.
+	Status themes.OutputLevel
+	// Icon is an optional glyph or emoji shown before the header.
+	Icon string
 }
 
 func (i *EntityInfo) Title() string {
 	title := i.Header
+	if i.Icon != "" {
+		title = i.Icon + " " + title
+	}
 	if i.SubHeader != "" {
 		title += fmt.Sprintf(" (%s)", i.SubHeader)
 	}
@@ -30,4 +46,24 @@ func (i *EntityInfo) Title() string {
 }
 
 func (i *EntityInfo) Description() string { return i.Desc }
-func (i *EntityInfo) FilterValue() string { return i.ID }
+
+// FilterValue returns the title followed by the ID, description and tags, so that
+// every text field can be filtered on. The title comes first so that matches can be
+// highlighted in it.
+func (i *EntityInfo) FilterValue() string {
+	values := []string{i.Title(), i.ID, i.Desc}
+	for _, tag := range i.Tags {
+		values = append(values, "#"+tag)
+	}
+	return strings.Join(values, " ")
+}
+
+// HasTag returns true if the entity has the tag, compared case-insensitively.
+func (i *EntityInfo) HasTag(tag string) bool {
+	for _, t := range i.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
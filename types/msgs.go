@@ -21,10 +21,23 @@ type TickMsg time.Time
 type SubmitMsg struct{}
 type ReplaceViewMsg struct{}
 
+// PushViewMsg asks the container to show View, which must implement the container's
+// View interface, on top of the current view. Going back returns to the current view.
+type PushViewMsg struct {
+	View tea.Model
+}
+
 type ToastDismissMsg struct {
 	ID int
 }
 
+// NoticeMsg asks the container to show a toast notification. Views return it from
+// commands to report the outcome of background work.
+type NoticeMsg struct {
+	Text  string
+	Level themes.OutputLevel
+}
+
 func Tick() tea.Msg {
 	return tea.Tick(TickTime, func(t time.Time) tea.Msg {
 		return TickMsg(t)
@@ -39,6 +52,14 @@ func ReplaceView() tea.Msg {
 	return ReplaceViewMsg{}
 }
 
+// PushView returns a command that asks the container to show v on top of the
+// current view.
+func PushView(v tea.Model) tea.Cmd {
+	return func() tea.Msg {
+		return PushViewMsg{View: v}
+	}
+}
+
 type RenderState struct {
 	Width         int
 	Height        int
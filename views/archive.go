@@ -9,10 +9,12 @@ import (
 	"time"
 
 	"charm.land/bubbles/v2/list"
+	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/muesli/reflow/wordwrap"
 
+	"github.com/flowexec/tuikit/i18n"
 	"github.com/flowexec/tuikit/io"
 	"github.com/flowexec/tuikit/themes"
 	"github.com/flowexec/tuikit/types"
@@ -25,6 +27,9 @@ type LogArchiveView struct {
 	model       *list.Model
 	items       []list.Item
 	activeEntry *io.ArchiveEntry
+	quitOnTick  bool // lastEntry mode: quit shortly after the entry is shown
+	entryView   viewport.Model
+	search      contentSearch
 	err         *ErrorView
 
 	width, height int
@@ -52,13 +57,16 @@ func NewLogArchiveView(state *types.RenderState, archiveDir string, lastEntry bo
 	model.Styles = state.Theme.ListStyles()
 
 	var lastEntryFile *io.ArchiveEntry
-	if lastEntry {
+	if lastEntry && len(entries) > 0 {
 		lastEntryFile = &entries[0]
 	}
 	return &LogArchiveView{
 		archiveDir:    archiveDir,
 		cachedEntries: entries,
 		activeEntry:   lastEntryFile,
+		quitOnTick:    lastEntryFile != nil,
+		entryView:     viewport.New(viewport.WithWidth(state.ContentWidth), viewport.WithHeight(state.ContentHeight)),
+		search:        newContentSearch(),
 		model:         &model,
 		items:         items,
 		width:         state.ContentWidth,
@@ -77,16 +85,25 @@ func (v *LogArchiveView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return v.err.Update(msg)
 	}
 	switch msg := msg.(type) {
-	case types.RenderState:
+	case *types.RenderState:
 		v.width = msg.ContentWidth
 		v.height = msg.ContentHeight
 		v.model.SetSize(v.width, v.height)
+		v.entryView.SetWidth(v.width)
 	case types.TickMsg:
-		if v.activeEntry != nil {
-			time.Sleep(time.Second)
-			return v, tea.Quit
+		if v.quitOnTick {
+			v.quitOnTick = false
+			return v, tea.Tick(time.Second, func(time.Time) tea.Msg { return tea.Quit() })
 		}
 	case tea.KeyPressMsg:
+		if v.activeEntry != nil {
+			if cmd, ok := v.search.update(msg); ok {
+				return v, cmd
+			}
+			var cmd tea.Cmd
+			v.entryView, cmd = v.entryView.Update(msg)
+			return v, cmd
+		}
 		// When the filter input is active, pass all keys through to the list.
 		if v.model.FilterState() == list.Filtering {
 			break
@@ -165,11 +182,16 @@ func (v *LogArchiveView) View() tea.View {
 			v.err = NewErrorView(err, v.styles)
 			return v.err.View()
 		} else if content == "" {
-			content = "\nno data found in log entry\n"
+			content = "\n" + i18n.T(i18n.MsgEmptyLogEntry) + "\n"
+		}
+		v.entryView.SetHeight(v.height - v.search.height())
+		v.search.render(&v.entryView, wordwrap.String("\n"+content+"\n", v.width), v.styles)
+		content = v.entryView.View()
+		if v.search.active() {
+			content += "\n" + v.search.view(v.styles, v.width)
 		}
-		content = wordwrap.String("\n"+content+"\n", v.width)
 	case len(v.items) == 0:
-		v.err = NewErrorView(errors.New("no log entries found"), v.styles)
+		v.err = NewErrorView(errors.New(i18n.T(i18n.MsgNoLogEntries)), v.styles)
 		return v.err.View()
 	default:
 		v.model.SetSize(v.width, v.height)
@@ -180,19 +202,24 @@ func (v *LogArchiveView) View() tea.View {
 }
 
 func (v *LogArchiveView) HelpBindings() []themes.HelpKey {
-	if v.err != nil || v.activeEntry != nil {
+	if v.err != nil {
 		return nil
 	}
+	if v.activeEntry != nil {
+		return append([]themes.HelpKey{
+			{Key: "↑/↓", Desc: i18n.T(i18n.HelpScroll)},
+		}, searchHelpKeys()...)
+	}
 	return []themes.HelpKey{
-		{Key: "enter", Desc: "select"},
-		{Key: "/", Desc: "filter"},
-		{Key: "d", Desc: "delete selected"},
-		{Key: "x", Desc: "delete all"},
+		{Key: "enter", Desc: i18n.T(i18n.HelpSelect)},
+		{Key: "/", Desc: i18n.T(i18n.HelpFilter)},
+		{Key: "d", Desc: i18n.T(i18n.HelpDeleteSelected)},
+		{Key: "x", Desc: i18n.T(i18n.HelpDeleteAll)},
 	}
 }
 
 func (v *LogArchiveView) CapturingInput() bool {
-	return v.model.FilterState() == list.Filtering
+	return v.model.FilterState() == list.Filtering || v.search.prompting
 }
 
 func (v *LogArchiveView) Type() string {
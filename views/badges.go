@@ -0,0 +1,168 @@
+package views
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"charm.land/bubbles/v2/list"
+	"charm.land/lipgloss/v2"
+
+	"github.com/flowexec/tuikit/themes"
+	"github.com/flowexec/tuikit/types"
+)
+
+var outputLevels = map[themes.OutputLevel]bool{
+	themes.OutputLevelSuccess: true,
+	themes.OutputLevelNotice:  true,
+	themes.OutputLevelInfo:    true,
+	themes.OutputLevelWarning: true,
+	themes.OutputLevelError:   true,
+}
+
+// renderStatusBadge renders a status as a dot and its name in the color of its level.
+func renderStatusBadge(theme themes.Theme, status themes.OutputLevel) string {
+	if status == "" {
+		return ""
+	}
+	return theme.RenderLevel("● "+string(status), status)
+}
+
+// renderTagBadges renders each tag as a badge, separated by spaces.
+func renderTagBadges(theme themes.Theme, tags []string) string {
+	cp := theme.ColorPalette()
+	style := lipgloss.NewStyle().Foreground(cp.BlackColor()).Background(cp.TertiaryColor()).Padding(0, 1)
+	badges := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			badges = append(badges, style.Render(tag))
+		}
+	}
+	return strings.Join(badges, " ")
+}
+
+// renderEntityBadges renders the status and tag badges of an entity.
+func renderEntityBadges(theme themes.Theme, info *types.EntityInfo) string {
+	badges := make([]string, 0, 2)
+	for _, badge := range []string{renderStatusBadge(theme, info.Status), renderTagBadges(theme, info.Tags)} {
+		if badge != "" {
+			badges = append(badges, badge)
+		}
+	}
+	return strings.Join(badges, " ")
+}
+
+// FormatTags formats a comma-separated list of tags as badges.
+func FormatTags(value string, theme themes.Theme) string {
+	if theme == nil {
+		return value
+	}
+	return renderTagBadges(theme, strings.Split(value, ","))
+}
+
+// FormatLevel formats an output level name, e.g. "error", as a status badge. Other
+// values are rendered unchanged.
+func FormatLevel(value string, theme themes.Theme) string {
+	lvl := themes.OutputLevel(strings.ToLower(strings.TrimSpace(value)))
+	if theme == nil || !outputLevels[lvl] {
+		return value
+	}
+	return renderStatusBadge(theme, lvl)
+}
+
+// EntityTableColumns returns the columns of a table listing entities with
+// EntityTableRows: name, status, tags and description.
+func EntityTableColumns() []TableColumn {
+	return []TableColumn{
+		{Title: "Name", Percentage: 35},
+		{Title: "Status", AutoWidth: true, Format: FormatLevel},
+		{Title: "Tags", Percentage: 25, Format: FormatTags},
+		{Title: "Description", Percentage: 40},
+	}
+}
+
+// EntityTableRows returns a row per entity for the EntityTableColumns. Tags are
+// joined with commas, so rows can be filtered by tag with tags:name.
+func EntityTableRows(items []*types.EntityInfo) []TableRow {
+	rows := make([]TableRow, 0, len(items))
+	for _, info := range items {
+		rows = append(rows, TableRow{
+			Data: []string{info.Title(), string(info.Status), strings.Join(info.Tags, ","), info.Desc},
+		})
+	}
+	return rows
+}
+
+// filterCollection returns the list filter of CollectionView for its items. Terms
+// written as tag:name or #name only keep entities with that tag; the rest of the term
+// is matched fuzzily against every text field of the items. Quote tags that contain
+// spaces, as in tag:"needs review".
+func filterCollection(items []list.Item) list.FilterFunc {
+	return func(term string, targets []string) []list.Rank {
+		var tags, text []string
+		for _, field := range filterFields(term) {
+			switch {
+			case len(field) > 4 && strings.EqualFold(field[:4], "tag:"):
+				tags = append(tags, field[4:])
+			case len(field) > 1 && field[0] == '#':
+				tags = append(tags, field[1:])
+			default:
+				text = append(text, field)
+			}
+		}
+		if len(tags) == 0 {
+			return list.DefaultFilter(term, targets)
+		}
+
+		var ranks []list.Rank
+		if len(text) > 0 {
+			ranks = list.DefaultFilter(strings.Join(text, " "), targets)
+		} else {
+			ranks = make([]list.Rank, 0, len(targets))
+			for i := range targets {
+				ranks = append(ranks, list.Rank{Index: i})
+			}
+		}
+		kept := ranks[:0]
+		for _, rank := range ranks {
+			if rank.Index >= len(items) {
+				continue
+			}
+			info, ok := items[rank.Index].(*types.EntityInfo)
+			if !ok {
+				continue
+			}
+			if !slices.ContainsFunc(tags, func(tag string) bool { return !info.HasTag(tag) }) {
+				kept = append(kept, rank)
+			}
+		}
+		return kept
+	}
+}
+
+// filterFields splits a filter term on spaces, keeping double-quoted text together
+// and dropping the quotes.
+func filterFields(term string) []string {
+	var (
+		fields []string
+		field  strings.Builder
+		quoted bool
+	)
+	for _, r := range term {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteRune(r)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
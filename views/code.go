@@ -0,0 +1,349 @@
+package views
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/flowexec/tuikit/i18n"
+	"github.com/flowexec/tuikit/themes"
+	"github.com/flowexec/tuikit/types"
+)
+
+const CodeViewType = "code"
+
+// codeTabWidth is the number of spaces tabs are expanded to.
+const codeTabWidth = 4
+
+// CodeView shows source code colored with the theme's chroma code style, with line
+// numbers and a cursor on the current line. The code is highlighted once, and only the
+// lines in view are rendered, so large files stay responsive.
+type CodeView struct {
+	render *types.RenderState
+	err    *ErrorView
+
+	title  string
+	lines  [][]codeToken
+	widths []int
+
+	cursor int // current line, 0-based
+	offset int // first line in view, 0-based
+	wrap   bool
+	// rangeStart and rangeEnd are the highlighted lines, 1-based and inclusive; zero
+	// when there is no highlighted range.
+	rangeStart, rangeEnd int
+
+	gotoPrompt textinput.Model
+	prompting  bool
+}
+
+// NewCodeView shows the code. The language is a chroma language name, file name or
+// extension; when no lexer matches it, the code is not colored.
+func NewCodeView(render *types.RenderState, title, code, language string) *CodeView {
+	code = strings.ReplaceAll(code, "\r\n", "\n")
+	code = strings.ReplaceAll(code, "\t", strings.Repeat(" ", codeTabWidth))
+	code = strings.TrimSuffix(code, "\n")
+	lines := highlightTokens(code, language, render.Theme)
+	widths := make([]int, len(lines))
+	for i, line := range lines {
+		for _, token := range line {
+			widths[i] += ansi.StringWidth(token.text)
+		}
+	}
+
+	in := textinput.New()
+	in.Prompt = i18n.T(i18n.MsgGotoLinePrompt)
+	in.CharLimit = 10
+	return &CodeView{
+		render:     render,
+		title:      title,
+		lines:      lines,
+		widths:     widths,
+		gotoPrompt: in,
+	}
+}
+
+// NewCodeViewFromFile shows the file at path, colored for its file name.
+func NewCodeViewFromFile(render *types.RenderState, path string) *CodeView {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return &CodeView{render: render, err: NewErrorView(err, render.Theme)}
+	}
+	return NewCodeView(render, path, string(content), filepath.Base(path))
+}
+
+// SetWrap wraps long lines instead of truncating them.
+func (v *CodeView) SetWrap(wrap bool) {
+	v.wrap = wrap
+	v.scrollToCursor()
+}
+
+// SetHighlightRange highlights the lines from start to end, 1-based and inclusive,
+// e.g. the location of an error, and moves the cursor to start. A zero start clears
+// the highlight.
+func (v *CodeView) SetHighlightRange(start, end int) {
+	if start <= 0 {
+		v.rangeStart, v.rangeEnd = 0, 0
+		return
+	}
+	v.rangeStart, v.rangeEnd = start, max(start, end)
+	v.GotoLine(start)
+}
+
+// GotoLine moves the cursor to the line, 1-based, and centers it in the view.
+func (v *CodeView) GotoLine(line int) {
+	v.cursor = min(max(line-1, 0), len(v.lines)-1)
+	v.offset = max(v.cursor-v.codeHeight()/2, 0)
+	v.scrollToCursor()
+}
+
+// CurrentLine returns the line of the cursor, 1-based.
+func (v *CodeView) CurrentLine() int {
+	return v.cursor + 1
+}
+
+func (v *CodeView) Init() tea.Cmd {
+	return nil
+}
+
+//nolint:gocognit
+func (v *CodeView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if v.err != nil {
+		return v.err.Update(msg)
+	}
+	switch msg := msg.(type) {
+	case *types.RenderState:
+		v.render = msg
+		v.scrollToCursor()
+	case tea.KeyPressMsg:
+		if v.prompting {
+			return v, v.updatePrompt(msg)
+		}
+		halfPage := max(v.codeHeight()/2, 1)
+		switch msg.String() {
+		case types.KeyUp, "k":
+			v.moveCursor(-1)
+		case types.KeyDown, "j":
+			v.moveCursor(1)
+		case "pgup", "u":
+			v.moveCursor(-halfPage)
+		case "pgdown", "d":
+			v.moveCursor(halfPage)
+		case "g", "home":
+			v.moveCursor(-len(v.lines))
+		case "G", "end":
+			v.moveCursor(len(v.lines))
+		case "w":
+			v.SetWrap(!v.wrap)
+		case ":":
+			v.prompting = true
+			v.gotoPrompt.SetValue("")
+			return v, v.gotoPrompt.Focus()
+		}
+	}
+	return v, nil
+}
+
+func (v *CodeView) updatePrompt(msg tea.KeyPressMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		v.closePrompt()
+		return nil
+	case types.KeyEnter:
+		line, err := strconv.Atoi(strings.TrimSpace(v.gotoPrompt.Value()))
+		v.closePrompt()
+		if err != nil {
+			return nil
+		}
+		v.GotoLine(line)
+		return nil
+	}
+	var cmd tea.Cmd
+	v.gotoPrompt, cmd = v.gotoPrompt.Update(msg)
+	return cmd
+}
+
+func (v *CodeView) closePrompt() {
+	v.prompting = false
+	v.gotoPrompt.Blur()
+}
+
+func (v *CodeView) moveCursor(delta int) {
+	v.cursor = min(max(v.cursor+delta, 0), len(v.lines)-1)
+	v.scrollToCursor()
+}
+
+// scrollToCursor scrolls the least needed to keep the cursor line in view.
+func (v *CodeView) scrollToCursor() {
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+		return
+	}
+	height := v.codeHeight()
+	rows := 0
+	for i := v.cursor; i >= v.offset; i-- {
+		rows += v.lineRows(i)
+		if rows > height {
+			v.offset = i + 1
+			return
+		}
+	}
+}
+
+// codeHeight is the number of rows showing code, below the status line.
+func (v *CodeView) codeHeight() int {
+	return max(v.render.ContentHeight-1, 1)
+}
+
+func (v *CodeView) gutterWidth() int {
+	return len(strconv.Itoa(len(v.lines))) + 3
+}
+
+func (v *CodeView) codeWidth() int {
+	return max(v.render.ContentWidth-v.gutterWidth(), 1)
+}
+
+// lineRows is the number of rows the line takes.
+func (v *CodeView) lineRows(line int) int {
+	if !v.wrap || v.widths[line] == 0 {
+		return 1
+	}
+	return (v.widths[line] + v.codeWidth() - 1) / v.codeWidth()
+}
+
+func (v *CodeView) View() tea.View {
+	if v.err != nil {
+		return v.err.View()
+	}
+	cp := v.render.Theme.ColorPalette()
+	lineBackground := codeLineHighlight(v.render.Theme)
+	numberStyle := lipgloss.NewStyle().Foreground(cp.GrayColor())
+	currentStyle := lipgloss.NewStyle().Foreground(cp.PrimaryColor()).Bold(true)
+	rangeStyle := lipgloss.NewStyle().Foreground(cp.ErrorColor()).Bold(true)
+	digits := v.gutterWidth() - 3
+	width := v.codeWidth()
+
+	rows := make([]string, 0, v.codeHeight())
+	for i := v.offset; i < len(v.lines) && len(rows) < v.codeHeight(); i++ {
+		inRange := v.rangeStart > 0 && i+1 >= v.rangeStart && i+1 <= v.rangeEnd
+		style, marker := numberStyle, " "
+		switch {
+		case inRange:
+			style, marker = rangeStyle, "▌"
+		case i == v.cursor:
+			style = currentStyle
+		}
+		var background color.Color
+		if i == v.cursor || inRange {
+			background = lineBackground
+		}
+
+		segments := splitCodeTokens(v.lines[i], width, v.wrap)
+		for n, segment := range segments {
+			number := fmt.Sprintf("%*d", digits, i+1)
+			if n > 0 {
+				number = strings.Repeat(" ", digits)
+			}
+			code := renderCodeTokens(segment, background)
+			if pad := width - codeTokensWidth(segment); background != nil && pad > 0 {
+				code += lipgloss.NewStyle().Background(background).Render(strings.Repeat(" ", pad))
+			}
+			rows = append(rows, style.Render(marker+number)+numberStyle.Render(" │")+code)
+			if len(rows) == v.codeHeight() {
+				break
+			}
+		}
+	}
+	for len(rows) < v.codeHeight() {
+		rows = append(rows, "")
+	}
+	return tea.View{Content: strings.Join(rows, "\n") + "\n" + v.renderStatus()}
+}
+
+// renderStatus renders the status line: the title and the cursor position, or the
+// go-to-line prompt.
+func (v *CodeView) renderStatus() string {
+	cp := v.render.Theme.ColorPalette()
+	width := v.render.ContentWidth
+	if v.prompting {
+		return lipgloss.NewStyle().Foreground(cp.SecondaryColor()).Width(width).Render(v.gotoPrompt.View())
+	}
+	position := i18n.Tf(i18n.MsgLinePosition, v.cursor+1, len(v.lines))
+	if v.wrap {
+		position = i18n.T(i18n.MsgSoftWrap) + "  " + position
+	}
+	title := ansi.Truncate(v.title, max(width-ansi.StringWidth(position)-2, 0), "…")
+	gap := max(width-ansi.StringWidth(title)-ansi.StringWidth(position), 1)
+	return lipgloss.NewStyle().Foreground(cp.GrayColor()).Render(title + strings.Repeat(" ", gap) + position)
+}
+
+func (v *CodeView) HelpBindings() []themes.HelpKey {
+	if v.err != nil {
+		return nil
+	}
+	return []themes.HelpKey{
+		{Key: "↑/↓", Desc: i18n.T(i18n.HelpNavigate)},
+		{Key: "u/d", Desc: i18n.T(i18n.HelpHalfPage)},
+		{Key: "g/G", Desc: i18n.T(i18n.HelpTopBottom)},
+		{Key: ":", Desc: i18n.T(i18n.HelpGotoLine)},
+		{Key: "w", Desc: i18n.T(i18n.HelpSoftWrap)},
+	}
+}
+
+// CapturingInput reports whether the go-to-line prompt is open.
+func (v *CodeView) CapturingInput() bool {
+	return v.prompting
+}
+
+func (v *CodeView) Type() string {
+	return CodeViewType
+}
+
+// splitCodeTokens splits a line of tokens into rows of at most width cells. Without
+// wrap, only the first row is kept.
+func splitCodeTokens(tokens []codeToken, width int, wrap bool) [][]codeToken {
+	rows := [][]codeToken{nil}
+	used := 0
+	for _, token := range tokens {
+		text := token.text
+		for text != "" {
+			if used == width {
+				if !wrap {
+					return rows
+				}
+				rows = append(rows, nil)
+				used = 0
+			}
+			head := ansi.Truncate(text, width-used, "")
+			if head == "" {
+				// A wide character that doesn't fit the row.
+				if used == 0 {
+					head = text[:len(text)-len(ansi.TruncateLeft(text, 1, ""))]
+				} else {
+					used = width
+					continue
+				}
+			}
+			rows[len(rows)-1] = append(rows[len(rows)-1], codeToken{text: head, style: token.style})
+			used += ansi.StringWidth(head)
+			text = text[len(head):]
+		}
+	}
+	return rows
+}
+
+func codeTokensWidth(tokens []codeToken) int {
+	width := 0
+	for _, token := range tokens {
+		width += ansi.StringWidth(token.text)
+	}
+	return width
+}
@@ -2,12 +2,12 @@ package views
 
 import (
 	"fmt"
-	"sort"
+	"io"
 
 	"charm.land/bubbles/v2/list"
 	tea "charm.land/bubbletea/v2"
-	"charm.land/glamour/v2"
 
+	"github.com/flowexec/tuikit/i18n"
 	"github.com/flowexec/tuikit/themes"
 	"github.com/flowexec/tuikit/types"
 )
@@ -24,6 +24,15 @@ type CollectionView struct {
 	styles        themes.Theme
 	callbacks     []types.KeyCallback
 	selectedFunc  func(header string) error
+	exportPrompt  exportPrompt
+	rendered      markdownCache
+
+	delegate   collectionDelegate
+	groupBy    func(info *types.EntityInfo) string
+	collapsed  map[string]bool
+	sortOrders []CollectionSortOrder
+	sortIndex  int
+	reverse    bool
 }
 
 func NewCollectionView(
@@ -44,35 +53,28 @@ func NewCollectionView(
 	default:
 		format = types.CollectionFormatList
 	}
-	items := make([]list.Item, 0)
-	for _, item := range collection.Items() {
-		items = append(items, item)
-	}
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].FilterValue() < items[j].FilterValue()
-	})
-	delegate := list.NewDefaultDelegate()
-	delegate.Styles = state.Theme.ListItemStyles()
-	delegate.ShowDescription = false
-	delegate.SetSpacing(0)
-
-	model := list.New(items, delegate, state.Width, state.Height)
+	delegate := newCollectionDelegate(state.Theme, hasDescriptions(collection.Items()))
+	model := list.New(nil, delegate, state.Width, state.Height)
 	model.SetShowTitle(false)
 	model.SetShowHelp(false)
 	model.SetShowPagination(false)
 	model.SetStatusBarItemName(collection.Singular(), collection.Plural())
 	model.Styles = state.Theme.ListStyles()
-	return &CollectionView{
+	v := &CollectionView{
 		collection:   collection,
 		model:        &model,
-		items:        items,
 		format:       format,
 		width:        state.ContentWidth,
 		height:       state.ContentHeight,
 		styles:       state.Theme,
 		selectedFunc: selectedFunc,
 		callbacks:    keys,
+		exportPrompt: newExportPrompt(false),
+		delegate:     delegate,
+		collapsed:    make(map[string]bool),
 	}
+	v.UpdateItemsFromCollections()
+	return v
 }
 
 func (v *CollectionView) Init() tea.Cmd {
@@ -86,16 +88,49 @@ func (v *CollectionView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case types.RenderState:
+	case *types.RenderState:
 		v.width = msg.ContentWidth
 		v.height = msg.ContentHeight
 		v.model.SetSize(v.width, v.height)
 	case tea.KeyPressMsg:
+		if v.exportPrompt.active {
+			return v, v.exportPrompt.update(msg, v.exportSnapshot)
+		}
 		// When the filter input is active, pass all keys through to the list.
 		if v.model.FilterState() == list.Filtering {
 			break
 		}
+		// Key callbacks take precedence over the built-in keys.
+		for _, cb := range v.callbacks {
+			if cb.Key == msg.String() {
+				if err := cb.Callback(); err != nil {
+					v.err = NewErrorView(err, v.styles)
+				}
+				return v, nil
+			}
+		}
 		switch msg.String() {
+		case "ctrl+s":
+			return v, v.exportPrompt.open()
+		case "o":
+			if len(v.sortOrders) > 0 {
+				return v, v.cycleSortOrder()
+			}
+		case "O":
+			if len(v.sortOrders) > 0 {
+				return v, v.reverseSortOrder()
+			}
+		case "d":
+			v.SetShowDescriptions(!v.delegate.ShowDescription)
+			return v, nil
+		case "+":
+			if v.groupBy != nil {
+				return v, v.toggleAllSections()
+			}
+		case "space", "tab":
+			if cmd, ok := v.toggleSection(); ok {
+				return v, cmd
+			}
 		case "-", "l":
 			if v.format == types.CollectionFormatList {
 				return v, nil
@@ -112,27 +147,21 @@ func (v *CollectionView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			v.format = types.CollectionFormatJSON
 		case types.KeyEnter:
+			if cmd, ok := v.toggleSection(); ok {
+				return v, cmd
+			}
 			if v.selectedFunc == nil {
 				return v, nil
 			}
-			selected := v.model.SelectedItem()
-			if selected == nil {
+			info, ok := v.model.SelectedItem().(*types.EntityInfo)
+			if !ok || info == nil {
 				return v, nil
 			}
 
-			if err := v.selectedFunc(selected.FilterValue()); err != nil {
+			if err := v.selectedFunc(info.ID); err != nil {
 				v.err = NewErrorView(err, v.styles)
 			}
 			return v, nil
-		default:
-			for _, cb := range v.callbacks {
-				if cb.Key == msg.String() {
-					if err := cb.Callback(); err != nil {
-						v.err = NewErrorView(err, v.styles)
-					}
-					return v, nil
-				}
-			}
 		}
 	}
 
@@ -141,19 +170,64 @@ func (v *CollectionView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return v, cmd
 }
 
-func (v *CollectionView) UpdateItemsFromCollections() {
-	items := make([]list.Item, 0)
-	for _, item := range v.collection.Items() {
-		items = append(items, item)
+func (v *CollectionView) Items() []list.Item {
+	return v.model.Items()
+}
+
+// SetFilter filters the list as if the query was typed after "/". Terms written as
+// tag:name or #name only keep items with that tag. An empty query clears the filter.
+func (v *CollectionView) SetFilter(query string) {
+	if query == "" {
+		v.model.ResetFilter()
+		return
 	}
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].FilterValue() < items[j].FilterValue()
-	})
-	v.items = items
+	v.model.SetFilterText(query)
 }
 
-func (v *CollectionView) Items() []list.Item {
-	return v.model.Items()
+// SelectedIndex returns the index of the selected item among the visible items.
+func (v *CollectionView) SelectedIndex() int {
+	return v.model.Index()
+}
+
+// SelectedData returns the ID, header, subheader and description of the selected
+// item, or nil when nothing is selected.
+func (v *CollectionView) SelectedData() []string {
+	info, ok := v.model.SelectedItem().(*types.EntityInfo)
+	if !ok || info == nil {
+		return nil
+	}
+	return []string{info.ID, info.Header, info.SubHeader, info.Desc}
+}
+
+// Export writes the items currently shown in the list, in the current sort order and
+// with the list's filter applied. Items in collapsed sections are included.
+func (v *CollectionView) Export(w io.Writer, opts ExportOptions) error {
+	if opts.Theme == nil {
+		opts.Theme = v.styles
+	}
+	return ExportRows(w, collectionExportColumns(), v.exportRows(), opts)
+}
+
+func (v *CollectionView) exportRows() []TableRow {
+	shown := v.model.VisibleItems()
+	if v.model.FilterState() == list.Unfiltered {
+		shown = v.buildItems(nil)
+	}
+	items := make([]*types.EntityInfo, 0, len(shown))
+	for _, item := range shown {
+		if info, ok := item.(*types.EntityInfo); ok {
+			items = append(items, info)
+		}
+	}
+	return collectionExportRows(items)
+}
+
+func (v *CollectionView) exportSnapshot(bool) exportJob {
+	rows := v.exportRows()
+	theme := v.styles
+	return func(w io.Writer, format ExportFormat) (int, error) {
+		return len(rows), ExportRows(w, collectionExportColumns(), rows, ExportOptions{Format: format, Theme: theme})
+	}
 }
 
 func (v *CollectionView) renderedView() tea.View {
@@ -170,42 +244,34 @@ func (v *CollectionView) renderedView() tea.View {
 		content = fmt.Sprintf("```json\n%s\n```", content)
 		isMkdwn = true
 	case types.CollectionFormatList:
-		v.model.SetSize(v.width, v.height)
-		v.UpdateItemsFromCollections()
+		height := v.height
+		if v.exportPrompt.active {
+			height-- // export prompt
+		}
+		v.model.SetSize(v.width, height)
 		style := v.styles.CollectionStyle().Width(v.width)
 		content = style.Render(v.model.View())
+		if v.exportPrompt.active {
+			content += "\n" + v.exportPrompt.view(v.styles, v.width)
+		}
 	case types.EntityFormatDocument:
 		fallthrough
 	default:
-		content = "unsupported format"
+		content = i18n.T(i18n.MsgUnsupportedFormat)
 	}
 	if err != nil {
 		v.err = NewErrorView(err, v.styles)
 		return v.err.View()
 	}
 	if content == "" {
-		content = "no data"
+		content = i18n.T(i18n.MsgNoData)
 	}
 
 	if !isMkdwn {
 		return tea.View{Content: content}
 	}
 
-	mdStyles, err := v.styles.GlamourMarkdownStyleJSON()
-	if err != nil {
-		v.err = NewErrorView(err, v.styles)
-		return v.err.View()
-	}
-	renderer, err := glamour.NewTermRenderer(
-		glamour.WithStylesFromJSONBytes([]byte(mdStyles)),
-		glamour.WithWordWrap(v.width-2),
-	)
-	if err != nil {
-		v.err = NewErrorView(err, v.styles)
-		return v.err.View()
-	}
-
-	viewStr, err := renderer.Render(content)
+	viewStr, err := v.rendered.render(v.styles, v.width-2, false, content)
 	if err != nil {
 		v.err = NewErrorView(err, v.styles)
 		return v.err.View()
@@ -232,19 +298,30 @@ func (v *CollectionView) HelpBindings() []themes.HelpKey {
 		}
 	}
 	if v.selectedFunc != nil {
-		keys = append(keys, themes.HelpKey{Key: "enter", Desc: "select"})
+		keys = append(keys, themes.HelpKey{Key: "enter", Desc: i18n.T(i18n.HelpSelect)})
+	}
+	if v.groupBy != nil {
+		keys = append(keys,
+			themes.HelpKey{Key: "space/tab", Desc: i18n.T(i18n.HelpExpandCollapse)},
+			themes.HelpKey{Key: "+", Desc: i18n.T(i18n.HelpExpandAll)},
+		)
+	}
+	keys = append(keys, themes.HelpKey{Key: "/", Desc: i18n.T(i18n.HelpFilter)})
+	if len(v.sortOrders) > 0 {
+		keys = append(keys, themes.HelpKey{Key: "o/O", Desc: i18n.T(i18n.HelpSortOrder)})
 	}
 	keys = append(keys,
-		themes.HelpKey{Key: "/", Desc: "filter"},
-		themes.HelpKey{Key: "l", Desc: "list"},
-		themes.HelpKey{Key: "y", Desc: "yaml"},
-		themes.HelpKey{Key: "j", Desc: "json"},
+		themes.HelpKey{Key: "d", Desc: i18n.T(i18n.HelpDescriptions)},
+		themes.HelpKey{Key: "l", Desc: i18n.T(i18n.HelpList)},
+		themes.HelpKey{Key: "y", Desc: i18n.T(i18n.HelpYAML)},
+		themes.HelpKey{Key: "j", Desc: i18n.T(i18n.HelpJSON)},
+		themes.HelpKey{Key: "ctrl+s", Desc: i18n.T(i18n.HelpExport)},
 	)
 	return keys
 }
 
 func (v *CollectionView) CapturingInput() bool {
-	return v.model.FilterState() == list.Filtering
+	return v.model.FilterState() == list.Filtering || v.exportPrompt.active
 }
 
 func (v *CollectionView) Type() string {
@@ -0,0 +1,284 @@
+package views
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/flowexec/tuikit/i18n"
+	"github.com/flowexec/tuikit/themes"
+	"github.com/flowexec/tuikit/types"
+)
+
+// CollectionSortOrder is a named order for the items of a CollectionView.
+type CollectionSortOrder struct {
+	Name    string
+	Compare func(a, b *types.EntityInfo) int
+}
+
+var (
+	// SortByID orders items by ID. It is the default order.
+	SortByID = CollectionSortOrder{Name: "id", Compare: func(a, b *types.EntityInfo) int {
+		return cmp.Compare(a.ID, b.ID)
+	}}
+	// SortByHeader orders items by header, case-insensitively.
+	SortByHeader = CollectionSortOrder{Name: "name", Compare: func(a, b *types.EntityInfo) int {
+		return cmp.Or(cmp.Compare(strings.ToLower(a.Header), strings.ToLower(b.Header)), cmp.Compare(a.ID, b.ID))
+	}}
+)
+
+// DefaultCollectionSortOrders are used when no sort orders are set. Only the first
+// is applied, since "o" and "O" are only bound once sort orders are set.
+var DefaultCollectionSortOrders = []CollectionSortOrder{SortByID, SortByHeader}
+
+// GroupBySubHeader groups the items of a CollectionView by their SubHeader.
+func GroupBySubHeader(info *types.EntityInfo) string {
+	return info.SubHeader
+}
+
+// collectionSection is the list item heading a group of items.
+type collectionSection struct {
+	name      string
+	count     int
+	collapsed bool
+}
+
+// FilterValue is empty so that sections are hidden while filtering.
+func (s *collectionSection) FilterValue() string { return "" }
+
+// SetGroupBy groups the items into collapsible sections named by the function. Items
+// with an empty group name are shown last. A nil function shows a flat list.
+func (v *CollectionView) SetGroupBy(groupBy func(info *types.EntityInfo) string) tea.Cmd {
+	v.groupBy = groupBy
+	v.model.SetShowStatusBar(groupBy == nil)
+	return v.UpdateItemsFromCollections()
+}
+
+// SetSortOrders sets the sort orders cycled with "o" and reversed with "O". The first
+// order is applied.
+func (v *CollectionView) SetSortOrders(orders ...CollectionSortOrder) tea.Cmd {
+	v.sortOrders = orders
+	v.sortIndex = 0
+	return v.UpdateItemsFromCollections()
+}
+
+// SetShowDescriptions shows or hides the item descriptions below their titles. By
+// default, descriptions are shown when any item has one.
+func (v *CollectionView) SetShowDescriptions(show bool) {
+	v.delegate.ShowDescription = show
+	v.model.SetDelegate(v.delegate)
+}
+
+func (v *CollectionView) orders() []CollectionSortOrder {
+	if len(v.sortOrders) == 0 {
+		return DefaultCollectionSortOrders
+	}
+	return v.sortOrders
+}
+
+func (v *CollectionView) sortOrder() CollectionSortOrder {
+	orders := v.orders()
+	return orders[v.sortIndex%len(orders)]
+}
+
+func (v *CollectionView) cycleSortOrder() tea.Cmd {
+	v.sortIndex = (v.sortIndex + 1) % len(v.orders())
+	return tea.Batch(v.UpdateItemsFromCollections(), v.sortNotice())
+}
+
+func (v *CollectionView) reverseSortOrder() tea.Cmd {
+	v.reverse = !v.reverse
+	return tea.Batch(v.UpdateItemsFromCollections(), v.sortNotice())
+}
+
+func (v *CollectionView) sortNotice() tea.Cmd {
+	name := v.sortOrder().Name
+	if v.reverse {
+		name += " ↓"
+	}
+	return noticeCmd(i18n.Tf(i18n.MsgSortedBy, name), themes.OutputLevelInfo)
+}
+
+// buildItems returns the collection's items in the current sort order, preceded by
+// their section when grouped. The items of collapsed sections are left out.
+func (v *CollectionView) buildItems(collapsed map[string]bool) []list.Item {
+	entities := slices.Clone(v.collection.Items())
+	order := v.sortOrder()
+	slices.SortStableFunc(entities, func(a, b *types.EntityInfo) int {
+		if v.reverse {
+			return order.Compare(b, a)
+		}
+		return order.Compare(a, b)
+	})
+
+	items := make([]list.Item, 0, len(entities))
+	if v.groupBy == nil {
+		for _, info := range entities {
+			items = append(items, info)
+		}
+		return items
+	}
+
+	groups := make(map[string][]*types.EntityInfo)
+	for _, info := range entities {
+		name := v.groupBy(info)
+		groups[name] = append(groups[name], info)
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		// Ungrouped items are shown last.
+		if (a == "") != (b == "") {
+			return cmp.Compare(b, a)
+		}
+		return cmp.Compare(a, b)
+	})
+	for _, name := range names {
+		section := &collectionSection{name: name, count: len(groups[name]), collapsed: collapsed[name]}
+		items = append(items, section)
+		if section.collapsed {
+			continue
+		}
+		for _, info := range groups[name] {
+			items = append(items, info)
+		}
+	}
+	return items
+}
+
+// UpdateItemsFromCollections reloads the items from the collection, keeping the
+// selected item.
+func (v *CollectionView) UpdateItemsFromCollections() tea.Cmd {
+	selected := v.model.SelectedItem()
+	v.items = v.buildItems(v.collapsed)
+	v.model.Filter = filterCollection(v.items)
+	cmd := v.model.SetItems(v.items)
+	if selected != nil {
+		if i := slices.IndexFunc(v.model.VisibleItems(), func(item list.Item) bool {
+			return sameCollectionItem(item, selected)
+		}); i >= 0 {
+			v.model.Select(i)
+		}
+	}
+	return cmd
+}
+
+func sameCollectionItem(a, b list.Item) bool {
+	switch a := a.(type) {
+	case *collectionSection:
+		s, ok := b.(*collectionSection)
+		return ok && s.name == a.name
+	case *types.EntityInfo:
+		info, ok := b.(*types.EntityInfo)
+		return ok && info.ID == a.ID
+	}
+	return false
+}
+
+// toggleSection collapses or expands the selected section. It returns false when the
+// selected item isn't a section.
+func (v *CollectionView) toggleSection() (tea.Cmd, bool) {
+	section, ok := v.model.SelectedItem().(*collectionSection)
+	if !ok || v.groupBy == nil {
+		return nil, false
+	}
+	v.collapsed[section.name] = !section.collapsed
+	return v.UpdateItemsFromCollections(), true
+}
+
+// toggleAllSections collapses every section, or expands them all when they are
+// already collapsed.
+func (v *CollectionView) toggleAllSections() tea.Cmd {
+	if v.groupBy == nil {
+		return nil
+	}
+	sections := make([]string, 0)
+	allCollapsed := true
+	for _, item := range v.items {
+		if section, ok := item.(*collectionSection); ok {
+			sections = append(sections, section.name)
+			allCollapsed = allCollapsed && section.collapsed
+		}
+	}
+	for _, name := range sections {
+		v.collapsed[name] = !allCollapsed
+	}
+	return v.UpdateItemsFromCollections()
+}
+
+// collectionDelegate renders items like the default delegate, with their
+// descriptions and their status and tag badges, and renders section headings with
+// their item count.
+type collectionDelegate struct {
+	list.DefaultDelegate
+	theme themes.Theme
+}
+
+func newCollectionDelegate(theme themes.Theme, showDescription bool) collectionDelegate {
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles = theme.ListItemStyles()
+	delegate.ShowDescription = showDescription
+	delegate.SetSpacing(0)
+	return collectionDelegate{DefaultDelegate: delegate, theme: theme}
+}
+
+func (d collectionDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	section, ok := item.(*collectionSection)
+	if !ok {
+		d.renderItem(w, m, index, item)
+		return
+	}
+
+	name := section.name
+	if name == "" {
+		name = i18n.T(i18n.MsgUngrouped)
+	}
+	marker := "▾"
+	if section.collapsed {
+		marker = "▸"
+	}
+	style := d.Styles.NormalTitle.Foreground(d.theme.ColorPalette().TertiaryColor()).Bold(true)
+	if index == m.Index() {
+		style = d.Styles.SelectedTitle
+	}
+	width := m.Width() - style.GetHorizontalFrameSize()
+	title := ansi.Truncate(fmt.Sprintf("%s %s (%d)", marker, name, section.count), width, "…")
+	fmt.Fprint(w, style.Render(title)) //nolint:errcheck
+	if d.ShowDescription {
+		fmt.Fprint(w, "\n") //nolint:errcheck
+	}
+}
+
+// renderItem renders an item with the default delegate and appends its badges to the
+// title line, truncating the title to make room for them.
+func (d collectionDelegate) renderItem(w io.Writer, m list.Model, index int, item list.Item) {
+	info, ok := item.(*types.EntityInfo)
+	badges := ""
+	if ok {
+		badges = renderEntityBadges(d.theme, info)
+	}
+	if badges == "" {
+		d.DefaultDelegate.Render(w, m, index, item)
+		return
+	}
+	var buf strings.Builder
+	d.DefaultDelegate.Render(&buf, m, index, item)
+	title, rest, _ := strings.Cut(buf.String(), "\n")
+	title = ansi.Truncate(title, max(m.Width()-ansi.StringWidth(badges)-1, 0), "…")
+	fmt.Fprint(w, title, " ", badges) //nolint:errcheck
+	if d.ShowDescription {
+		fmt.Fprint(w, "\n", rest) //nolint:errcheck
+	}
+}
+
+func hasDescriptions(items []*types.EntityInfo) bool {
+	return slices.ContainsFunc(items, func(info *types.EntityInfo) bool { return info.Desc != "" })
+}
@@ -6,7 +6,9 @@ import (
 	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
 
+	"github.com/flowexec/tuikit/i18n"
 	"github.com/flowexec/tuikit/themes"
 	"github.com/flowexec/tuikit/types"
 )
@@ -27,6 +29,7 @@ type DetailView struct {
 	metadataHeight int
 
 	viewport viewport.Model
+	search   contentSearch
 	theme    themes.Theme
 	width    int
 	height   int
@@ -40,6 +43,7 @@ func NewDetailView(
 	v := &DetailView{
 		metadata: metadata,
 		body:     body,
+		search:   newContentSearch(),
 		theme:    state.Theme,
 		width:    state.ContentWidth,
 		height:   state.ContentHeight,
@@ -60,6 +64,9 @@ func (v *DetailView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		v.theme = msg.Theme
 		v.syncViewport()
 	case tea.KeyPressMsg:
+		if cmd, ok := v.search.update(msg); ok {
+			return v, cmd
+		}
 		halfPage := max(v.viewport.Height()/2, 1)
 		switch msg.String() {
 		case "k":
@@ -84,13 +91,17 @@ func (v *DetailView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (v *DetailView) View() tea.View {
 	metaStr := v.renderMetadata()
-	v.viewport.SetContent(v.body)
+	v.syncViewport()
+	v.search.render(&v.viewport, v.body, v.theme)
 
 	var sections []string
 	if metaStr != "" {
 		sections = append(sections, metaStr)
 	}
 	sections = append(sections, v.renderBodyBox())
+	if v.search.active() {
+		sections = append(sections, v.search.view(v.theme, v.width-4))
+	}
 
 	content := lipgloss.NewStyle().MarginLeft(2).Render(
 		lipgloss.JoinVertical(lipgloss.Left, sections...),
@@ -99,11 +110,16 @@ func (v *DetailView) View() tea.View {
 }
 
 func (v *DetailView) HelpBindings() []themes.HelpKey {
-	return []themes.HelpKey{
-		{Key: "j/k", Desc: "scroll"},
-		{Key: "u/d", Desc: "half-page"},
-		{Key: "g/G", Desc: "top/bottom"},
-	}
+	return append([]themes.HelpKey{
+		{Key: "j/k", Desc: i18n.T(i18n.HelpScroll)},
+		{Key: "u/d", Desc: i18n.T(i18n.HelpHalfPage)},
+		{Key: "g/G", Desc: i18n.T(i18n.HelpTopBottom)},
+	}, searchHelpKeys()...)
+}
+
+// CapturingInput reports whether the search prompt is open.
+func (v *DetailView) CapturingInput() bool {
+	return v.search.prompting
 }
 
 func (v *DetailView) Type() string {
@@ -123,7 +139,7 @@ func (v *DetailView) syncViewport() {
 	v.metadataHeight = v.calcMetadataHeight()
 	// Body box border (2) + padding (2) are chrome around the viewport
 	bodyChrome := 4
-	vpHeight := max(v.height-v.metadataHeight-bodyChrome, 1)
+	vpHeight := max(v.height-v.metadataHeight-bodyChrome-v.search.height(), 1)
 
 	v.viewport.SetHeight(vpHeight)
 	v.viewport.SetWidth(v.width - 10) // account for margin (2) + border (2) + padding (4) + buffer (2)
@@ -145,10 +161,12 @@ func (v *DetailView) renderMetadata() string {
 	cp := v.theme.ColorPalette()
 	maxKeyLen := 0
 	for _, f := range v.metadata {
-		if len(f.Key) > maxKeyLen {
-			maxKeyLen = len(f.Key)
-		}
+		maxKeyLen = max(maxKeyLen, ansi.StringWidth(f.Key))
 	}
+	tableWidth := min(v.width-6, 60)
+	// Values are truncated so that each field stays on one line: border (2),
+	// padding (2), key column, separator and value padding (3).
+	maxValLen := max(tableWidth-4-(maxKeyLen+1)-3, 1)
 
 	keyStyle := lipgloss.NewStyle().
 		Foreground(cp.SecondaryColor()).
@@ -164,11 +182,11 @@ func (v *DetailView) renderMetadata() string {
 
 	var rows []string
 	for _, f := range v.metadata {
-		row := keyStyle.Render(f.Key) + " " + sep + valStyle.Render(f.Value)
+		value := ansi.Truncate(f.Value, maxValLen, truncationTail(maxValLen))
+		row := keyStyle.Render(f.Key) + " " + sep + valStyle.Render(value)
 		rows = append(rows, row)
 	}
 
-	tableWidth := min(v.width-6, 60)
 	return lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(cp.BorderColor()).
@@ -0,0 +1,409 @@
+package views
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/viewport"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/flowexec/tuikit/i18n"
+	"github.com/flowexec/tuikit/io"
+	"github.com/flowexec/tuikit/themes"
+	"github.com/flowexec/tuikit/types"
+)
+
+const DiffViewType = "diff"
+
+// DefaultDiffContext is the number of unchanged lines kept around each change when
+// unchanged regions are folded.
+const DefaultDiffContext = 3
+
+// DiffMode is how a DiffView lays out the compared texts.
+type DiffMode int
+
+const (
+	DiffSideBySide DiffMode = iota
+	DiffUnified
+)
+
+// diffRow is a rendered row of a diff. In unified mode, and for unchanged and fold
+// lines, only left is set. In side-by-side mode, changed rows pair a deleted line on
+// the left with an inserted line on the right.
+type diffRow struct {
+	left, right *diffLine
+	hunk        bool // first row of a change
+}
+
+// DiffView compares two texts line by line. Changes are shown side by side or as a
+// unified diff, with unchanged regions folded by default. Lines are colored with the
+// theme's code style when a language is set.
+type DiffView struct {
+	render   *types.RenderState
+	viewport viewport.Model
+	err      *ErrorView
+
+	oldTitle, newTitle string
+	oldLines, newLines []string
+	highlighted        bool
+	lines              []diffLine
+	rows               []diffRow
+	added, removed     int
+
+	mode    DiffMode
+	context int
+	folded  bool
+}
+
+// NewDiffView compares two texts. The language is a chroma language name, file name or
+// extension used to color the lines; when empty, the lines are not colored.
+func NewDiffView(render *types.RenderState, oldTitle, oldText, newTitle, newText, language string) *DiffView {
+	vp := viewport.New(viewport.WithWidth(render.ContentWidth), viewport.WithHeight(max(render.ContentHeight-1, 1)))
+	oldPlain, newPlain := strings.Split(oldText, "\n"), strings.Split(newText, "\n")
+	v := &DiffView{
+		render:   render,
+		viewport: vp,
+		oldTitle: oldTitle,
+		newTitle: newTitle,
+		oldLines: highlightLines(oldText, language, render.Theme),
+		newLines: highlightLines(newText, language, render.Theme),
+		lines:    diffLines(oldPlain, newPlain),
+		context:  DefaultDiffContext,
+		folded:   true,
+	}
+	v.highlighted = codeLexer(language) != nil
+	for _, line := range v.lines {
+		switch line.op {
+		case diffInsert:
+			v.added++
+		case diffDelete:
+			v.removed++
+		}
+	}
+	v.refresh()
+	return v
+}
+
+// NewEntityDiffView compares two entities in the given format, YAML or JSON.
+func NewEntityDiffView(
+	render *types.RenderState,
+	oldTitle string, oldEntity types.Entity,
+	newTitle string, newEntity types.Entity,
+	format types.Format,
+) *DiffView {
+	encode := types.Entity.YAML
+	language := "yaml"
+	//nolint:exhaustive
+	switch format {
+	case "json", "JSON":
+		encode, language = types.Entity.JSON, "json"
+	}
+	oldText, err := encode(oldEntity)
+	if err != nil {
+		return &DiffView{render: render, err: NewErrorView(err, render.Theme)}
+	}
+	newText, err := encode(newEntity)
+	if err != nil {
+		return &DiffView{render: render, err: NewErrorView(err, render.Theme)}
+	}
+	return NewDiffView(render, oldTitle, oldText, newTitle, newText, language)
+}
+
+// NewArchiveDiffView compares the logs of two log archive entries.
+func NewArchiveDiffView(render *types.RenderState, oldEntry, newEntry io.ArchiveEntry) *DiffView {
+	oldText, err := oldEntry.Read()
+	if err != nil {
+		return &DiffView{render: render, err: NewErrorView(err, render.Theme)}
+	}
+	newText, err := newEntry.Read()
+	if err != nil {
+		return &DiffView{render: render, err: NewErrorView(err, render.Theme)}
+	}
+	title := func(e io.ArchiveEntry) string { return e.Title() + " " + e.Description() }
+	return NewDiffView(render, title(oldEntry), oldText, title(newEntry), newText, "")
+}
+
+// SetMode sets the layout of the diff.
+func (v *DiffView) SetMode(mode DiffMode) {
+	v.mode = mode
+	v.refresh()
+}
+
+// SetFolded folds or unfolds the unchanged regions. Folding keeps context unchanged
+// lines around each change; when context is negative, DefaultDiffContext is used.
+func (v *DiffView) SetFolded(folded bool, context int) {
+	v.folded = folded
+	if context < 0 {
+		context = DefaultDiffContext
+	}
+	v.context = context
+	v.refresh()
+}
+
+// Mode returns the layout of the diff.
+func (v *DiffView) Mode() DiffMode {
+	return v.mode
+}
+
+func (v *DiffView) Init() tea.Cmd {
+	return nil
+}
+
+func (v *DiffView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if v.err != nil {
+		return v.err.Update(msg)
+	}
+	switch msg := msg.(type) {
+	case *types.RenderState:
+		v.render = msg
+		v.viewport.SetWidth(msg.ContentWidth)
+		v.viewport.SetHeight(max(msg.ContentHeight-1, 1))
+		v.refresh()
+		return v, nil
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "n":
+			v.jumpToHunk(1)
+			return v, nil
+		case "N":
+			v.jumpToHunk(-1)
+			return v, nil
+		case "u":
+			if v.mode == DiffUnified {
+				v.SetMode(DiffSideBySide)
+			} else {
+				v.SetMode(DiffUnified)
+			}
+			return v, nil
+		case "f":
+			v.folded = !v.folded
+			v.refresh()
+			return v, nil
+		case "g":
+			v.viewport.GotoTop()
+			return v, nil
+		case "G":
+			v.viewport.GotoBottom()
+			return v, nil
+		}
+	}
+	var cmd tea.Cmd
+	v.viewport, cmd = v.viewport.Update(msg)
+	return v, cmd
+}
+
+// hunkOffset is the scroll offset that shows a change with a few lines above it.
+func hunkOffset(row int) int {
+	return max(row-2, 0)
+}
+
+// jumpToHunk scrolls to the next (dir > 0) or previous change.
+func (v *DiffView) jumpToHunk(dir int) {
+	offset := v.viewport.YOffset()
+	target := -1
+	for i, row := range v.rows {
+		if !row.hunk {
+			continue
+		}
+		if dir > 0 && hunkOffset(i) > offset {
+			target = i
+			break
+		}
+		if dir < 0 && hunkOffset(i) < offset {
+			target = i
+		}
+	}
+	if target >= 0 {
+		v.viewport.SetYOffset(hunkOffset(target))
+	}
+}
+
+// refresh rebuilds the rows for the current mode and folding, and renders them.
+func (v *DiffView) refresh() {
+	if v.render == nil || v.err != nil {
+		return
+	}
+	lines := v.lines
+	if v.folded {
+		lines = foldLines(lines, v.context)
+	}
+
+	v.rows = v.rows[:0]
+	for i := 0; i < len(lines); {
+		line := &lines[i]
+		hunk := line.op != diffEqual && line.op != diffFold &&
+			(i == 0 || lines[i-1].op == diffEqual || lines[i-1].op == diffFold)
+		if v.mode == DiffUnified || line.op == diffEqual || line.op == diffFold {
+			v.rows = append(v.rows, diffRow{left: line, hunk: hunk})
+			i++
+			continue
+		}
+		var deleted, inserted []*diffLine
+		for ; i < len(lines) && (lines[i].op == diffDelete || lines[i].op == diffInsert); i++ {
+			if lines[i].op == diffDelete {
+				deleted = append(deleted, &lines[i])
+			} else {
+				inserted = append(inserted, &lines[i])
+			}
+		}
+		for r := range max(len(deleted), len(inserted)) {
+			row := diffRow{hunk: r == 0}
+			if r < len(deleted) {
+				row.left = deleted[r]
+			}
+			if r < len(inserted) {
+				row.right = inserted[r]
+			}
+			v.rows = append(v.rows, row)
+		}
+	}
+
+	rendered := make([]string, len(v.rows))
+	for i, row := range v.rows {
+		if v.mode == DiffUnified {
+			rendered[i] = v.renderUnifiedRow(row)
+		} else {
+			rendered[i] = v.renderSideBySideRow(row)
+		}
+	}
+	v.viewport.SetContentLines(rendered)
+}
+
+func (v *DiffView) gutterWidth() int {
+	return len(strconv.Itoa(max(len(v.oldLines), len(v.newLines))))
+}
+
+func (v *DiffView) lineNumber(n int) string {
+	num := ""
+	if n > 0 {
+		num = strconv.Itoa(n)
+	}
+	style := lipgloss.NewStyle().Foreground(v.render.Theme.ColorPalette().GrayColor())
+	return style.Render(fmt.Sprintf("%*s", v.gutterWidth(), num))
+}
+
+// lineText returns the sign and text of a line, taking the text from the old or new
+// side.
+func (v *DiffView) lineText(line *diffLine, old bool) string {
+	cp := v.render.Theme.ColorPalette()
+	var text string
+	if old {
+		text = v.oldLines[line.old-1]
+	} else {
+		text = v.newLines[line.new-1]
+	}
+	var style lipgloss.Style
+	sign := " "
+	switch line.op {
+	case diffDelete:
+		style, sign = lipgloss.NewStyle().Foreground(cp.ErrorColor()), "-"
+	case diffInsert:
+		style, sign = lipgloss.NewStyle().Foreground(cp.SuccessColor()), "+"
+	default:
+		if !v.highlighted {
+			text = lipgloss.NewStyle().Foreground(cp.BodyColor()).Render(text)
+		}
+		return sign + " " + text
+	}
+	if !v.highlighted {
+		text = style.Render(text)
+	}
+	return style.Bold(true).Render(sign) + " " + text
+}
+
+func (v *DiffView) renderFold(line *diffLine) string {
+	style := lipgloss.NewStyle().Foreground(v.render.Theme.ColorPalette().GrayColor()).Italic(true)
+	return strings.Repeat(" ", v.gutterWidth()) + " " + style.Render("⋯ "+i18n.Tf(i18n.MsgFoldedLines, line.count))
+}
+
+func (v *DiffView) renderUnifiedRow(row diffRow) string {
+	line := row.left
+	var content string
+	switch line.op {
+	case diffFold:
+		content = v.renderFold(line)
+	case diffInsert:
+		content = v.lineNumber(line.old) + " " + v.lineNumber(line.new) + " " + v.lineText(line, false)
+	default:
+		content = v.lineNumber(line.old) + " " + v.lineNumber(line.new) + " " + v.lineText(line, true)
+	}
+	return ansi.Truncate(content, v.render.ContentWidth, "…")
+}
+
+// halfWidth is the width of each side in side-by-side mode.
+func (v *DiffView) halfWidth() int {
+	return max((v.render.ContentWidth-3)/2, 1)
+}
+
+func (v *DiffView) renderSideBySideRow(row diffRow) string {
+	half := v.halfWidth()
+	cell := func(content string) string {
+		content = ansi.Truncate(content, half, "…")
+		return content + strings.Repeat(" ", max(half-ansi.StringWidth(content), 0))
+	}
+	separator := lipgloss.NewStyle().Foreground(v.render.Theme.ColorPalette().BorderColor()).Render(" │ ")
+
+	var left, right string
+	switch {
+	case row.left != nil && row.left.op == diffFold:
+		return ansi.Truncate(v.renderFold(row.left), v.render.ContentWidth, "…")
+	case row.left != nil && row.left.op == diffEqual:
+		left = v.lineNumber(row.left.old) + " " + v.lineText(row.left, true)
+		right = v.lineNumber(row.left.new) + " " + v.lineText(row.left, false)
+	default:
+		if row.left != nil {
+			left = v.lineNumber(row.left.old) + " " + v.lineText(row.left, true)
+		}
+		if row.right != nil {
+			right = v.lineNumber(row.right.new) + " " + v.lineText(row.right, false)
+		}
+	}
+	return cell(left) + separator + cell(right)
+}
+
+func (v *DiffView) renderHeader() string {
+	cp := v.render.Theme.ColorPalette()
+	removed := lipgloss.NewStyle().Foreground(cp.ErrorColor()).Bold(true)
+	added := lipgloss.NewStyle().Foreground(cp.SuccessColor()).Bold(true)
+	gray := lipgloss.NewStyle().Foreground(cp.GrayColor())
+
+	summary := gray.Render(i18n.T(i18n.MsgNoDifferences))
+	if v.added+v.removed > 0 {
+		summary = added.Render(fmt.Sprintf("+%d", v.added)) + " " + removed.Render(fmt.Sprintf("-%d", v.removed))
+	}
+	oldTitle, newTitle := removed.Render("--- "+v.oldTitle), added.Render("+++ "+v.newTitle)
+	if v.mode == DiffUnified {
+		return ansi.Truncate(oldTitle+"  "+newTitle+"  "+summary, v.render.ContentWidth, "…")
+	}
+	half := v.halfWidth()
+	left := ansi.Truncate(oldTitle, half, "…")
+	left += strings.Repeat(" ", max(half-ansi.StringWidth(left), 0))
+	return ansi.Truncate(left+"   "+newTitle+"  "+summary, v.render.ContentWidth, "…")
+}
+
+func (v *DiffView) View() tea.View {
+	if v.err != nil {
+		return v.err.View()
+	}
+	return tea.View{Content: v.renderHeader() + "\n" + v.viewport.View()}
+}
+
+func (v *DiffView) HelpBindings() []themes.HelpKey {
+	if v.err != nil {
+		return nil
+	}
+	return []themes.HelpKey{
+		{Key: "↑/↓", Desc: i18n.T(i18n.HelpScroll)},
+		{Key: "n/N", Desc: i18n.T(i18n.HelpNextChange)},
+		{Key: "u", Desc: i18n.T(i18n.HelpDiffMode)},
+		{Key: "f", Desc: i18n.T(i18n.HelpFoldUnchanged)},
+		{Key: "g/G", Desc: i18n.T(i18n.HelpTopBottom)},
+	}
+}
+
+func (v *DiffView) Type() string {
+	return DiffViewType
+}
@@ -0,0 +1,167 @@
+package views
+
+import "slices"
+
+// maxDiffEdits bounds the work done to diff two texts. Texts that differ in more lines
+// are shown as a single replacement of the differing region.
+const maxDiffEdits = 1000
+
+type diffOp int
+
+const (
+	diffEqual diffOp = iota
+	diffDelete
+	diffInsert
+	diffFold
+)
+
+// diffLine is a line of a diff. Old and new are 1-based line numbers in the old and
+// new text, or 0 when the line isn't in that text. Fold lines stand for a run of
+// unchanged lines and hold its length in count.
+type diffLine struct {
+	op       diffOp
+	old, new int
+	count    int
+}
+
+// diffLines returns the shortest edit script turning a into b, using Myers' algorithm
+// on the lines that remain after trimming the common prefix and suffix.
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]diffLine, 0, max(len(a), len(b)))
+	for i := range prefix {
+		lines = append(lines, diffLine{op: diffEqual, old: i + 1, new: i + 1})
+	}
+	lines = append(lines, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix, prefix)...)
+	for i := range suffix {
+		lines = append(lines, diffLine{op: diffEqual, old: len(a) - suffix + i + 1, new: len(b) - suffix + i + 1})
+	}
+	return lines
+}
+
+// myersDiff diffs the lines of a and b, which start after the given line offsets.
+func myersDiff(a, b []string, oldOffset, newOffset int) []diffLine {
+	n, m := len(a), len(b)
+	if n+m == 0 {
+		return nil
+	}
+	offset := n + m
+	v := make([]int, 2*offset+2)
+	// trace[d] holds v[-d..d] after d edits, for backtracking.
+	var trace [][]int
+	limit := min(n+m, maxDiffEdits)
+search:
+	for d := 0; ; d++ {
+		if d > limit {
+			return replaceLines(n, m, oldOffset, newOffset)
+		}
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // insertion
+			} else {
+				x = v[offset+k-1] + 1 // deletion
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				trace = append(trace, slices.Clone(v[offset-d:offset+d+1]))
+				break search
+			}
+		}
+		trace = append(trace, slices.Clone(v[offset-d:offset+d+1]))
+	}
+
+	// Backtrack from (n, m) to (0, 0), collecting the lines in reverse.
+	lines := make([]diffLine, 0, max(n, m))
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1] // v[-(d-1)..d-1]
+		at := func(k int) int { return prev[k+d-1] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			lines = append(lines, diffLine{op: diffEqual, old: oldOffset + x, new: newOffset + y})
+			x--
+			y--
+		}
+		if x == prevX {
+			lines = append(lines, diffLine{op: diffInsert, new: newOffset + y})
+			y--
+		} else {
+			lines = append(lines, diffLine{op: diffDelete, old: oldOffset + x})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		lines = append(lines, diffLine{op: diffEqual, old: oldOffset + x, new: newOffset + y})
+		x--
+		y--
+	}
+	slices.Reverse(lines)
+	return lines
+}
+
+// replaceLines returns a diff deleting n old lines and inserting m new lines.
+func replaceLines(n, m, oldOffset, newOffset int) []diffLine {
+	lines := make([]diffLine, 0, n+m)
+	for i := 1; i <= n; i++ {
+		lines = append(lines, diffLine{op: diffDelete, old: oldOffset + i})
+	}
+	for i := 1; i <= m; i++ {
+		lines = append(lines, diffLine{op: diffInsert, new: newOffset + i})
+	}
+	return lines
+}
+
+// foldLines replaces runs of unchanged lines that are more than context lines away
+// from a change with a fold line.
+func foldLines(lines []diffLine, context int) []diffLine {
+	folded := make([]diffLine, 0, len(lines))
+	for i := 0; i < len(lines); {
+		if lines[i].op != diffEqual {
+			folded = append(folded, lines[i])
+			i++
+			continue
+		}
+		end := i
+		for end < len(lines) && lines[end].op == diffEqual {
+			end++
+		}
+		keepBefore, keepAfter := context, context
+		if i == 0 {
+			keepBefore = 0
+		}
+		if end == len(lines) {
+			keepAfter = 0
+		}
+		if end-i <= keepBefore+keepAfter+1 {
+			folded = append(folded, lines[i:end]...)
+		} else {
+			folded = append(folded, lines[i:i+keepBefore]...)
+			folded = append(folded, diffLine{op: diffFold, count: end - i - keepBefore - keepAfter})
+			folded = append(folded, lines[end-keepAfter:end]...)
+		}
+		i = end
+	}
+	return folded
+}
@@ -3,11 +3,12 @@ package views
 import (
 	"fmt"
 	"math"
+	"slices"
 
 	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
-	"charm.land/glamour/v2"
 
+	"github.com/flowexec/tuikit/i18n"
 	"github.com/flowexec/tuikit/themes"
 	"github.com/flowexec/tuikit/types"
 )
@@ -16,6 +17,8 @@ type EntityView struct {
 	entity types.Entity
 
 	viewport viewport.Model
+	rendered markdownCache
+	search   contentSearch
 	err      *ErrorView
 
 	styles        themes.Theme
@@ -52,6 +55,7 @@ func NewEntityView(
 		format:    format,
 		callbacks: keys,
 		viewport:  vp,
+		search:    newContentSearch(),
 	}
 }
 
@@ -64,14 +68,23 @@ func (v *EntityView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if v.err != nil {
 		return v.err.Update(msg)
 	}
+	// Key callbacks take precedence over the search keys, except while typing a query.
+	if msg, ok := msg.(tea.KeyPressMsg); ok && (v.search.prompting || !v.hasCallback(msg.String())) {
+		if cmd, handled := v.search.update(msg); handled {
+			return v, cmd
+		}
+	}
 	var cmd tea.Cmd
 	v.viewport, cmd = v.viewport.Update(msg)
 
 	switch msg := msg.(type) {
-	case types.RenderState:
+	case *types.RenderState:
+		v.width = msg.ContentWidth
+		v.height = msg.ContentHeight
+		if msg.Theme != nil {
+			v.styles = msg.Theme
+		}
 		v.viewport.SetWidth(msg.ContentWidth)
-		v.viewport.SetHeight(msg.ContentHeight)
-		v.viewport.SetContent(v.renderedView().Content)
 	case tea.KeyPressMsg:
 		switch msg.String() {
 		case "-", "d":
@@ -110,6 +123,10 @@ func (v *EntityView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return v, cmd
 }
 
+func (v *EntityView) hasCallback(key string) bool {
+	return slices.ContainsFunc(v.callbacks, func(cb types.KeyCallback) bool { return cb.Key == key })
+}
+
 func (v *EntityView) renderedView() tea.View {
 	var content string
 	var err error
@@ -125,32 +142,17 @@ func (v *EntityView) renderedView() tea.View {
 	case types.CollectionFormatList:
 		fallthrough
 	default:
-		content = "unsupported format"
+		content = i18n.T(i18n.MsgUnsupportedFormat)
 	}
 	if err != nil {
 		v.err = NewErrorView(err, v.styles)
 		return v.err.View()
 	}
 	if content == "" {
-		content = "no data"
-	}
-
-	mdStyles, err := v.styles.GlamourMarkdownStyleJSON()
-	if err != nil {
-		v.err = NewErrorView(err, v.styles)
-		return v.err.View()
-	}
-	renderer, err := glamour.NewTermRenderer(
-		glamour.WithStylesFromJSONBytes([]byte(mdStyles)),
-		glamour.WithPreservedNewLines(),
-		glamour.WithWordWrap(int(math.Floor(float64(v.width)*0.95))),
-	)
-	if err != nil {
-		v.err = NewErrorView(err, v.styles)
-		return v.err.View()
+		content = i18n.T(i18n.MsgNoData)
 	}
 
-	viewStr, err := renderer.Render(content)
+	viewStr, err := v.rendered.render(v.styles, int(math.Floor(float64(v.width)*0.95)), true, content)
 	if err != nil {
 		v.err = NewErrorView(err, v.styles)
 		return v.err.View()
@@ -162,8 +164,16 @@ func (v *EntityView) View() tea.View {
 	if v.err != nil {
 		return v.err.View()
 	}
-	v.viewport.SetContent(v.renderedView().Content)
-	return tea.View{Content: v.viewport.View()}
+	rendered := v.renderedView()
+	if v.err != nil {
+		return rendered
+	}
+	v.viewport.SetHeight(v.height - v.search.height())
+	v.search.render(&v.viewport, rendered.Content, v.styles)
+	if !v.search.active() {
+		return tea.View{Content: v.viewport.View()}
+	}
+	return tea.View{Content: v.viewport.View() + "\n" + v.search.view(v.styles, v.width)}
 }
 
 func (v *EntityView) HelpBindings() []themes.HelpKey {
@@ -177,12 +187,17 @@ func (v *EntityView) HelpBindings() []themes.HelpKey {
 		}
 	}
 	keys = append(keys,
-		themes.HelpKey{Key: "↑/↓", Desc: "scroll"},
-		themes.HelpKey{Key: "d", Desc: "document"},
-		themes.HelpKey{Key: "y", Desc: "yaml"},
-		themes.HelpKey{Key: "j", Desc: "json"},
+		themes.HelpKey{Key: "↑/↓", Desc: i18n.T(i18n.HelpScroll)},
+		themes.HelpKey{Key: "d", Desc: i18n.T(i18n.HelpDocument)},
+		themes.HelpKey{Key: "y", Desc: i18n.T(i18n.HelpYAML)},
+		themes.HelpKey{Key: "j", Desc: i18n.T(i18n.HelpJSON)},
 	)
-	return keys
+	return append(keys, searchHelpKeys()...)
+}
+
+// CapturingInput reports whether the search prompt is open.
+func (v *EntityView) CapturingInput() bool {
+	return v.search.prompting
 }
 
 func (v *EntityView) Type() string {
@@ -7,6 +7,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 
+	"github.com/flowexec/tuikit/i18n"
 	"github.com/flowexec/tuikit/themes"
 	"github.com/flowexec/tuikit/types"
 )
@@ -52,7 +53,7 @@ func (v *ErrorView) Type() string {
 }
 
 func errorString(err error) string {
-	errStr := "!! encountered error !!\n\n"
+	errStr := i18n.T(i18n.MsgEncounteredError) + "\n\n"
 	// split on `:` or ` - ` to print wrapped errors on new lines
 	// TODO: this is a hacky way to handle wrapped errors. Instead a defined error pattern should be enforced
 	parts := strings.Split(err.Error(), ":")
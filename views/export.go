@@ -0,0 +1,372 @@
+package views
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/flowexec/tuikit/i18n"
+	"github.com/flowexec/tuikit/themes"
+	"github.com/flowexec/tuikit/types"
+)
+
+type ExportFormat string
+
+const (
+	ExportCSV      ExportFormat = "csv"
+	ExportTSV      ExportFormat = "tsv"
+	ExportJSON     ExportFormat = "json"
+	ExportMarkdown ExportFormat = "markdown"
+)
+
+// ParseExportFormat returns the export format for a format name or file extension,
+// e.g. "csv", "md" or ".json".
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), ".")) {
+	case "csv":
+		return ExportCSV, nil
+	case "tsv", "tab":
+		return ExportTSV, nil
+	case "json":
+		return ExportJSON, nil
+	case "md", "markdown":
+		return ExportMarkdown, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q", s)
+	}
+}
+
+// ExportOptions configures how rows are exported.
+type ExportOptions struct {
+	Format ExportFormat
+	// IncludeChildren exports child rows. They are nested under "children" in JSON
+	// and follow their parent in the other formats.
+	IncludeChildren bool
+	// Raw exports cell values without applying column formatters.
+	Raw bool
+	// Theme is passed to column formatters. The Everforest theme is used when nil.
+	Theme themes.Theme
+}
+
+// ExportRows writes rows in the given format using the column titles as headers.
+// Column formatters are applied unless opts.Raw is set; styling is always removed.
+// It can be used outside of a running program, e.g. for non-interactive CLI output.
+func ExportRows(w io.Writer, columns []TableColumn, rows []TableRow, opts ExportOptions) error {
+	switch opts.Format {
+	case ExportCSV, ExportTSV:
+		return exportDelimited(w, columns, rows, opts)
+	case ExportJSON:
+		return exportJSON(w, columns, rows, opts)
+	case ExportMarkdown:
+		return exportMarkdown(w, columns, rows, opts)
+	default:
+		return fmt.Errorf("unsupported export format %q", opts.Format)
+	}
+}
+
+// ExportCollection writes the items of a collection in the given format.
+func ExportCollection(w io.Writer, collection types.Collection, opts ExportOptions) error {
+	return ExportRows(w, collectionExportColumns(), collectionExportRows(collection.Items()), opts)
+}
+
+func exportCell(col TableColumn, value string, opts ExportOptions) string {
+	if !opts.Raw && col.Format != nil {
+		theme := opts.Theme
+		if theme == nil {
+			theme = themes.EverforestTheme()
+		}
+		value = col.Format(value, theme)
+	}
+	return ansi.Strip(value)
+}
+
+func exportRecord(columns []TableColumn, row TableRow, opts ExportOptions) []string {
+	record := make([]string, len(columns))
+	for i, col := range columns {
+		record[i] = exportCell(col, cellValue(row.Data, i), opts)
+	}
+	return record
+}
+
+// flattenRows returns the rows with their children following them, depth first.
+func flattenRows(rows []TableRow, includeChildren bool) []TableRow {
+	flat := make([]TableRow, 0, len(rows))
+	for _, row := range rows {
+		flat = append(flat, row)
+		if includeChildren {
+			flat = append(flat, flattenRows(row.Children, true)...)
+		}
+	}
+	return flat
+}
+
+func exportDelimited(w io.Writer, columns []TableColumn, rows []TableRow, opts ExportOptions) error {
+	cw := csv.NewWriter(w)
+	if opts.Format == ExportTSV {
+		cw.Comma = '\t'
+	}
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.Title
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range flattenRows(rows, opts.IncludeChildren) {
+		if err := cw.Write(exportRecord(columns, row, opts)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonRecord marshals a row as an object whose keys keep the column order.
+type jsonRecord struct {
+	keys     []string
+	values   []string
+	children []jsonRecord
+}
+
+func (r jsonRecord) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	if r.children != nil {
+		children, err := json.Marshal(r.children)
+		if err != nil {
+			return nil, err
+		}
+		if len(r.keys) > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(`"children":`)
+		buf.Write(children)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func jsonRecords(columns []TableColumn, rows []TableRow, opts ExportOptions) []jsonRecord {
+	keys := make([]string, len(columns))
+	for i, col := range columns {
+		keys[i] = col.Title
+	}
+	records := make([]jsonRecord, 0, len(rows))
+	for _, row := range rows {
+		record := jsonRecord{keys: keys, values: exportRecord(columns, row, opts)}
+		if opts.IncludeChildren && len(row.Children) > 0 {
+			record.children = jsonRecords(columns, row.Children, opts)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func exportJSON(w io.Writer, columns []TableColumn, rows []TableRow, opts ExportOptions) error {
+	data, err := json.MarshalIndent(jsonRecords(columns, rows, opts), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+func exportMarkdown(w io.Writer, columns []TableColumn, rows []TableRow, opts ExportOptions) error {
+	var b strings.Builder
+	header := make([]string, len(columns))
+	separator := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = escapeMarkdownCell(col.Title)
+		switch col.Align {
+		case AlignRight:
+			separator[i] = "---:"
+		case AlignCenter:
+			separator[i] = ":---:"
+		default:
+			separator[i] = "---"
+		}
+	}
+	writeMarkdownRow(&b, header)
+	writeMarkdownRow(&b, separator)
+	for _, row := range flattenRows(rows, opts.IncludeChildren) {
+		record := exportRecord(columns, row, opts)
+		for i := range record {
+			record[i] = escapeMarkdownCell(record[i])
+		}
+		writeMarkdownRow(&b, record)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	b.WriteString("| ")
+	b.WriteString(strings.Join(cells, " | "))
+	b.WriteString(" |\n")
+}
+
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func countRows(rows []TableRow, includeChildren bool) int {
+	return len(flattenRows(rows, includeChildren))
+}
+
+func collectionExportColumns() []TableColumn {
+	return []TableColumn{{Title: "ID"}, {Title: "Header"}, {Title: "SubHeader"}, {Title: "Description"}}
+}
+
+func collectionExportRows(items []*types.EntityInfo) []TableRow {
+	rows := make([]TableRow, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, TableRow{Data: []string{item.ID, item.Header, item.SubHeader, item.Desc}})
+	}
+	return rows
+}
+
+// exportJob writes a snapshot of a view's rows in the given format and returns the
+// number of rows written. It is run outside of the update loop.
+type exportJob func(w io.Writer, format ExportFormat) (int, error)
+
+// exportPrompt asks for the destination of an export. A file path exports to that
+// file, using its extension as the format; a bare format name copies to the clipboard.
+// For views with child rows, tab toggles whether they are exported.
+type exportPrompt struct {
+	input    textinput.Model
+	active   bool
+	nested   bool // the view has child rows
+	children bool
+}
+
+func newExportPrompt(nested bool) exportPrompt {
+	in := textinput.New()
+	in.Prompt = i18n.T(i18n.MsgExportPrompt)
+	in.Placeholder = i18n.T(i18n.MsgExportPlaceholder)
+	in.CharLimit = 256
+	return exportPrompt{input: in, nested: nested, children: nested}
+}
+
+func (p *exportPrompt) open() tea.Cmd {
+	p.active = true
+	p.input.SetValue("")
+	return p.input.Focus()
+}
+
+func (p *exportPrompt) close() {
+	p.active = false
+	p.input.Blur()
+}
+
+// update handles a key while the prompt is open. snapshot is called when the
+// destination is submitted, with whether child rows are exported.
+func (p *exportPrompt) update(msg tea.KeyPressMsg, snapshot func(children bool) exportJob) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		p.close()
+		return nil
+	case "tab":
+		if p.nested {
+			p.children = !p.children
+			return nil
+		}
+	case types.KeyEnter:
+		dest := strings.TrimSpace(p.input.Value())
+		p.close()
+		if dest == "" {
+			return nil
+		}
+		return runExport(dest, snapshot(p.children))
+	}
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return cmd
+}
+
+func (p *exportPrompt) view(theme themes.Theme, width int) string {
+	content := p.input.View()
+	if p.nested {
+		hint := i18n.T(i18n.MsgExportNoChildren)
+		if p.children {
+			hint = i18n.T(i18n.MsgExportChildren)
+		}
+		content += lipgloss.NewStyle().Foreground(theme.ColorPalette().GrayColor()).Render("  " + hint)
+	}
+	return lipgloss.NewStyle().
+		Foreground(theme.ColorPalette().SecondaryColor()).
+		Width(width).
+		MaxHeight(1).
+		Render(content)
+}
+
+func runExport(dest string, job exportJob) tea.Cmd {
+	if format, err := ParseExportFormat(dest); err == nil {
+		return func() tea.Msg {
+			var buf bytes.Buffer
+			n, err := job(&buf, format)
+			if err != nil {
+				return exportFailed(err)
+			}
+			return tea.BatchMsg{
+				tea.SetClipboard(buf.String()),
+				noticeCmd(i18n.Tf(i18n.MsgExportCopied, n), themes.OutputLevelSuccess),
+			}
+		}
+	}
+	return func() tea.Msg {
+		format, err := ParseExportFormat(filepath.Ext(dest))
+		if err != nil {
+			return exportFailed(err)
+		}
+		f, err := os.Create(dest)
+		if err != nil {
+			return exportFailed(err)
+		}
+		n, err := job(f, format)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return exportFailed(err)
+		}
+		return types.NoticeMsg{Text: i18n.Tf(i18n.MsgExported, n, dest), Level: themes.OutputLevelSuccess}
+	}
+}
+
+// exportFailed reports an export error as a toast so the view stays open.
+func exportFailed(err error) tea.Msg {
+	return types.NoticeMsg{Text: err.Error(), Level: themes.OutputLevelError}
+}
+
+func noticeCmd(text string, lvl themes.OutputLevel) tea.Cmd {
+	return func() tea.Msg {
+		return types.NoticeMsg{Text: text, Level: lvl}
+	}
+}
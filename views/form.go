@@ -32,21 +32,54 @@ const (
 )
 
 type FormField struct {
-	Group uint
-	Type  FormFieldType
-	Key   string
+	Group uint          `json:"group,omitempty" yaml:"group,omitempty"`
+	Type  FormFieldType `json:"type,omitempty"  yaml:"type,omitempty"`
+	Key   string        `json:"key"             yaml:"key"`
 
-	Default        string
-	Required       bool
-	ValidationExpr string
-	Title          string
-	Description    string
-	Placeholder    string
+	Default        string `json:"default,omitempty"        yaml:"default,omitempty"`
+	Required       bool   `json:"required,omitempty"       yaml:"required,omitempty"`
+	ValidationExpr string `json:"validationExpr,omitempty" yaml:"validationExpr,omitempty"`
+	Title          string `json:"title,omitempty"          yaml:"title,omitempty"`
+	Description    string `json:"description,omitempty"    yaml:"description,omitempty"`
+	Placeholder    string `json:"placeholder,omitempty"    yaml:"placeholder,omitempty"`
 
 	value     string
 	confirmed bool
 }
 
+var formFieldTypeNames = map[FormFieldType]string{
+	PromptTypeText:      "text",
+	PromptTypeMasked:    "masked",
+	PromptTypeMultiline: "multiline",
+	PromptTypeConfirm:   "confirm",
+}
+
+func (t FormFieldType) String() string {
+	if name, ok := formFieldTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", uint(t))
+}
+
+// MarshalText encodes the field type by name so that forms can be declared in YAML or JSON.
+func (t FormFieldType) MarshalText() ([]byte, error) {
+	name, ok := formFieldTypeNames[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown field type: %d", uint(t))
+	}
+	return []byte(name), nil
+}
+
+func (t *FormFieldType) UnmarshalText(text []byte) error {
+	for ft, name := range formFieldTypeNames {
+		if strings.EqualFold(name, string(text)) {
+			*t = ft
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown field type: %s", text)
+}
+
 func (f *FormField) Set(val string) {
 	//nolint:exhaustive
 	switch f.Type {
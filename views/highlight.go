@@ -0,0 +1,143 @@
+package views
+
+import (
+	"image/color"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+
+	"github.com/flowexec/tuikit/themes"
+)
+
+// codeLexer returns the chroma lexer for a language name, file name or extension, or
+// nil when the language is empty or unknown.
+func codeLexer(language string) chroma.Lexer {
+	if language == "" {
+		return nil
+	}
+	lexer := lexers.Get(language)
+	if lexer == nil {
+		lexer = lexers.Match(language)
+	}
+	if lexer == nil {
+		lexer = lexers.Match("file." + strings.TrimPrefix(language, "."))
+	}
+	if lexer == nil {
+		return nil
+	}
+	return chroma.Coalesce(lexer)
+}
+
+// codeToken is a run of code in a single style.
+type codeToken struct {
+	text  string
+	style lipgloss.Style
+}
+
+// highlightLines returns the lines of the code colored with the theme's chroma code
+// style. The lines are returned uncolored when no lexer matches the language, which
+// may be a language name, file name or extension.
+func highlightLines(code, language string, theme themes.Theme) []string {
+	tokens := highlightTokens(code, language, theme)
+	lines := make([]string, 0, len(tokens))
+	for _, line := range tokens {
+		lines = append(lines, renderCodeTokens(line, nil))
+	}
+	return lines
+}
+
+// renderCodeTokens renders a line of tokens, with the background when it is set.
+func renderCodeTokens(tokens []codeToken, background color.Color) string {
+	var line strings.Builder
+	for _, token := range tokens {
+		style := token.style
+		if background != nil {
+			style = style.Background(background)
+		}
+		line.WriteString(style.Render(token.text))
+	}
+	return line.String()
+}
+
+// highlightTokens splits each line of the code into tokens styled with the theme's
+// chroma code style. Each line is a single unstyled token when no lexer matches the
+// language.
+func highlightTokens(code, language string, theme themes.Theme) [][]codeToken {
+	plain := strings.Split(code, "\n")
+	lines := make([][]codeToken, 0, len(plain))
+	for _, line := range plain {
+		lines = append(lines, plainTokens(line))
+	}
+	lexer := codeLexer(language)
+	if lexer == nil || theme == nil {
+		return lines
+	}
+	iterator, err := lexer.Tokenise(nil, code)
+	if err != nil {
+		return lines
+	}
+	style := styles.Get(theme.ColorPalette().ChromaCodeStyle)
+
+	highlighted := make([][]codeToken, 0, len(plain))
+	var line []codeToken
+	for _, token := range iterator.Tokens() {
+		tokenStyle := chromaStyle(style.Get(token.Type))
+		parts := strings.Split(token.Value, "\n")
+		for n, part := range parts {
+			if n > 0 {
+				highlighted = append(highlighted, line)
+				line = nil
+			}
+			if part != "" {
+				line = append(line, codeToken{text: part, style: tokenStyle})
+			}
+		}
+	}
+	highlighted = append(highlighted, line)
+	// Lexers may add a trailing newline; fall back to plain lines if the line count
+	// doesn't match.
+	if len(highlighted) == len(plain)+1 && len(highlighted[len(highlighted)-1]) == 0 {
+		highlighted = highlighted[:len(plain)]
+	}
+	if len(highlighted) != len(plain) {
+		return lines
+	}
+	return highlighted
+}
+
+func plainTokens(line string) []codeToken {
+	if line == "" {
+		return nil
+	}
+	return []codeToken{{text: line, style: lipgloss.NewStyle()}}
+}
+
+// codeLineHighlight returns the background of highlighted lines in the theme's chroma
+// code style, or the theme's border color when the style has none.
+func codeLineHighlight(theme themes.Theme) color.Color {
+	cp := theme.ColorPalette()
+	if entry := styles.Get(cp.ChromaCodeStyle).Get(chroma.LineHighlight); entry.Background.IsSet() {
+		return lipgloss.Color(entry.Background.String())
+	}
+	return cp.BorderColor()
+}
+
+func chromaStyle(entry chroma.StyleEntry) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	if entry.Colour.IsSet() {
+		style = style.Foreground(lipgloss.Color(entry.Colour.String()))
+	}
+	if entry.Bold == chroma.Yes {
+		style = style.Bold(true)
+	}
+	if entry.Italic == chroma.Yes {
+		style = style.Italic(true)
+	}
+	if entry.Underline == chroma.Yes {
+		style = style.Underline(true)
+	}
+	return style
+}
@@ -6,6 +6,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 
+	"github.com/flowexec/tuikit/i18n"
 	"github.com/flowexec/tuikit/themes"
 	"github.com/flowexec/tuikit/types"
 )
@@ -185,10 +186,10 @@ func (l *Library) HelpBindings() []themes.HelpKey {
 
 	// Library navigation keys
 	if l.pageIndex < len(l.pages)-1 {
-		keys = append(keys, themes.HelpKey{Key: "enter/→", Desc: "drill down"})
+		keys = append(keys, themes.HelpKey{Key: "enter/→", Desc: i18n.T(i18n.HelpDrillDown)})
 	}
 	if l.pageIndex > 0 {
-		keys = append(keys, themes.HelpKey{Key: "esc/←", Desc: "go back"})
+		keys = append(keys, themes.HelpKey{Key: "esc/←", Desc: i18n.T(i18n.HelpGoBack)})
 	}
 
 	return keys
@@ -212,6 +213,14 @@ func (l *Library) CapturingInput() bool {
 	return false
 }
 
+// HeaderSegments forwards the contextual header segments of the active page.
+func (l *Library) HeaderSegments() []themes.HeaderSegment {
+	if hs, ok := l.activeView.(interface{ HeaderSegments() []themes.HeaderSegment }); ok {
+		return hs.HeaderSegments()
+	}
+	return nil
+}
+
 func (l *Library) activatePage(index int) {
 	l.pageIndex = index
 	if len(l.selections) > index {
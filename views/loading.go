@@ -7,12 +7,16 @@ import (
 	"charm.land/bubbles/v2/spinner"
 	tea "charm.land/bubbletea/v2"
 
+	"github.com/flowexec/tuikit/i18n"
 	"github.com/flowexec/tuikit/themes"
 )
 
 const (
 	LoadingViewType = "loading"
-	DefaultLoading  = "loading..."
+	// DefaultLoading is the English loading message.
+	//
+	// Deprecated: the loading view falls back to the i18n.MsgLoading catalog message.
+	DefaultLoading = "loading..."
 )
 
 type LoadingView struct {
@@ -47,7 +51,7 @@ func (v *LoadingView) View() tea.View {
 	defer v.mu.RUnlock()
 	msg := v.msg
 	if msg == "" {
-		msg = DefaultLoading
+		msg = i18n.T(i18n.MsgLoading)
 	}
 	txt := fmt.Sprintf("\n\n  %s %s\n\n", v.spinner.View(), v.theme.RenderInfo(msg))
 	return tea.View{Content: txt}
@@ -2,41 +2,73 @@ package views
 
 import (
 	"math"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
-	"charm.land/glamour/v2"
+	"charm.land/lipgloss/v2"
 
+	"github.com/flowexec/tuikit/i18n"
 	"github.com/flowexec/tuikit/themes"
 	"github.com/flowexec/tuikit/types"
 )
 
+// MarkdownView renders a markdown document. Its headings are listed in a table of
+// contents sidebar, and its links can be focused and followed; relative markdown files
+// open in a new MarkdownView pushed onto the container's navigation.
 type MarkdownView struct {
 	content       string
+	path          string
+	baseDir       string
 	viewport      viewport.Model
 	err           *ErrorView
+	rendered      markdownCache
+	search        contentSearch
 	theme         themes.Theme
 	width, height int
 	mu            sync.RWMutex
+
+	outline      markdownOutline
+	decorated    string
+	decoratedFor string
+	focus        int
+	showContents bool
+	// anchor is the heading to scroll to once the document is rendered.
+	anchor string
+
+	watch        bool
+	fileErr      error
+	fileStat     fileStat
+	poll         int
+	pollInterval time.Duration
 }
 
 func NewMarkdownView(state *types.RenderState, content string) *MarkdownView {
 	vp := viewport.New(viewport.WithWidth(state.ContentWidth), viewport.WithHeight(state.ContentHeight))
-	vp.Style = state.Theme.EntityViewStyle().Width(state.ContentWidth).Height(state.ContentHeight)
+	vp.Style = state.Theme.EntityViewStyle()
 	return &MarkdownView{
 		content:  content,
 		viewport: vp,
+		search:   newContentSearch(),
 		theme:    state.Theme,
+		width:    state.ContentWidth,
+		height:   state.ContentHeight,
+		outline:  parseMarkdownOutline(content),
+		focus:    -1,
 	}
 }
 
 func (v *MarkdownView) Init() tea.Cmd {
 	v.mu.Lock()
 	defer v.mu.Unlock()
-	return v.viewport.Init()
+	v.poll++
+	return tea.Batch(v.viewport.Init(), v.pollFile())
 }
 
+//nolint:gocognit
 func (v *MarkdownView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
@@ -44,18 +76,51 @@ func (v *MarkdownView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if v.err != nil {
 		return v.err.Update(msg)
 	}
+	if msg, ok := msg.(markdownFileMsg); ok && msg.view == v {
+		return v, v.handleFileMsg(msg)
+	}
+
 	switch msg := msg.(type) {
-	case types.RenderState:
+	case *types.RenderState:
 		v.width = msg.ContentWidth
 		v.height = msg.ContentHeight
+		if msg.Theme != nil {
+			v.theme = msg.Theme
+		}
 		v.viewport.SetWidth(v.width)
-		v.viewport.SetHeight(v.height)
 	case tea.KeyPressMsg:
+		if cmd, ok := v.search.update(msg); ok {
+			return v, cmd
+		}
 		switch msg.String() {
 		case types.KeyUp:
 			v.viewport.ScrollUp(1)
 		case types.KeyDown:
 			v.viewport.ScrollDown(1)
+		case "t":
+			v.showContents = !v.showContents
+			return v, nil
+		case "]":
+			if line, ok := v.outline.headingLine(v.viewport.YOffset(), 1); ok {
+				v.viewport.SetYOffset(line)
+			}
+			return v, nil
+		case "[":
+			if line, ok := v.outline.headingLine(v.viewport.YOffset(), -1); ok {
+				v.viewport.SetYOffset(line)
+			}
+			return v, nil
+		case "tab":
+			v.focusLink(1)
+			return v, nil
+		case "shift+tab":
+			v.focusLink(-1)
+			return v, nil
+		case types.KeyEnter:
+			if v.focus >= 0 {
+				return v, v.openLink(v.outline.links[v.focus])
+			}
+			return v, nil
 		}
 	}
 	var cmd tea.Cmd
@@ -63,41 +128,104 @@ func (v *MarkdownView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return v, cmd
 }
 
+// focusLink moves the focus to the next link, or to the previous one when delta is
+// negative, and scrolls it into view.
+func (v *MarkdownView) focusLink(delta int) {
+	n := len(v.outline.links)
+	if n == 0 {
+		return
+	}
+	switch {
+	case v.focus < 0 && delta < 0:
+		v.focus = n - 1
+	case v.focus < 0:
+		v.focus = 0
+	default:
+		v.focus = ((v.focus+delta)%n + n) % n
+	}
+	if link := v.outline.links[v.focus]; link.line >= 0 {
+		v.viewport.EnsureVisible(link.line, link.start, link.end)
+	}
+}
+
 func (v *MarkdownView) View() tea.View {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
-	mdStyles, err := v.theme.GlamourMarkdownStyleJSON()
-	if err != nil {
-		v.err = NewErrorView(err, v.theme)
-		return v.err.View()
-	}
 	if v.err != nil {
 		return v.err.View()
 	}
-	renderer, err := glamour.NewTermRenderer(
-		glamour.WithStylesFromJSONBytes([]byte(mdStyles)),
-		glamour.WithPreservedNewLines(),
-		glamour.WithWordWrap(int(math.Floor(float64(v.width)*0.95))),
-	)
+
+	docWidth, sidebarWidth := v.width, 0
+	if v.showContents && len(v.outline.headings) > 0 {
+		sidebarWidth = min(30, v.width/3)
+		docWidth -= sidebarWidth
+	}
+	viewStr, err := v.rendered.render(v.theme, int(math.Floor(float64(docWidth)*0.95)), true, v.content)
 	if err != nil {
 		v.err = NewErrorView(err, v.theme)
 		return v.err.View()
 	}
+	if viewStr != v.outline.rendered {
+		v.outline.locate(viewStr)
+		v.decoratedFor = ""
+	}
+	if decoratedFor := strconv.Itoa(v.focus); decoratedFor != v.decoratedFor {
+		v.decorated = strings.Join(v.outline.decorate(v.focus, v.theme), "\n")
+		v.decoratedFor = decoratedFor
+	}
 
-	viewStr, err := renderer.Render(v.content)
-	if err != nil {
-		v.err = NewErrorView(err, v.theme)
-		return v.err.View()
+	height := v.height - v.search.height()
+	if v.fileErr != nil {
+		height--
+	}
+	v.viewport.SetWidth(docWidth)
+	v.viewport.SetHeight(height)
+	v.search.render(&v.viewport, v.decorated, v.theme)
+	if v.anchor != "" {
+		if line, ok := v.outline.anchorLine(v.anchor); ok {
+			v.viewport.SetYOffset(line)
+		}
+		v.anchor = ""
 	}
-	v.viewport.SetContent(viewStr)
-	return tea.View{Content: v.viewport.View()}
+
+	content := v.viewport.View()
+	if sidebarWidth > 0 {
+		current := v.outline.currentHeading(v.viewport.YOffset())
+		content = lipgloss.JoinHorizontal(lipgloss.Top,
+			v.outline.renderContents(v.theme, current, sidebarWidth, height), content)
+	}
+	if v.fileErr != nil {
+		content = renderFileError(v.theme, v.fileErr, v.width) + "\n" + content
+	}
+	if !v.search.active() {
+		return tea.View{Content: content}
+	}
+	return tea.View{Content: content + "\n" + v.search.view(v.theme, v.width)}
 }
 
 func (v *MarkdownView) HelpBindings() []themes.HelpKey {
-	return []themes.HelpKey{
-		{Key: "↑/↓", Desc: "scroll"},
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	keys := []themes.HelpKey{
+		{Key: "↑/↓", Desc: i18n.T(i18n.HelpScroll)},
+		{Key: "t", Desc: i18n.T(i18n.HelpContents)},
+		{Key: "]/[", Desc: i18n.T(i18n.HelpNextHeading)},
 	}
+	if len(v.outline.links) > 0 {
+		keys = append(keys,
+			themes.HelpKey{Key: "tab/shift+tab", Desc: i18n.T(i18n.HelpNextLink)},
+			themes.HelpKey{Key: "enter", Desc: i18n.T(i18n.HelpOpenLink)},
+		)
+	}
+	return append(keys, searchHelpKeys()...)
+}
+
+// CapturingInput reports whether the search prompt is open.
+func (v *MarkdownView) CapturingInput() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.search.prompting
 }
 
 func (v *MarkdownView) Type() string {
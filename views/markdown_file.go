@@ -0,0 +1,139 @@
+package views
+
+import (
+	"os"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/flowexec/tuikit/i18n"
+	"github.com/flowexec/tuikit/themes"
+	"github.com/flowexec/tuikit/types"
+)
+
+// DefaultMarkdownPollInterval is how often a file-backed MarkdownView checks its file
+// for changes.
+const DefaultMarkdownPollInterval = time.Second
+
+// markdownFileMsg is the result of checking the file of a MarkdownView. Content is
+// only read when the file changed since the last check.
+type markdownFileMsg struct {
+	view    *MarkdownView
+	poll    int
+	stat    fileStat
+	content string
+	changed bool
+	err     error
+}
+
+// fileStat is what tells a file change apart.
+type fileStat struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewMarkdownViewFromFile creates a MarkdownView showing the markdown file at path. The
+// file is checked for changes while the view is shown and rendered again, at the same
+// scroll position, when it changes. Errors reading the file are shown in a banner
+// above the last content read.
+func NewMarkdownViewFromFile(state *types.RenderState, path string) *MarkdownView {
+	v := NewMarkdownView(state, "")
+	v.path = path
+	v.watch = true
+	if info, err := os.Stat(path); err != nil {
+		v.fileErr = err
+	} else if content, err := os.ReadFile(path); err != nil {
+		v.fileErr = err
+	} else {
+		v.setContent(string(content))
+		v.fileStat = fileStat{modTime: info.ModTime(), size: info.Size()}
+	}
+	return v
+}
+
+// SetContent replaces the document, keeping the scroll position.
+func (v *MarkdownView) SetContent(content string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.setContent(content)
+}
+
+func (v *MarkdownView) setContent(content string) {
+	v.content = content
+	v.outline = parseMarkdownOutline(content)
+	v.decoratedFor = ""
+	if v.focus >= len(v.outline.links) {
+		v.focus = -1
+	}
+}
+
+// SetPollInterval sets how often the file of a file-backed view is checked for
+// changes. It applies from the next check.
+func (v *MarkdownView) SetPollInterval(interval time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.pollInterval = interval
+}
+
+// pollFile checks the file for changes after the poll interval. Each call to Init
+// starts a new poll, and the results of earlier polls are ignored.
+func (v *MarkdownView) pollFile() tea.Cmd {
+	if !v.watch {
+		return nil
+	}
+	interval := v.pollInterval
+	if interval <= 0 {
+		interval = DefaultMarkdownPollInterval
+	}
+	path, last, poll := v.path, v.fileStat, v.poll
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		msg := markdownFileMsg{view: v, poll: poll, stat: last}
+		info, err := os.Stat(path)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		msg.stat = fileStat{modTime: info.ModTime(), size: info.Size()}
+		if msg.stat == last {
+			return msg
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		msg.content, msg.changed = string(content), true
+		return msg
+	})
+}
+
+func (v *MarkdownView) handleFileMsg(msg markdownFileMsg) tea.Cmd {
+	if msg.poll != v.poll {
+		return nil
+	}
+	v.fileErr = msg.err
+	if msg.err != nil {
+		// Read the file again once it is back.
+		v.fileStat = fileStat{}
+	} else {
+		v.fileStat = msg.stat
+	}
+	if msg.changed {
+		v.setContent(msg.content)
+	}
+	return v.pollFile()
+}
+
+// renderFileError renders the banner shown when the file can't be read.
+func renderFileError(theme themes.Theme, err error, width int) string {
+	cp := theme.ColorPalette()
+	text := ansi.Truncate(i18n.Tf(i18n.MsgReloadFailed, err), max(width-2, 1), "…")
+	return lipgloss.NewStyle().
+		Foreground(cp.BlackColor()).
+		Background(cp.ErrorColor()).
+		Padding(0, 1).
+		Width(width).
+		Render(text)
+}
@@ -0,0 +1,319 @@
+package views
+
+import (
+	"cmp"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/flowexec/tuikit/i18n"
+	"github.com/flowexec/tuikit/themes"
+	"github.com/flowexec/tuikit/types"
+)
+
+var (
+	markdownHeadingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+	markdownLinkPattern     = regexp.MustCompile(`(!?)\[([^\]]*)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	markdownAutolinkPattern = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	markdownInlinePattern   = regexp.MustCompile("[*_`~]")
+)
+
+// markdownHeading is a heading of a markdown document. Line is its line in the
+// rendered document, or -1 when it wasn't found there.
+type markdownHeading struct {
+	level int
+	text  string
+	line  int
+}
+
+// markdownLink is a link of a markdown document. Line, start and end locate its URL in
+// the rendered document; line is -1 when it wasn't found there.
+type markdownLink struct {
+	text, target string
+	line         int
+	start, end   int
+}
+
+// markdownOutline holds the headings and links of a document and where they were
+// rendered.
+type markdownOutline struct {
+	rendered string
+	headings []markdownHeading
+	links    []markdownLink
+}
+
+// parseMarkdownOutline reads the headings and links of a markdown document, skipping
+// fenced code blocks.
+func parseMarkdownOutline(content string) markdownOutline {
+	var outline markdownOutline
+	fenced := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+		if m := markdownHeadingPattern.FindStringSubmatch(trimmed); m != nil {
+			outline.headings = append(outline.headings, markdownHeading{
+				level: len(m[1]),
+				text:  plainMarkdownText(m[2]),
+				line:  -1,
+			})
+		}
+
+		type found struct {
+			at   int
+			link markdownLink
+		}
+		var links []found
+		for _, m := range markdownLinkPattern.FindAllStringSubmatchIndex(line, -1) {
+			if m[3] > m[2] { // images
+				continue
+			}
+			links = append(links, found{m[0], markdownLink{text: line[m[4]:m[5]], target: line[m[6]:m[7]], line: -1}})
+		}
+		for _, m := range markdownAutolinkPattern.FindAllStringSubmatchIndex(line, -1) {
+			target := line[m[2]:m[3]]
+			links = append(links, found{m[0], markdownLink{text: target, target: target, line: -1}})
+		}
+		slices.SortFunc(links, func(a, b found) int { return cmp.Compare(a.at, b.at) })
+		for _, l := range links {
+			outline.links = append(outline.links, l.link)
+		}
+	}
+	return outline
+}
+
+// plainMarkdownText removes inline formatting and link targets from markdown text.
+func plainMarkdownText(text string) string {
+	text = markdownLinkPattern.ReplaceAllString(text, "$2")
+	return strings.Join(strings.Fields(markdownInlinePattern.ReplaceAllString(text, "")), " ")
+}
+
+// locate finds the headings and links in the rendered document. Both are searched in
+// document order, so repeated headings and links are told apart.
+func (o *markdownOutline) locate(rendered string) {
+	o.rendered = rendered
+	lines := strings.Split(ansi.Strip(rendered), "\n")
+
+	normalized := make([]string, len(lines))
+	for n, line := range lines {
+		normalized[n] = strings.Join(strings.Fields(line), " ")
+	}
+	next := 0
+	for i := range o.headings {
+		h := &o.headings[i]
+		// Headings end their line, unlike paragraphs mentioning them; headings ending
+		// with a link are matched anywhere in the line.
+		h.line = slices.IndexFunc(normalized[next:], func(line string) bool { return strings.HasSuffix(line, h.text) })
+		if h.line < 0 {
+			h.line = slices.IndexFunc(normalized[next:], func(line string) bool { return strings.Contains(line, h.text) })
+		}
+		if h.line >= 0 {
+			h.line += next
+			next = h.line + 1
+		}
+	}
+
+	line, col := 0, 0
+	for i := range o.links {
+		link := &o.links[i]
+		link.line = -1
+		for n := line; n < len(lines); n++ {
+			from := 0
+			if n == line {
+				from = min(col, len(lines[n]))
+			}
+			at := strings.Index(lines[n][from:], link.target)
+			if at < 0 {
+				continue
+			}
+			at += from
+			link.line = n
+			link.start = ansi.StringWidth(lines[n][:at])
+			link.end = link.start + ansi.StringWidth(link.target)
+			line, col = n, at+len(link.target)
+			break
+		}
+	}
+}
+
+// decorate returns the rendered lines with each external link emitted as an OSC 8
+// hyperlink and the focused link highlighted.
+func (o *markdownOutline) decorate(focus int, theme themes.Theme) []string {
+	lines := strings.Split(o.rendered, "\n")
+	cp := theme.ColorPalette()
+	focusStyle := lipgloss.NewStyle().Foreground(cp.BlackColor()).Background(cp.SecondaryColor())
+	// Links are decorated from the end, so that the positions of earlier links on the
+	// same line still hold.
+	for i := len(o.links) - 1; i >= 0; i-- {
+		link := o.links[i]
+		if link.line < 0 {
+			continue
+		}
+		line := lines[link.line]
+		if i == focus {
+			line = lipgloss.StyleRanges(line, lipgloss.NewRange(link.start, link.end, focusStyle))
+		}
+		if isExternalLink(link.target) {
+			line = ansi.Cut(line, 0, link.start) +
+				ansi.SetHyperlink(link.target) + ansi.Cut(line, link.start, link.end) + ansi.ResetHyperlink() +
+				ansi.TruncateLeft(line, link.end, "")
+		}
+		lines[link.line] = line
+	}
+	return lines
+}
+
+// currentHeading returns the index of the heading of the section shown at the top of
+// the view, or -1 before the first heading.
+func (o *markdownOutline) currentHeading(offset int) int {
+	current := -1
+	for i, h := range o.headings {
+		if h.line >= 0 && h.line <= offset {
+			current = i
+		}
+	}
+	return current
+}
+
+// headingLine returns the line of the next heading below the offset, or of the
+// previous heading above it when delta is negative. It returns false when there is
+// none.
+func (o *markdownOutline) headingLine(offset, delta int) (int, bool) {
+	if delta > 0 {
+		for _, h := range o.headings {
+			if h.line > offset {
+				return h.line, true
+			}
+		}
+		return 0, false
+	}
+	for i := len(o.headings) - 1; i >= 0; i-- {
+		if h := o.headings[i]; h.line >= 0 && h.line < offset {
+			return h.line, true
+		}
+	}
+	return 0, false
+}
+
+// anchorLine returns the line of the heading with the anchor, e.g. "getting-started".
+func (o *markdownOutline) anchorLine(anchor string) (int, bool) {
+	for _, h := range o.headings {
+		if h.line >= 0 && headingAnchor(h.text) == anchor {
+			return h.line, true
+		}
+	}
+	return 0, false
+}
+
+func headingAnchor(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r == ' ' || r == '-':
+			b.WriteRune('-')
+		case r == '_' || ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') || r > 127:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isExternalLink(target string) bool {
+	u, err := url.Parse(target)
+	return err == nil && u.Scheme != ""
+}
+
+// renderContents renders the table of contents sidebar, highlighting the current
+// heading.
+func (o *markdownOutline) renderContents(theme themes.Theme, current, width, height int) string {
+	cp := theme.ColorPalette()
+	textWidth := max(width-2, 1)
+	lines := []string{
+		lipgloss.NewStyle().Foreground(cp.PrimaryColor()).Bold(true).Render(i18n.T(i18n.MsgContents)),
+	}
+	for i, h := range o.headings {
+		text := ansi.Truncate(strings.Repeat("  ", h.level-1)+h.text, textWidth, "…")
+		style := lipgloss.NewStyle().Foreground(cp.BodyColor())
+		if i == current {
+			style = style.Foreground(cp.SecondaryColor()).Bold(true)
+		}
+		lines = append(lines, style.Render(text))
+	}
+	if len(lines) > height {
+		// Keep the current heading in view.
+		start := min(max(current+1-height/2, 0), len(lines)-height)
+		lines = lines[start : start+height]
+	}
+	return lipgloss.NewStyle().
+		Width(width).
+		Height(height).
+		MaxHeight(height).
+		PaddingRight(1).
+		Border(lipgloss.NormalBorder(), false, true, false, false).
+		BorderForeground(cp.BorderColor()).
+		Render(strings.Join(lines, "\n"))
+}
+
+// openLink follows the focused link. Anchors scroll to their heading, relative
+// markdown files open in a file-backed view pushed onto the container's navigation
+// and other targets are copied to the clipboard.
+func (v *MarkdownView) openLink(link markdownLink) tea.Cmd {
+	target, anchor, _ := strings.Cut(link.target, "#")
+	if target == "" {
+		if line, ok := v.outline.anchorLine(anchor); ok {
+			v.viewport.SetYOffset(line)
+		}
+		return nil
+	}
+	if isExternalLink(link.target) || !strings.EqualFold(filepath.Ext(target), ".md") {
+		return tea.Batch(
+			tea.SetClipboard(link.target),
+			noticeCmd(i18n.Tf(i18n.MsgLinkCopied, link.target), themes.OutputLevelInfo),
+		)
+	}
+
+	path := filepath.FromSlash(target)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(v.linkDir(), path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return noticeCmd(err.Error(), themes.OutputLevelError)
+	}
+	linked := NewMarkdownViewFromFile(
+		&types.RenderState{ContentWidth: v.width, ContentHeight: v.height, Theme: v.theme},
+		path,
+	)
+	linked.pollInterval = v.pollInterval
+	linked.anchor = anchor
+	return types.PushView(linked)
+}
+
+// linkDir is the directory relative links are resolved against: the base directory
+// when one is set, or else the directory of the file shown.
+func (v *MarkdownView) linkDir() string {
+	if v.baseDir != "" || v.path == "" {
+		return v.baseDir
+	}
+	return filepath.Dir(v.path)
+}
+
+// SetBaseDir sets the directory that relative links are resolved against. Views
+// created from a file default to the file's directory; views created from a string
+// resolve links against the working directory until a base directory is set.
+func (v *MarkdownView) SetBaseDir(dir string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.baseDir = dir
+}
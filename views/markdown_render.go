@@ -0,0 +1,104 @@
+package views
+
+import (
+	"sync"
+
+	"charm.land/glamour/v2"
+
+	"github.com/flowexec/tuikit/themes"
+)
+
+// maxMarkdownRenderers bounds the shared renderer cache. Resizing the terminal creates
+// a renderer per width, so the cache is emptied once it holds this many.
+const maxMarkdownRenderers = 32
+
+// markdownRendererKey identifies a glamour renderer. Themes are identified by their
+// name and color palette, since theme values may not be comparable.
+type markdownRendererKey struct {
+	name             string
+	palette          *themes.ColorPalette
+	width            int
+	preserveNewLines bool
+}
+
+// markdownRenderer is a glamour renderer shared by the views rendering with the same
+// theme and width. Renderers reuse their buffers, so rendering is serialized.
+type markdownRenderer struct {
+	mu       sync.Mutex
+	renderer *glamour.TermRenderer
+}
+
+func (r *markdownRenderer) render(content string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.renderer.Render(content)
+}
+
+var markdownRenderers = struct {
+	sync.Mutex
+	cache map[markdownRendererKey]*markdownRenderer
+}{cache: make(map[markdownRendererKey]*markdownRenderer)}
+
+// sharedMarkdownRenderer returns the renderer for the theme and word wrap width,
+// creating it on first use.
+func sharedMarkdownRenderer(key markdownRendererKey, theme themes.Theme) (*markdownRenderer, error) {
+	markdownRenderers.Lock()
+	defer markdownRenderers.Unlock()
+	if r, ok := markdownRenderers.cache[key]; ok {
+		return r, nil
+	}
+
+	mdStyles, err := theme.GlamourMarkdownStyleJSON()
+	if err != nil {
+		return nil, err
+	}
+	opts := []glamour.TermRendererOption{
+		glamour.WithStylesFromJSONBytes([]byte(mdStyles)),
+		glamour.WithWordWrap(key.width),
+	}
+	if key.preserveNewLines {
+		opts = append(opts, glamour.WithPreservedNewLines())
+	}
+	renderer, err := glamour.NewTermRenderer(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(markdownRenderers.cache) >= maxMarkdownRenderers {
+		clear(markdownRenderers.cache)
+	}
+	r := &markdownRenderer{renderer: renderer}
+	markdownRenderers.cache[key] = r
+	return r, nil
+}
+
+// markdownCache holds the last document rendered by a view, so that the document is
+// only rendered again when its content, the width or the theme changes.
+type markdownCache struct {
+	key     markdownRendererKey
+	content string
+	out     string
+	valid   bool
+}
+
+// render returns the rendered markdown content, word wrapped at width.
+func (c *markdownCache) render(theme themes.Theme, width int, preserveNewLines bool, content string) (string, error) {
+	key := markdownRendererKey{
+		name:             theme.String(),
+		palette:          theme.ColorPalette(),
+		width:            width,
+		preserveNewLines: preserveNewLines,
+	}
+	if c.valid && c.key == key && c.content == content {
+		return c.out, nil
+	}
+	r, err := sharedMarkdownRenderer(key, theme)
+	if err != nil {
+		return "", err
+	}
+	out, err := r.render(content)
+	if err != nil {
+		return "", err
+	}
+	c.key, c.content, c.out, c.valid = key, content, out, true
+	return out, nil
+}
@@ -0,0 +1,371 @@
+package views
+
+import (
+	"slices"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/flowexec/tuikit/i18n"
+	"github.com/flowexec/tuikit/themes"
+	"github.com/flowexec/tuikit/types"
+)
+
+const MasterDetailViewType = "masterdetail"
+
+const (
+	// DefaultPreviewDebounce is how long the cursor must rest on a row before its
+	// preview is loaded.
+	DefaultPreviewDebounce = 150 * time.Millisecond
+	// DefaultPaneSize is the default size of the preview pane, as a percentage.
+	DefaultPaneSize = 40
+)
+
+// PanePosition is where the preview pane of a MasterDetailView is shown.
+type PanePosition int
+
+const (
+	PaneRight PanePosition = iota
+	PaneBottom
+)
+
+// SelectableView is a view that reports its current selection, such as Table or
+// CollectionView.
+type SelectableView interface {
+	tea.Model
+	Selectable
+}
+
+type previewDebounceMsg struct {
+	view *MasterDetailView
+	seq  int
+}
+
+type previewLoadedMsg struct {
+	view *MasterDetailView
+	seq  int
+	data ScreenData
+	err  error
+}
+
+// MasterDetailView shows a selectable master view next to a preview of its hovered
+// row or item. The preview is loaded by a DataProvider, which receives the hovered
+// selection, whenever the cursor rests on a new row. The preview is rendered as an
+// EntityView when the data has an Entity, as a DetailView when it has Fields and as
+// markdown otherwise. Page up and page down scroll the preview.
+type MasterDetailView struct {
+	render    *types.RenderState
+	master    SelectableView
+	provider  DataProvider
+	callbacks []types.KeyCallback
+
+	// Debounce is how long the cursor must rest before a preview is loaded. When
+	// zero, DefaultPreviewDebounce is used.
+	Debounce time.Duration
+
+	position  PanePosition
+	size      int
+	collapsed bool
+	preview   tea.Model
+	data      *ScreenData
+	err       error
+	loading   bool
+	hovered   *PageSelection
+	seq       int
+}
+
+// NewMasterDetailView creates a MasterDetailView. The key callbacks are actions on
+// the master view; they take precedence over the master's own keys.
+func NewMasterDetailView(
+	render *types.RenderState,
+	master SelectableView,
+	provider DataProvider,
+	keys ...types.KeyCallback,
+) *MasterDetailView {
+	v := &MasterDetailView{
+		render:    render,
+		master:    master,
+		provider:  provider,
+		callbacks: keys,
+	}
+	v.layout()
+	return v
+}
+
+func (v *MasterDetailView) Init() tea.Cmd {
+	return tea.Batch(v.master.Init(), v.checkHover())
+}
+
+func (v *MasterDetailView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *types.RenderState:
+		v.render = msg
+		return v, v.layout()
+	case previewDebounceMsg:
+		if msg.view == v {
+			if msg.seq == v.seq {
+				return v, v.loadPreview()
+			}
+			return v, nil
+		}
+	case previewLoadedMsg:
+		if msg.view == v {
+			if msg.seq == v.seq {
+				v.loading = false
+				v.data, v.err = &msg.data, msg.err
+				return v, v.buildPreview()
+			}
+			return v, nil
+		}
+	case tea.KeyPressMsg:
+		if !v.CapturingInput() {
+			for _, cb := range v.callbacks {
+				if cb.Key == msg.String() {
+					return v, func() tea.Msg { return cb.Callback() }
+				}
+			}
+			switch msg.String() {
+			case "pgup", "pgdown":
+				if v.preview != nil && !v.collapsed {
+					var cmd tea.Cmd
+					v.preview, cmd = v.preview.Update(msg)
+					return v, cmd
+				}
+			case "p":
+				v.collapsed = !v.collapsed
+				cmd := v.layout()
+				if !v.collapsed {
+					v.hovered = nil // reload the preview of the current row
+					return v, tea.Batch(cmd, v.checkHover())
+				}
+				return v, cmd
+			case "P":
+				if v.position == PaneRight {
+					return v, v.SetPosition(PaneBottom)
+				}
+				return v, v.SetPosition(PaneRight)
+			}
+		}
+	}
+
+	_, cmd := v.master.Update(msg)
+	return v, tea.Batch(cmd, v.checkHover())
+}
+
+// checkHover schedules a debounced preview load when the hovered row has changed.
+func (v *MasterDetailView) checkHover() tea.Cmd {
+	if v.collapsed || v.provider == nil {
+		return nil
+	}
+	sel := PageSelection{Index: v.master.SelectedIndex(), Data: v.master.SelectedData()}
+	if v.hovered != nil && v.hovered.Index == sel.Index && slices.Equal(v.hovered.Data, sel.Data) {
+		return nil
+	}
+	v.hovered = &sel
+	v.seq++
+	if sel.Data == nil {
+		v.loading, v.data, v.err, v.preview = false, nil, nil, nil
+		return nil
+	}
+	v.loading = true
+	seq := v.seq
+	return tea.Tick(v.debounce(), func(time.Time) tea.Msg {
+		return previewDebounceMsg{view: v, seq: seq}
+	})
+}
+
+func (v *MasterDetailView) loadPreview() tea.Cmd {
+	provider, sel, seq := v.provider, *v.hovered, v.seq
+	return func() tea.Msg {
+		data, err := provider([]PageSelection{sel})
+		return previewLoadedMsg{view: v, seq: seq, data: data, err: err}
+	}
+}
+
+func (v *MasterDetailView) debounce() time.Duration {
+	if v.Debounce > 0 {
+		return v.Debounce
+	}
+	return DefaultPreviewDebounce
+}
+
+// Position returns where the preview pane is shown.
+func (v *MasterDetailView) Position() PanePosition {
+	return v.position
+}
+
+// SetPosition moves the preview pane and lays the view out again.
+func (v *MasterDetailView) SetPosition(position PanePosition) tea.Cmd {
+	v.position = position
+	return v.layout()
+}
+
+// PaneSize returns the width (or height, when at the bottom) of the preview pane as
+// a percentage of the content area.
+func (v *MasterDetailView) PaneSize() int {
+	if v.size > 0 && v.size < 100 {
+		return v.size
+	}
+	return DefaultPaneSize
+}
+
+// SetPaneSize sets the size of the preview pane as a percentage of the content area
+// and lays the view out again. Sizes outside 1-99 use DefaultPaneSize.
+func (v *MasterDetailView) SetPaneSize(percent int) tea.Cmd {
+	v.size = percent
+	return v.layout()
+}
+
+// paneDimensions returns the outer width and height of the preview pane, including
+// its border.
+func (v *MasterDetailView) paneDimensions() (int, int) {
+	if v.position == PaneBottom {
+		return v.render.ContentWidth, v.render.ContentHeight * v.PaneSize() / 100
+	}
+	return v.render.ContentWidth * v.PaneSize() / 100, v.render.ContentHeight
+}
+
+// layout resizes the master view and the preview for the current pane.
+func (v *MasterDetailView) layout() tea.Cmd {
+	if v.render == nil {
+		return nil
+	}
+	master := *v.render
+	if !v.collapsed {
+		w, h := v.paneDimensions()
+		if v.position == PaneBottom {
+			master.ContentHeight -= h
+		} else {
+			master.ContentWidth -= w
+		}
+	}
+	_, cmd := v.master.Update(&master)
+	if v.preview == nil || v.collapsed {
+		return cmd
+	}
+	_, previewCmd := v.preview.Update(v.paneState())
+	return tea.Batch(cmd, previewCmd)
+}
+
+// paneState is the render state of the preview, inside the pane's border.
+func (v *MasterDetailView) paneState() *types.RenderState {
+	w, h := v.paneDimensions()
+	if v.position == PaneBottom {
+		h--
+	} else {
+		w--
+	}
+	return &types.RenderState{
+		Width:         v.render.Width,
+		Height:        v.render.Height,
+		ContentWidth:  max(w, 1),
+		ContentHeight: max(h, 1),
+		Theme:         v.render.Theme,
+	}
+}
+
+// buildPreview replaces the preview with one for the loaded data and returns its
+// Init command.
+func (v *MasterDetailView) buildPreview() tea.Cmd {
+	if v.render == nil || v.collapsed {
+		return nil
+	}
+	state := v.paneState()
+	switch {
+	case v.err != nil:
+		v.preview = NewErrorView(v.err, state.Theme)
+	case v.data == nil:
+		v.preview = nil
+	case v.data.Entity != nil:
+		v.preview = NewEntityView(state, v.data.Entity, types.EntityFormatDocument)
+	case len(v.data.Fields) > 0:
+		v.preview = NewDetailView(state, v.data.Content, v.data.Fields...)
+	default:
+		v.preview = NewMarkdownView(state, v.data.Content)
+	}
+	if v.preview == nil {
+		return nil
+	}
+	return v.preview.Init()
+}
+
+func (v *MasterDetailView) View() tea.View {
+	if v.render == nil {
+		return tea.View{Content: i18n.T(i18n.MsgNoData)}
+	}
+	master := v.master.View().Content
+	if v.collapsed {
+		return tea.View{Content: master}
+	}
+
+	w, h := v.paneDimensions()
+	cp := v.render.Theme.ColorPalette()
+	pane := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false).
+		BorderForeground(cp.BorderColor())
+	var content string
+	if v.position == PaneBottom {
+		pane = pane.BorderTop(true).Width(w).Height(h - 1).MaxHeight(h)
+		masterHeight := v.render.ContentHeight - h
+		master = lipgloss.NewStyle().Height(masterHeight).MaxHeight(masterHeight).Render(master)
+		content = lipgloss.JoinVertical(lipgloss.Left, master, pane.Render(v.renderPreview()))
+	} else {
+		pane = pane.BorderLeft(true).Width(w - 1).Height(h).MaxHeight(h)
+		masterWidth := v.render.ContentWidth - w
+		master = lipgloss.NewStyle().Width(masterWidth).MaxWidth(masterWidth).Height(h).MaxHeight(h).Render(master)
+		content = lipgloss.JoinHorizontal(lipgloss.Top, master, pane.Render(v.renderPreview()))
+	}
+	return tea.View{Content: content}
+}
+
+func (v *MasterDetailView) renderPreview() string {
+	gray := lipgloss.NewStyle().Foreground(v.render.Theme.ColorPalette().GrayColor()).MarginLeft(1)
+	switch {
+	case v.loading && v.preview == nil:
+		return gray.Render(i18n.T(i18n.MsgLoading))
+	case v.preview == nil:
+		return gray.Render(i18n.T(i18n.MsgNoData))
+	default:
+		return v.preview.View().Content
+	}
+}
+
+func (v *MasterDetailView) HelpBindings() []themes.HelpKey {
+	var keys []themes.HelpKey
+	for _, cb := range v.callbacks {
+		if cb.Key != "" && cb.Label != "" {
+			keys = append(keys, themes.HelpKey{Key: cb.Key, Desc: cb.Label})
+		}
+	}
+	if h, ok := v.master.(interface{ HelpBindings() []themes.HelpKey }); ok {
+		keys = append(keys, h.HelpBindings()...)
+	}
+	return append(keys,
+		themes.HelpKey{Key: "pgup/pgdn", Desc: i18n.T(i18n.HelpScrollPreview)},
+		themes.HelpKey{Key: "p", Desc: i18n.T(i18n.HelpTogglePreview)},
+		themes.HelpKey{Key: "P", Desc: i18n.T(i18n.HelpPreviewPosition)},
+	)
+}
+
+func (v *MasterDetailView) CapturingInput() bool {
+	ic, ok := v.master.(interface{ CapturingInput() bool })
+	return ok && ic.CapturingInput()
+}
+
+func (v *MasterDetailView) SelectedIndex() int {
+	return v.master.SelectedIndex()
+}
+
+func (v *MasterDetailView) SelectedData() []string {
+	return v.master.SelectedData()
+}
+
+// Master returns the master view.
+func (v *MasterDetailView) Master() SelectableView {
+	return v.master
+}
+
+func (v *MasterDetailView) Type() string {
+	return MasterDetailViewType
+}
@@ -0,0 +1,334 @@
+package views
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/flowexec/tuikit/types"
+)
+
+// ViewFactory builds a view from its definition. Factories are registered by name
+// in a ViewRegistry and referenced by the definition's Type.
+type ViewFactory func(ctx BuildContext) (tea.Model, error)
+
+// BuildContext is passed to a ViewFactory when a definition is built.
+type BuildContext struct {
+	Render     *types.RenderState
+	Definition ViewDefinition
+	Bindings   *ScreenBindings
+	// Selections holds the selections of prior Library pages.
+	Selections []PageSelection
+	Registry   *ViewRegistry
+}
+
+// Data calls the definition's data provider.
+func (c BuildContext) Data() (ScreenData, error) {
+	return c.Bindings.provide(c.Definition.Provider, c.Selections)
+}
+
+// Action returns a function that runs the named action for the given view.
+// The returned function receives extra values, such as submitted form values.
+func (c BuildContext) Action(name string, view tea.Model) (func(values map[string]any) error, error) {
+	fn, err := c.Bindings.action(name)
+	if err != nil {
+		return nil, err
+	}
+	return func(values map[string]any) error {
+		return fn(c.actionContext(view, values))
+	}, nil
+}
+
+func (c BuildContext) actionContext(view tea.Model, values map[string]any) ActionContext {
+	selections := make([]PageSelection, len(c.Selections), len(c.Selections)+1)
+	copy(selections, c.Selections)
+	if s, ok := view.(Selectable); ok {
+		if data := s.SelectedData(); data != nil {
+			selections = append(selections, PageSelection{Index: s.SelectedIndex(), Data: data})
+		}
+	}
+	return ActionContext{View: view, Selections: selections, Values: values}
+}
+
+// ViewRegistry maps view type names to factories. NewViewRegistry registers the
+// built-in views: table, markdown, detail, entity, collection, form, library and
+// masterdetail.
+type ViewRegistry struct {
+	factories map[string]ViewFactory
+	mu        sync.RWMutex
+}
+
+func NewViewRegistry() *ViewRegistry {
+	r := &ViewRegistry{factories: make(map[string]ViewFactory)}
+	r.Register(TableViewType, tableFactory)
+	r.Register("markdown", markdownFactory)
+	r.Register(DetailViewType, detailFactory)
+	r.Register("entity", entityFactory)
+	r.Register("collection", collectionFactory)
+	r.Register(FormViewType, formFactory)
+	r.Register(LibraryViewType, libraryFactory)
+	r.Register(MasterDetailViewType, masterDetailFactory)
+	return r
+}
+
+// Register adds or replaces the factory for the given view type.
+func (r *ViewRegistry) Register(name string, factory ViewFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[strings.ToLower(name)] = factory
+}
+
+func (r *ViewRegistry) Lookup(name string) (ViewFactory, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.factories[strings.ToLower(name)]
+	return f, ok
+}
+
+// Build builds the view for a definition along with the key callbacks for its actions.
+func (r *ViewRegistry) Build(
+	render *types.RenderState,
+	def ViewDefinition,
+	bindings *ScreenBindings,
+) (tea.Model, []types.KeyCallback, error) {
+	return r.build(render, def, bindings, nil)
+}
+
+// BuildScreen builds a top-level screen. Views with actions that are not already a
+// Library are wrapped in a single-page Library so that their action keys are handled.
+func (r *ViewRegistry) BuildScreen(
+	render *types.RenderState,
+	def ViewDefinition,
+	bindings *ScreenBindings,
+) (tea.Model, error) {
+	if len(def.Actions) == 0 || strings.EqualFold(def.Type, LibraryViewType) {
+		model, _, err := r.Build(render, def, bindings)
+		if err != nil {
+			return nil, err
+		}
+		return model, nil
+	}
+
+	// The Library builds its page right away; a failure to build it is returned rather
+	// than shown.
+	var buildErr error
+	page := LibraryPage{Title: def.Title, Factory: func(
+		render *types.RenderState,
+		selections []PageSelection,
+	) (tea.Model, []types.KeyCallback) {
+		model, keys, err := r.build(render, def, bindings, selections)
+		if err != nil {
+			buildErr = err
+			return NewErrorView(err, render.Theme), nil
+		}
+		return model, keys
+	}}
+	lib := NewLibrary(render, page)
+	if buildErr != nil {
+		return nil, buildErr
+	}
+	return lib, nil
+}
+
+func (r *ViewRegistry) build(
+	render *types.RenderState,
+	def ViewDefinition,
+	bindings *ScreenBindings,
+	selections []PageSelection,
+) (tea.Model, []types.KeyCallback, error) {
+	factory, ok := r.Lookup(def.Type)
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown view type %q", def.Type)
+	}
+	ctx := BuildContext{
+		Render:     render,
+		Definition: def,
+		Bindings:   bindings,
+		Selections: selections,
+		Registry:   r,
+	}
+	model, err := factory(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to build %s view %q: %w", def.Type, def.Title, err)
+	}
+
+	keys := make([]types.KeyCallback, 0, len(def.Actions))
+	for _, a := range def.Actions {
+		run, err := ctx.Action(a.Action, model)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, types.KeyCallback{
+			Key:      a.Key,
+			Label:    a.Label,
+			Callback: func() error { return run(nil) },
+		})
+	}
+	return model, keys, nil
+}
+
+func (r *ViewRegistry) pageFactory(def ViewDefinition, bindings *ScreenBindings) PageFactory {
+	return func(render *types.RenderState, selections []PageSelection) (tea.Model, []types.KeyCallback) {
+		model, keys, err := r.build(render, def, bindings, selections)
+		if err != nil {
+			return NewErrorView(err, render.Theme), nil
+		}
+		return model, keys
+	}
+}
+
+var defaultViewRegistry = NewViewRegistry()
+
+// DefaultViewRegistry returns the registry used by RegisterView and BuildScreen.
+func DefaultViewRegistry() *ViewRegistry {
+	return defaultViewRegistry
+}
+
+// RegisterView adds a factory to the default registry.
+func RegisterView(name string, factory ViewFactory) {
+	defaultViewRegistry.Register(name, factory)
+}
+
+// BuildScreen builds a top-level screen from the default registry.
+func BuildScreen(render *types.RenderState, def ViewDefinition, bindings *ScreenBindings) (tea.Model, error) {
+	return defaultViewRegistry.BuildScreen(render, def, bindings)
+}
+
+func tableFactory(ctx BuildContext) (tea.Model, error) {
+	data, err := ctx.Data()
+	if err != nil {
+		return nil, err
+	}
+	mode := TableDisplayFull
+	if strings.EqualFold(ctx.Definition.Mode, "mini") {
+		mode = TableDisplayMini
+	}
+	table := NewTable(ctx.Render, ctx.Definition.Columns, data.Rows, mode)
+	if ctx.Definition.OnSelect != "" {
+		run, err := ctx.Action(ctx.Definition.OnSelect, table)
+		if err != nil {
+			return nil, err
+		}
+		table.OnSelect = func(int) error { return run(nil) }
+	}
+	return table, nil
+}
+
+func markdownFactory(ctx BuildContext) (tea.Model, error) {
+	data, err := ctx.Data()
+	if err != nil {
+		return nil, err
+	}
+	content := data.Content
+	if content == "" {
+		content = ctx.Definition.Content
+	}
+	return NewMarkdownView(ctx.Render, content), nil
+}
+
+func detailFactory(ctx BuildContext) (tea.Model, error) {
+	data, err := ctx.Data()
+	if err != nil {
+		return nil, err
+	}
+	body := data.Content
+	if body == "" {
+		body = ctx.Definition.Content
+	}
+	return NewDetailView(ctx.Render, body, data.Fields...), nil
+}
+
+func entityFactory(ctx BuildContext) (tea.Model, error) {
+	data, err := ctx.Data()
+	if err != nil {
+		return nil, err
+	}
+	if data.Entity == nil {
+		return nil, fmt.Errorf("provider %q returned no entity", ctx.Definition.Provider)
+	}
+	return NewEntityView(ctx.Render, data.Entity, ctx.Definition.Format), nil
+}
+
+func collectionFactory(ctx BuildContext) (tea.Model, error) {
+	data, err := ctx.Data()
+	if err != nil {
+		return nil, err
+	}
+	if data.Collection == nil {
+		return nil, fmt.Errorf("provider %q returned no collection", ctx.Definition.Provider)
+	}
+	view := NewCollectionView(ctx.Render, data.Collection, ctx.Definition.Format, nil)
+	if ctx.Definition.OnSelect != "" {
+		run, err := ctx.Action(ctx.Definition.OnSelect, view)
+		if err != nil {
+			return nil, err
+		}
+		view.selectedFunc = func(id string) error {
+			return run(map[string]any{"id": id})
+		}
+	}
+	return view, nil
+}
+
+func formFactory(ctx BuildContext) (tea.Model, error) {
+	fields := make([]*FormField, len(ctx.Definition.Fields))
+	for i := range ctx.Definition.Fields {
+		f := ctx.Definition.Fields[i]
+		fields[i] = &f
+	}
+	form, err := NewFormView(ctx.Render, fields...)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Definition.OnSubmit != "" {
+		run, err := ctx.Action(ctx.Definition.OnSubmit, form)
+		if err != nil {
+			return nil, err
+		}
+		form.Callback = run
+	}
+	return form, nil
+}
+
+func libraryFactory(ctx BuildContext) (tea.Model, error) {
+	if len(ctx.Definition.Pages) == 0 {
+		return nil, fmt.Errorf("library requires at least one page")
+	}
+	pages := make([]LibraryPage, len(ctx.Definition.Pages))
+	for i, p := range ctx.Definition.Pages {
+		pages[i] = LibraryPage{Title: p.Title, Factory: ctx.Registry.pageFactory(p, ctx.Bindings)}
+	}
+	return NewLibrary(ctx.Render, pages...), nil
+}
+
+// masterDetailFactory builds the definition's single page as the master view, with
+// the page's actions as keys of the MasterDetailView. The definition's provider loads
+// the preview and receives the prior Library selections followed by the hovered row.
+func masterDetailFactory(ctx BuildContext) (tea.Model, error) {
+	if len(ctx.Definition.Pages) != 1 {
+		return nil, fmt.Errorf("masterdetail requires a single page for its master view")
+	}
+	model, keys, err := ctx.Registry.build(ctx.Render, ctx.Definition.Pages[0], ctx.Bindings, ctx.Selections)
+	if err != nil {
+		return nil, err
+	}
+	master, ok := model.(SelectableView)
+	if !ok {
+		return nil, fmt.Errorf("%s views can't be used as a master view", ctx.Definition.Pages[0].Type)
+	}
+	name, bindings, prior := ctx.Definition.Provider, ctx.Bindings, ctx.Selections
+	if name == "" || bindings == nil || bindings.Providers[name] == nil {
+		return nil, fmt.Errorf("preview provider %q is not bound", name)
+	}
+	provider := func(hovered []PageSelection) (ScreenData, error) {
+		return bindings.provide(name, append(slices.Clone(prior), hovered...))
+	}
+	view := NewMasterDetailView(ctx.Render, master, provider, keys...)
+	if strings.EqualFold(ctx.Definition.Mode, "bottom") {
+		view.SetPosition(PaneBottom)
+	}
+	return view, nil
+}
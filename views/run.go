@@ -0,0 +1,633 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	stdIO "io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/flowexec/tuikit/i18n"
+	"github.com/flowexec/tuikit/io"
+	"github.com/flowexec/tuikit/themes"
+	"github.com/flowexec/tuikit/types"
+)
+
+const RunViewType = "run"
+
+// DefaultRunKillDelay is how long a canceled process has to exit after SIGINT before
+// it is killed.
+const DefaultRunKillDelay = 5 * time.Second
+
+// runWaitDelay is how long the output of a process that exited is still read, e.g.
+// from children left running in the background, when its command doesn't set a
+// WaitDelay.
+const runWaitDelay = time.Second
+
+// RunMode is how the commands of a RunView are run.
+type RunMode int
+
+const (
+	// RunSerial runs the commands one after another, stopping at the first failure.
+	RunSerial RunMode = iota
+	// RunParallel runs the commands at the same time.
+	RunParallel
+)
+
+// RunStatus is the state of a command run by a RunView.
+type RunStatus string
+
+const (
+	RunPending   RunStatus = "pending"
+	RunRunning   RunStatus = "running"
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"
+	RunCanceled  RunStatus = "canceled"
+	// RunSkipped is the status of commands not run because an earlier serial command
+	// failed, or because the run was canceled before they started.
+	RunSkipped RunStatus = "skipped"
+)
+
+// RunResult is the outcome of a command run by a RunView.
+type RunResult struct {
+	Name string
+	Args []string
+	// ExitCode is -1 when the process didn't exit on its own, e.g. when it couldn't be
+	// started or was killed by a signal.
+	ExitCode int
+	// Err is the error starting or waiting for the process, e.g. an *exec.ExitError.
+	Err      error
+	Status   RunStatus
+	Started  time.Time
+	Duration time.Duration
+}
+
+// runExitMsg is sent when a process exits, or fails to start. The exit is recorded
+// on the view first, so that it is still handled when the message is dropped while
+// another view is shown.
+type runExitMsg struct {
+	view *RunView
+}
+
+// runExit is the exit of a process, or its failure to start.
+type runExit struct {
+	run   int
+	index int
+	err   error
+}
+
+// runTickMsg updates the elapsed times while processes are running.
+type runTickMsg struct {
+	view *RunView
+	tick int
+}
+
+// runKillMsg kills the processes still running once the kill delay of a cancel passed.
+type runKillMsg struct {
+	view *RunView
+	run  int
+}
+
+type runProcess struct {
+	template *exec.Cmd
+	cmd      *exec.Cmd
+	name     string
+	status   RunStatus
+	started  time.Time
+	ended    time.Time
+	exitCode int
+	err      error
+	stdout   *streamWriter
+	stderr   *streamWriter
+}
+
+// RunView runs commands and streams their combined output, each line labeled with the
+// name of its command. Each command is shown with its status, elapsed time and exit
+// code. Runs can be canceled, sending SIGINT and then killing the processes still
+// running after the kill delay, and rerun once finished. The commands are only used as
+// templates: each run starts copies of them, with their output written to the view as
+// well as to their own Stdout and Stderr, if set.
+type RunView struct {
+	render    *types.RenderState
+	mode      RunMode
+	procs     []*runProcess
+	output    *StreamView
+	run       int
+	canceling bool
+	killDelay time.Duration
+	killAt    time.Time
+	ticks     int // id of the latest tick, so that only one tick is pending
+
+	// mu guards the exits recorded by the wait goroutines until the view handles them.
+	mu    sync.Mutex
+	exits []runExit
+
+	archiveDir, archiveID string
+	archive               *runArchive
+
+	onDone func(results []RunResult)
+}
+
+// NewRunView creates a RunView for the commands, which are created with exec.Command.
+// The context and Cancel function of commands created with exec.CommandContext can't
+// be copied, so they aren't used; cancel runs with Cancel or by closing the view.
+func NewRunView(render *types.RenderState, mode RunMode, cmds ...*exec.Cmd) *RunView {
+	v := &RunView{
+		render:    render,
+		mode:      mode,
+		killDelay: DefaultRunKillDelay,
+	}
+	seen := make(map[string]int)
+	for _, cmd := range cmds {
+		name := filepath.Base(cmd.Path)
+		if len(cmd.Args) > 0 {
+			name = filepath.Base(cmd.Args[0])
+		}
+		if seen[name]++; seen[name] > 1 {
+			name = fmt.Sprintf("%s#%d", name, seen[name])
+		}
+		v.procs = append(v.procs, &runProcess{template: cmd, name: name, status: RunPending, exitCode: -1})
+	}
+	v.output = NewStreamView(v.outputState())
+	return v
+}
+
+// SetKillDelay sets how long canceled processes have to exit after SIGINT before they
+// are killed.
+func (v *RunView) SetKillDelay(delay time.Duration) {
+	v.killDelay = delay
+}
+
+// SetArchive writes the combined output of each run to a new entry of the log archive
+// in dir, e.g. to be browsed with a LogArchiveView.
+func (v *RunView) SetArchive(dir, id string) {
+	v.archiveDir, v.archiveID = dir, id
+}
+
+// SetOnDone sets the callback called with the results when a run finishes.
+func (v *RunView) SetOnDone(callback func(results []RunResult)) {
+	v.onDone = callback
+}
+
+// Running reports whether any process of the current run is running.
+func (v *RunView) Running() bool {
+	return slices.ContainsFunc(v.procs, func(p *runProcess) bool { return p.status == RunRunning })
+}
+
+// Results returns the results of the current run. Commands still running have the
+// RunRunning status.
+func (v *RunView) Results() []RunResult {
+	results := make([]RunResult, 0, len(v.procs))
+	for _, p := range v.procs {
+		result := RunResult{
+			Name:     p.name,
+			Args:     slices.Clone(p.template.Args),
+			ExitCode: p.exitCode,
+			Err:      p.err,
+			Status:   p.status,
+			Started:  p.started,
+		}
+		if !p.ended.IsZero() {
+			result.Duration = p.ended.Sub(p.started)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Init starts the first run. When the view is shown again, e.g. after going back to
+// it, the processes that exited meanwhile are handled and the updates resume.
+func (v *RunView) Init() tea.Cmd {
+	if v.run == 0 {
+		return tea.Batch(v.output.Init(), v.start())
+	}
+	cmds := []tea.Cmd{v.output.Init(), func() tea.Msg { return runExitMsg{view: v} }}
+	if v.Running() {
+		cmds = append(cmds, v.tick())
+		if v.canceling {
+			cmds = append(cmds, v.killTimer())
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// Rerun runs the commands again, unless they are running.
+func (v *RunView) Rerun() tea.Cmd {
+	if v.Running() {
+		return nil
+	}
+	return v.start()
+}
+
+// Cancel sends SIGINT to the running processes and skips the commands not started.
+// Processes still running after the kill delay, or when Cancel is called again, are
+// killed. On unix, each command runs in a process group of its own and the signals
+// are sent to the whole group, reaching the processes it started too.
+func (v *RunView) Cancel() tea.Cmd {
+	if !v.Running() {
+		return nil
+	}
+	if v.canceling {
+		v.kill()
+		return nil
+	}
+	v.canceling = true
+	v.killAt = time.Now().Add(v.killDelay)
+	for _, p := range v.procs {
+		switch p.status {
+		case RunPending:
+			p.status = RunSkipped
+		case RunRunning:
+			if p.cmd.Process != nil {
+				if err := interruptProcess(p.cmd.Process); err != nil {
+					_ = killProcess(p.cmd.Process)
+				}
+			}
+		}
+	}
+	return v.killTimer()
+}
+
+// killTimer sends a runKillMsg once the kill delay of the cancel has passed.
+func (v *RunView) killTimer() tea.Cmd {
+	run := v.run
+	return tea.Tick(time.Until(v.killAt), func(time.Time) tea.Msg {
+		return runKillMsg{view: v, run: run}
+	})
+}
+
+// Close kills the running processes, closes the archive entry of the run and stops
+// the output from listening for lines. The container closes views it no longer shows
+// and the views it holds when the program exits; other callers should close the view
+// once they are done with it.
+func (v *RunView) Close() error {
+	v.kill()
+	_ = v.output.Close()
+	if v.archive == nil {
+		return nil
+	}
+	err := v.archive.close()
+	v.archive = nil
+	return err
+}
+
+func (v *RunView) kill() {
+	for _, p := range v.procs {
+		if p.status == RunRunning && p.cmd.Process != nil {
+			_ = killProcess(p.cmd.Process)
+		}
+	}
+}
+
+func (v *RunView) start() tea.Cmd {
+	v.run++
+	v.canceling = false
+	v.output.Clear()
+	for _, p := range v.procs {
+		p.cmd, p.err, p.exitCode = nil, nil, -1
+		p.status, p.started, p.ended = RunPending, time.Time{}, time.Time{}
+	}
+
+	cmds := []tea.Cmd{v.tick()}
+	v.archive = nil
+	if v.archiveDir != "" {
+		file, err := io.CreateArchiveLogFile(v.archiveDir, v.archiveID)
+		if err != nil {
+			cmds = append(cmds, noticeCmd(i18n.Tf(i18n.MsgArchiveFailed, err), themes.OutputLevelError))
+		} else {
+			v.archive = &runArchive{file: file, labels: len(v.procs) > 1}
+		}
+	}
+	switch {
+	case len(v.procs) == 0:
+		cmds = append(cmds, v.finish())
+	case v.mode == RunParallel:
+		for i := range v.procs {
+			cmds = append(cmds, v.startProcess(i))
+		}
+	default:
+		cmds = append(cmds, v.startProcess(0))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (v *RunView) startProcess(index int) tea.Cmd {
+	p := v.procs[index]
+	p.cmd = cloneCmd(p.template)
+	setProcessGroup(p.cmd)
+	p.stdout = v.lineWriter(p.name)
+	p.stderr = v.lineWriter(p.name)
+	p.cmd.Stdout = withWriter(p.stdout, p.template.Stdout)
+	p.cmd.Stderr = withWriter(p.stderr, p.template.Stderr)
+	if p.cmd.WaitDelay == 0 {
+		p.cmd.WaitDelay = runWaitDelay
+	}
+	p.status, p.started = RunRunning, time.Now()
+	v.archive.writeLine(p.name, "$ "+strings.Join(p.cmd.Args, " "))
+
+	cmd, run := p.cmd, v.run
+	if err := cmd.Start(); err != nil {
+		return func() tea.Msg { return v.recordExit(runExit{run: run, index: index, err: err}) }
+	}
+	return func() tea.Msg {
+		return v.recordExit(runExit{run: run, index: index, err: cmd.Wait()})
+	}
+}
+
+func (v *RunView) recordExit(exit runExit) tea.Msg {
+	v.mu.Lock()
+	v.exits = append(v.exits, exit)
+	v.mu.Unlock()
+	return runExitMsg{view: v}
+}
+
+// handleExits handles the exits recorded since they were last handled. The exits of
+// earlier runs are dropped.
+func (v *RunView) handleExits() tea.Cmd {
+	v.mu.Lock()
+	exits := v.exits
+	v.exits = nil
+	v.mu.Unlock()
+
+	var cmds []tea.Cmd
+	for _, exit := range exits {
+		if exit.run == v.run {
+			cmds = append(cmds, v.handleExit(exit))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// lineWriter returns a writer showing the output of a process in the view and writing
+// it to the archive.
+func (v *RunView) lineWriter(label string) *streamWriter {
+	archive := v.archive
+	return &streamWriter{line: func(text string) {
+		v.output.Append(label, text)
+		archive.writeLine(label, text)
+	}}
+}
+
+func (v *RunView) handleExit(exit runExit) tea.Cmd {
+	p := v.procs[exit.index]
+	p.stdout.flush()
+	p.stderr.flush()
+	v.output.flush()
+
+	// The output left open by children of a successful process was cut after the wait
+	// delay; that is not a failure of the process.
+	if errors.Is(exit.err, exec.ErrWaitDelay) {
+		exit.err = nil
+	}
+	p.ended, p.err = time.Now(), exit.err
+	if state := p.cmd.ProcessState; state != nil {
+		p.exitCode = state.ExitCode()
+	}
+	switch {
+	case exit.err == nil:
+		p.status = RunSucceeded
+	case v.canceling:
+		p.status = RunCanceled
+	default:
+		p.status = RunFailed
+	}
+	status := runStatusText(p.status)
+	if p.exitCode >= 0 {
+		status += " (" + i18n.Tf(i18n.MsgExitCode, p.exitCode) + ")"
+	}
+	v.archive.writeLine(p.name, status)
+
+	if next := exit.index + 1; v.mode == RunSerial && next < len(v.procs) && v.procs[next].status == RunPending {
+		if p.status == RunSucceeded {
+			return v.startProcess(next)
+		}
+		for _, rest := range v.procs[next:] {
+			rest.status = RunSkipped
+		}
+	}
+	if v.Running() {
+		return nil
+	}
+	return v.finish()
+}
+
+// finish closes the archive entry and reports the results.
+func (v *RunView) finish() tea.Cmd {
+	var cmd tea.Cmd
+	if v.archive != nil {
+		if err := v.archive.close(); err != nil {
+			cmd = noticeCmd(i18n.Tf(i18n.MsgArchiveFailed, err), themes.OutputLevelError)
+		}
+		v.archive = nil
+		io.RotateArchive(v.archiveDir)
+	}
+	if v.onDone != nil {
+		v.onDone(v.Results())
+	}
+	return cmd
+}
+
+func (v *RunView) tick() tea.Cmd {
+	v.ticks++
+	tick := v.ticks
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return runTickMsg{view: v, tick: tick}
+	})
+}
+
+func (v *RunView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *types.RenderState:
+		v.render = msg
+		v.output.Update(v.outputState())
+		return v, nil
+	case runExitMsg:
+		if msg.view != v {
+			return v, nil
+		}
+		return v, v.handleExits()
+	case runTickMsg:
+		if msg.view != v || msg.tick != v.ticks || !v.Running() {
+			return v, nil
+		}
+		return v, v.tick()
+	case runKillMsg:
+		if msg.view == v && msg.run == v.run {
+			v.kill()
+		}
+		return v, nil
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "c":
+			return v, v.Cancel()
+		case "r":
+			return v, v.Rerun()
+		}
+	}
+	_, cmd := v.output.Update(msg)
+	return v, cmd
+}
+
+// headerHeight is the number of rows showing the processes, including the separator
+// above the output.
+func (v *RunView) headerHeight() int {
+	return min(len(v.procs), max(v.render.ContentHeight/3, 1)) + 1
+}
+
+func (v *RunView) outputState() *types.RenderState {
+	state := *v.render
+	state.ContentHeight = max(v.render.ContentHeight-v.headerHeight(), 2)
+	return &state
+}
+
+func (v *RunView) View() tea.View {
+	cp := v.render.Theme.ColorPalette()
+	width := v.render.ContentWidth
+	rows := v.headerHeight() - 1
+
+	nameWidth, statusWidth := 0, 0
+	for _, p := range v.procs {
+		nameWidth = max(nameWidth, ansi.StringWidth(p.name))
+		statusWidth = max(statusWidth, ansi.StringWidth(runStatusText(p.status)))
+	}
+	grayStyle := lipgloss.NewStyle().Foreground(cp.GrayColor())
+	lines := make([]string, 0, rows+1)
+	for i, p := range v.procs {
+		if len(lines) == rows-1 && len(v.procs) > rows {
+			lines = append(lines, grayStyle.Render(i18n.Tf(i18n.MsgMoreBelow, len(v.procs)-i)))
+			break
+		}
+		icon, c := runStatusIcon(p.status, cp)
+		statusStyle := lipgloss.NewStyle().Foreground(c)
+		var elapsed, exit string
+		switch {
+		case p.status == RunRunning:
+			elapsed = time.Since(p.started).Truncate(time.Second).String()
+		case !p.ended.IsZero():
+			elapsed = p.ended.Sub(p.started).Round(10 * time.Millisecond).String()
+			if p.exitCode >= 0 {
+				exit = i18n.Tf(i18n.MsgExitCode, p.exitCode)
+			}
+		}
+		line := statusStyle.Render(icon) + " " +
+			lipgloss.NewStyle().Bold(true).Width(nameWidth).Render(p.name) + "  " +
+			statusStyle.Width(statusWidth).Render(runStatusText(p.status)) + "  " +
+			lipgloss.NewStyle().Width(8).Render(elapsed) +
+			lipgloss.NewStyle().Width(9).Render(exit)
+		command := ansi.Truncate(strings.Join(p.template.Args, " "), max(width-ansi.StringWidth(line), 0), "…")
+		lines = append(lines, line+grayStyle.Render(command))
+	}
+	lines = append(lines, lipgloss.NewStyle().Foreground(cp.BorderColor()).Render(strings.Repeat("─", width)))
+	return tea.View{Content: strings.Join(lines, "\n") + "\n" + v.output.View().Content}
+}
+
+func (v *RunView) HelpBindings() []themes.HelpKey {
+	return append(v.output.HelpBindings(),
+		themes.HelpKey{Key: "c", Desc: i18n.T(i18n.HelpCancel)},
+		themes.HelpKey{Key: "r", Desc: i18n.T(i18n.HelpRerun)},
+	)
+}
+
+func (v *RunView) Type() string {
+	return RunViewType
+}
+
+func runStatusText(status RunStatus) string {
+	switch status {
+	case RunRunning:
+		return i18n.T(i18n.MsgRunRunning)
+	case RunSucceeded:
+		return i18n.T(i18n.MsgRunSucceeded)
+	case RunFailed:
+		return i18n.T(i18n.MsgRunFailed)
+	case RunCanceled:
+		return i18n.T(i18n.MsgRunCanceled)
+	case RunSkipped:
+		return i18n.T(i18n.MsgRunSkipped)
+	}
+	return i18n.T(i18n.MsgRunPending)
+}
+
+func runStatusIcon(status RunStatus, cp *themes.ColorPalette) (string, color.Color) {
+	switch status {
+	case RunRunning:
+		return "●", cp.InfoColor()
+	case RunSucceeded:
+		return "✔", cp.SuccessColor()
+	case RunFailed:
+		return "✘", cp.ErrorColor()
+	case RunCanceled:
+		return "■", cp.WarningColor()
+	case RunSkipped:
+		return "–", cp.GrayColor()
+	}
+	return "○", cp.GrayColor()
+}
+
+// cloneCmd copies what describes the command to run, since a Cmd can only be started
+// once. The context of exec.CommandContext is unexported, so it and Cancel, which
+// requires it, are not copied.
+func cloneCmd(cmd *exec.Cmd) *exec.Cmd {
+	return &exec.Cmd{
+		Path:        cmd.Path,
+		Args:        slices.Clone(cmd.Args),
+		Env:         slices.Clone(cmd.Env),
+		Dir:         cmd.Dir,
+		Stdin:       cmd.Stdin,
+		ExtraFiles:  cmd.ExtraFiles,
+		SysProcAttr: cmd.SysProcAttr,
+		WaitDelay:   cmd.WaitDelay,
+		Err:         cmd.Err,
+	}
+}
+
+// withWriter also writes to w when it is set.
+func withWriter(line *streamWriter, w stdIO.Writer) stdIO.Writer {
+	if w == nil {
+		return line
+	}
+	return stdIO.MultiWriter(line, w)
+}
+
+// runArchive writes the combined output of a run to a log archive entry, without
+// colors. A nil runArchive writes nothing.
+type runArchive struct {
+	mu     sync.Mutex
+	file   *os.File
+	labels bool
+	err    error
+}
+
+func (a *runArchive) writeLine(label, text string) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		return
+	}
+	if a.labels {
+		text = label + " | " + text
+	}
+	_, a.err = fmt.Fprintln(a.file, ansi.Strip(text))
+}
+
+func (a *runArchive) close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.file.Close(); a.err == nil {
+		a.err = err
+	}
+	return a.err
+}
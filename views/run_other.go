@@ -0,0 +1,22 @@
+//go:build !unix
+
+package views
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup does nothing where process groups aren't supported.
+func setProcessGroup(*exec.Cmd) {}
+
+// interruptProcess sends an interrupt to p, which fails where interrupts aren't
+// supported, e.g. on Windows.
+func interruptProcess(p *os.Process) error {
+	return p.Signal(os.Interrupt)
+}
+
+// killProcess kills p.
+func killProcess(p *os.Process) error {
+	return p.Kill()
+}
@@ -0,0 +1,44 @@
+//go:build unix
+
+package views
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the command in a process group of its own, so that canceling
+// it also reaches the processes it starts.
+func setProcessGroup(cmd *exec.Cmd) {
+	attr := syscall.SysProcAttr{}
+	if cmd.SysProcAttr != nil {
+		attr = *cmd.SysProcAttr
+	}
+	// A new session has a process group of its own already.
+	if !attr.Setsid {
+		attr.Setpgid, attr.Pgid = true, 0
+	}
+	cmd.SysProcAttr = &attr
+}
+
+// interruptProcess sends SIGINT to the process group of p.
+func interruptProcess(p *os.Process) error {
+	return signalGroup(p, syscall.SIGINT)
+}
+
+// killProcess kills the process group of p.
+func killProcess(p *os.Process) error {
+	return signalGroup(p, syscall.SIGKILL)
+}
+
+// signalGroup signals the process group led by p, or p alone when it doesn't lead
+// one, e.g. when the group was reaped already.
+func signalGroup(p *os.Process, sig syscall.Signal) error {
+	err := syscall.Kill(-p.Pid, sig)
+	if errors.Is(err, syscall.ESRCH) {
+		return p.Signal(sig)
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "charm.land/bubbletea/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/flowexec/tuikit/types"
+)
+
+// ViewDefinition declares a view (or a whole screen) that is built from the ViewRegistry.
+// Definitions can be loaded from YAML or JSON and are bound to Go code through ScreenBindings.
+type ViewDefinition struct {
+	// Type is the name of the registered view factory, e.g. "table" or "library".
+	Type  string `json:"type"            yaml:"type"`
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+	// Provider is the name of the ScreenBindings data provider that supplies the view's data.
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+	// Content is static content for content-based views (markdown, detail).
+	Content string `json:"content,omitempty" yaml:"content,omitempty"`
+	// Mode is the table display mode ("full" or "mini"), or the preview pane position
+	// of a masterdetail view ("right" or "bottom").
+	Mode string `json:"mode,omitempty" yaml:"mode,omitempty"`
+	// Format is the initial format of entity and collection views.
+	Format types.Format `json:"format,omitempty" yaml:"format,omitempty"`
+
+	Columns []TableColumn      `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Fields  []FormField        `json:"fields,omitempty"  yaml:"fields,omitempty"`
+	Actions []ActionDefinition `json:"actions,omitempty" yaml:"actions,omitempty"`
+	Pages   []ViewDefinition   `json:"pages,omitempty"   yaml:"pages,omitempty"`
+
+	// OnSelect is the name of the action run when a row or item is selected.
+	OnSelect string `json:"onSelect,omitempty" yaml:"onSelect,omitempty"`
+	// OnSubmit is the name of the action run when a form is submitted.
+	OnSubmit string `json:"onSubmit,omitempty" yaml:"onSubmit,omitempty"`
+
+	// Options holds factory-specific settings for custom view types.
+	Options map[string]any `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// ActionDefinition binds a key to a named action from ScreenBindings.
+type ActionDefinition struct {
+	Key    string `json:"key"             yaml:"key"`
+	Label  string `json:"label,omitempty" yaml:"label,omitempty"`
+	Action string `json:"action"          yaml:"action"`
+}
+
+// ScreenData is the data a provider returns for a view. Only the fields used by the
+// view type need to be set.
+type ScreenData struct {
+	Rows       []TableRow
+	Content    string
+	Fields     []DetailField
+	Entity     types.Entity
+	Collection types.Collection
+}
+
+// DataProvider supplies the data for a view. It receives the selections made on prior
+// Library pages (empty for top-level views).
+type DataProvider func(selections []PageSelection) (ScreenData, error)
+
+// ActionContext describes the state in which an action was triggered.
+type ActionContext struct {
+	View tea.Model
+	// Selections holds the prior Library page selections followed by the current
+	// selection of the view, when the view is Selectable.
+	Selections []PageSelection
+	// Values holds submitted form values, or the selected item "id" for collections.
+	Values map[string]any
+}
+
+// ActionFunc is the Go implementation of a named action.
+type ActionFunc func(ctx ActionContext) error
+
+// ScreenBindings maps the provider and action names used in definitions to Go code.
+type ScreenBindings struct {
+	Providers map[string]DataProvider
+	Actions   map[string]ActionFunc
+}
+
+func (b *ScreenBindings) provide(name string, selections []PageSelection) (ScreenData, error) {
+	if name == "" {
+		return ScreenData{}, nil
+	}
+	if b == nil || b.Providers[name] == nil {
+		return ScreenData{}, fmt.Errorf("data provider %q is not bound", name)
+	}
+	return b.Providers[name](selections)
+}
+
+func (b *ScreenBindings) action(name string) (ActionFunc, error) {
+	if b == nil || b.Actions[name] == nil {
+		return nil, fmt.Errorf("action %q is not bound", name)
+	}
+	return b.Actions[name], nil
+}
+
+// LoadViewDefinition parses a view definition from YAML or JSON.
+func LoadViewDefinition(data []byte) (ViewDefinition, error) {
+	var def ViewDefinition
+	if err := yaml.Unmarshal(data, &def); err != nil {
+		return ViewDefinition{}, fmt.Errorf("unable to parse view definition: %w", err)
+	}
+	if err := def.Validate(); err != nil {
+		return ViewDefinition{}, err
+	}
+	return def, nil
+}
+
+// LoadViewDefinitionFile reads and parses a view definition from a YAML or JSON file.
+func LoadViewDefinitionFile(path string) (ViewDefinition, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return ViewDefinition{}, fmt.Errorf("unable to read view definition: %w", err)
+	}
+	return LoadViewDefinition(data)
+}
+
+// Validate checks the definition (and its pages) for missing required values.
+func (d ViewDefinition) Validate() error {
+	if d.Type == "" {
+		return fmt.Errorf("view definition %q is missing a type", d.Title)
+	}
+	for _, a := range d.Actions {
+		if a.Key == "" || a.Action == "" {
+			return fmt.Errorf("action in view %q must specify a key and an action", d.Title)
+		}
+	}
+	for _, p := range d.Pages {
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("invalid page: %w", err)
+		}
+	}
+	return nil
+}
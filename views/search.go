@@ -0,0 +1,264 @@
+package views
+
+import (
+	"regexp"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	"charm.land/bubbles/v2/viewport"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/flowexec/tuikit/i18n"
+	"github.com/flowexec/tuikit/themes"
+	"github.com/flowexec/tuikit/types"
+)
+
+// searchMatch is a match of a content search: a line and the cell range of the match
+// within it.
+type searchMatch struct {
+	line       int
+	start, end int
+}
+
+// contentSearch searches the rendered content of a viewport. "/" opens the prompt, and
+// matches are highlighted on top of the content's own styles. n and N move between
+// the matches once the query is submitted.
+type contentSearch struct {
+	input     textinput.Model
+	prompting bool
+
+	query         string
+	caseSensitive bool
+	regex         bool
+	pattern       *regexp.Regexp
+	err           error
+
+	matches []searchMatch
+	current int
+	// seek selects the first match in view on the next render; reveal scrolls to the
+	// current match.
+	seek, reveal bool
+	// content is the content last set on the viewport, which is only set again when
+	// the content or the search changes.
+	content string
+	stale   bool
+}
+
+func newContentSearch() contentSearch {
+	in := textinput.New()
+	in.Prompt = i18n.T(i18n.MsgSearchPrompt)
+	in.Placeholder = i18n.T(i18n.MsgSearchPlaceholder)
+	in.CharLimit = 256
+	return contentSearch{input: in}
+}
+
+// active reports whether the search bar is shown.
+func (s *contentSearch) active() bool {
+	return s.prompting || s.query != ""
+}
+
+// height is the number of lines taken by the search bar.
+func (s *contentSearch) height() int {
+	if s.active() {
+		return 1
+	}
+	return 0
+}
+
+func (s *contentSearch) open() tea.Cmd {
+	s.prompting = true
+	s.input.SetValue(s.query)
+	s.input.CursorEnd()
+	return s.input.Focus()
+}
+
+func (s *contentSearch) clear() {
+	s.prompting = false
+	s.input.Blur()
+	s.setQuery("")
+}
+
+// update handles a search key. It returns false when the key isn't a search key, so
+// that the view can handle it.
+func (s *contentSearch) update(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if !s.prompting {
+		switch msg.String() {
+		case "/":
+			return s.open(), true
+		case "n":
+			return nil, s.step(1)
+		case "N":
+			return nil, s.step(-1)
+		}
+		return nil, false
+	}
+
+	switch msg.String() {
+	case "esc":
+		s.clear()
+		return nil, true
+	case types.KeyEnter:
+		s.prompting = false
+		s.input.Blur()
+		if s.err != nil {
+			s.setQuery("")
+		}
+		return nil, true
+	case "alt+c":
+		s.caseSensitive = !s.caseSensitive
+		s.setQuery(s.query)
+		return nil, true
+	case "alt+r":
+		s.regex = !s.regex
+		s.setQuery(s.query)
+		return nil, true
+	}
+	var cmd tea.Cmd
+	s.input, cmd = s.input.Update(msg)
+	if s.input.Value() != s.query {
+		s.setQuery(s.input.Value())
+	}
+	return cmd, true
+}
+
+// setQuery compiles the query. An invalid regular expression keeps the error for the
+// search bar and clears the matches.
+func (s *contentSearch) setQuery(query string) {
+	s.query = query
+	s.pattern, s.err = nil, nil
+	s.matches = nil
+	s.seek, s.stale = true, true
+	if query == "" {
+		return
+	}
+	expr := query
+	if !s.regex {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if !s.caseSensitive {
+		expr = "(?i)" + expr
+	}
+	s.pattern, s.err = regexp.Compile(expr)
+}
+
+// step moves to the next match, or to the previous one when delta is negative. It
+// returns false when there is no query.
+func (s *contentSearch) step(delta int) bool {
+	if s.query == "" {
+		return false
+	}
+	if n := len(s.matches); n > 0 {
+		s.current = ((s.current+delta)%n + n) % n
+		s.reveal = true
+	}
+	return true
+}
+
+// render sets the content of the viewport with the matches highlighted, and scrolls to
+// the current match after it changed.
+func (s *contentSearch) render(vp *viewport.Model, content string, theme themes.Theme) {
+	if content == s.content && !s.stale && !s.reveal {
+		return
+	}
+	s.content, s.stale = content, false
+	if s.pattern == nil {
+		vp.SetContent(content)
+		return
+	}
+
+	lines := strings.Split(content, "\n")
+	s.matches = s.matches[:0]
+	lineMatches := make(map[int][]searchMatch)
+	for i, line := range lines {
+		plain := ansi.Strip(line)
+		for _, loc := range s.pattern.FindAllStringIndex(plain, -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+			start := ansi.StringWidth(plain[:loc[0]])
+			m := searchMatch{line: i, start: start, end: start + ansi.StringWidth(plain[loc[0]:loc[1]])}
+			s.matches = append(s.matches, m)
+			lineMatches[i] = append(lineMatches[i], m)
+		}
+	}
+	if s.seek {
+		s.seek = false
+		s.reveal = true
+		s.current = 0
+		for i, m := range s.matches {
+			if m.line >= vp.YOffset() {
+				s.current = i
+				break
+			}
+		}
+	}
+	s.current = min(s.current, max(len(s.matches)-1, 0))
+
+	cp := theme.ColorPalette()
+	matchStyle := lipgloss.NewStyle().Foreground(cp.BlackColor()).Background(cp.WarningColor())
+	currentStyle := lipgloss.NewStyle().Foreground(cp.BlackColor()).Background(cp.PrimaryColor()).Bold(true)
+	var current searchMatch
+	if len(s.matches) > 0 {
+		current = s.matches[s.current]
+	}
+	for i, matches := range lineMatches {
+		ranges := make([]lipgloss.Range, 0, len(matches))
+		for _, m := range matches {
+			style := matchStyle
+			if m == current {
+				style = currentStyle
+			}
+			ranges = append(ranges, lipgloss.NewRange(m.start, m.end, style))
+		}
+		lines[i] = lipgloss.StyleRanges(lines[i], ranges...)
+	}
+	vp.SetContentLines(lines)
+
+	if s.reveal && len(s.matches) > 0 {
+		vp.EnsureVisible(current.line, current.start, current.end)
+	}
+	s.reveal = false
+}
+
+// view renders the search bar: the prompt or the query, the match counter and the
+// state of the case and regex toggles.
+func (s *contentSearch) view(theme themes.Theme, width int) string {
+	cp := theme.ColorPalette()
+	var status string
+	switch {
+	case s.err != nil:
+		status = theme.RenderLevel(s.err.Error(), themes.OutputLevelError)
+	case s.query == "":
+	case len(s.matches) == 0:
+		status = theme.RenderLevel(i18n.T(i18n.MsgNoMatches), themes.OutputLevelWarning)
+	default:
+		status = i18n.Tf(i18n.MsgSearchMatch, s.current+1, len(s.matches))
+	}
+
+	toggle := func(label string, on bool) string {
+		if on {
+			return lipgloss.NewStyle().Foreground(cp.PrimaryColor()).Bold(true).Render(label)
+		}
+		return lipgloss.NewStyle().Foreground(cp.GrayColor()).Render(label)
+	}
+	toggles := toggle("Aa", s.caseSensitive) + " " + toggle(".*", s.regex)
+
+	query := s.input.View()
+	if !s.prompting {
+		query = s.input.Prompt + s.query
+	}
+	left := lipgloss.NewStyle().Foreground(cp.SecondaryColor()).Render(query)
+	right := strings.TrimSpace(status + "  " + toggles)
+	gap := max(width-ansi.StringWidth(left)-ansi.StringWidth(right), 1)
+	return ansi.Truncate(left+strings.Repeat(" ", gap)+right, width, "")
+}
+
+// searchHelpKeys are the help bindings of a view with a content search.
+func searchHelpKeys() []themes.HelpKey {
+	return []themes.HelpKey{
+		{Key: "/", Desc: i18n.T(i18n.HelpSearch)},
+		{Key: "n/N", Desc: i18n.T(i18n.HelpNextMatch)},
+	}
+}
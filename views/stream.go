@@ -0,0 +1,457 @@
+package views
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	stdIO "io"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/flowexec/tuikit/i18n"
+	"github.com/flowexec/tuikit/themes"
+	"github.com/flowexec/tuikit/types"
+)
+
+const StreamViewType = "stream"
+
+// DefaultStreamBufferSize is the number of lines a StreamView keeps by default.
+const DefaultStreamBufferSize = 10000
+
+// maxStreamLineLength is the longest line shown; longer lines are split into several.
+const maxStreamLineLength = 1024 * 1024
+
+// streamUpdateMsg tells a StreamView that lines are waiting to be shown.
+type streamUpdateMsg struct {
+	view *StreamView
+}
+
+// StreamView tails live output: subprocess pipes, log files or anything written to
+// its writers, including the output of a StdOutWriter whose logger writes to a pipe.
+// Each line is shown with the time it was read and the label of its stream. Lines
+// keep their ANSI colors, and logfmt and JSON log lines without colors are colored by
+// level. The view follows new lines until it is scrolled up.
+//
+// Lines can be added from any goroutine. Like the view's other state, the settings
+// and Clear are changed by the program's goroutine: call them before the program
+// starts or from Update.
+type StreamView struct {
+	render *types.RenderState
+
+	// mu guards the lines read by the stream goroutines until the view takes them.
+	mu        sync.Mutex
+	pending   []streamLine
+	capacity  int
+	notify    chan struct{}
+	listening bool // a command is waiting for lines
+	done      chan struct{}
+	closed    bool
+
+	buffer  *streamBuffer
+	top     int // absolute index of the first line in view
+	follow  bool
+	unread  int // lines received while paused
+	labels  map[string]int
+	showAt  bool
+	showTag bool
+}
+
+func NewStreamView(render *types.RenderState) *StreamView {
+	return &StreamView{
+		render:   render,
+		capacity: DefaultStreamBufferSize,
+		notify:   make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		buffer:   newStreamBuffer(DefaultStreamBufferSize),
+		follow:   true,
+		labels:   make(map[string]int),
+		showAt:   true,
+		showTag:  true,
+	}
+}
+
+// SetBufferSize sets the number of lines kept. Older lines are dropped. Call it
+// before the program starts or from Update.
+func (v *StreamView) SetBufferSize(size int) {
+	v.mu.Lock()
+	v.capacity = max(size, 1)
+	v.mu.Unlock()
+	v.buffer.resize(size)
+	v.top = max(v.top, v.buffer.first())
+}
+
+// SetShowTimestamps shows or hides the time each line was read.
+func (v *StreamView) SetShowTimestamps(show bool) {
+	v.showAt = show
+}
+
+// SetShowLabels shows or hides the stream label of each line.
+func (v *StreamView) SetShowLabels(show bool) {
+	v.showTag = show
+}
+
+// AddReader reads lines from r in the background and shows them with the label, e.g.
+// "stdout". A failure reading r is shown as an error line.
+func (v *StreamView) AddReader(label string, r stdIO.Reader) {
+	go func() {
+		err := readLines(r, func(text string) { v.append(label, text) })
+		if err != nil && !errors.Is(err, stdIO.ErrClosedPipe) {
+			v.appendLine(streamLine{
+				at:    time.Now(),
+				label: label,
+				text:  i18n.Tf(i18n.MsgStreamFailed, err),
+				level: themes.OutputLevelError,
+			})
+		}
+	}()
+}
+
+// readLines calls line for each line read from r until it ends. Lines longer than
+// maxStreamLineLength are split.
+func readLines(r stdIO.Reader, line func(text string)) error {
+	reader := bufio.NewReader(r)
+	var buf []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		buf = append(buf, chunk...)
+		if err == nil {
+			emitLine(buf[:len(buf)-1], line)
+			buf = buf[:0]
+			continue
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			buf = emitFullLines(buf, line)
+			continue
+		}
+		if len(buf) > 0 {
+			emitLine(buf, line)
+		}
+		if errors.Is(err, stdIO.EOF) {
+			return nil
+		}
+		return err
+	}
+}
+
+// emitLine calls line with b, split into lines of at most maxStreamLineLength.
+func emitLine(b []byte, line func(text string)) {
+	b = emitFullLines(b, line)
+	line(string(b))
+}
+
+// emitFullLines calls line with the leading maxStreamLineLength parts of b, keeping
+// multibyte characters whole, and returns the rest.
+func emitFullLines(b []byte, line func(text string)) []byte {
+	for len(b) > maxStreamLineLength {
+		n := maxStreamLineLength
+		for n > 0 && !utf8.RuneStart(b[n]) {
+			n--
+		}
+		if n == 0 {
+			n = maxStreamLineLength
+		}
+		line(string(b[:n]))
+		b = b[n:]
+	}
+	return b
+}
+
+// Writer returns a writer whose output is shown line by line with the label. A
+// trailing partial line is held until it is completed or longer than a line can be.
+// Writes never block on the view.
+func (v *StreamView) Writer(label string) stdIO.Writer {
+	return &streamWriter{line: func(text string) { v.append(label, text) }}
+}
+
+// Append shows a line with the label.
+func (v *StreamView) Append(label, text string) {
+	v.append(label, text)
+}
+
+// Clear removes all lines and resumes following. Lines written before Clear and not
+// yet shown are dropped too. Call it before the program starts or from Update.
+func (v *StreamView) Clear() {
+	v.mu.Lock()
+	v.pending = nil
+	capacity := v.capacity
+	v.mu.Unlock()
+	v.buffer = newStreamBuffer(capacity)
+	v.labels = make(map[string]int)
+	v.top = 0
+	v.setFollow(true)
+}
+
+func (v *StreamView) append(label, text string) {
+	text = strings.TrimSuffix(text, "\r")
+	line := streamLine{at: time.Now(), label: label, text: text}
+	if !strings.Contains(text, "\x1b[") {
+		line.level = lineLevel(text)
+	}
+	v.appendLine(line)
+}
+
+func (v *StreamView) appendLine(line streamLine) {
+	v.mu.Lock()
+	v.pending = append(v.pending, line)
+	if over := len(v.pending) - v.capacity; over > 0 {
+		v.pending = v.pending[over:]
+	}
+	v.mu.Unlock()
+	select {
+	case v.notify <- struct{}{}:
+	default:
+	}
+}
+
+// listen waits for lines to be appended. Only one command waits at a time; its
+// message may be dropped while another view is shown, so Init listens again.
+func (v *StreamView) listen() tea.Cmd {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.listening || v.closed {
+		return nil
+	}
+	v.listening = true
+	done := v.done
+	return func() tea.Msg {
+		v.mu.Lock()
+		waiting := len(v.pending) == 0
+		v.mu.Unlock()
+		if waiting {
+			select {
+			case <-v.notify:
+			case <-done:
+			}
+		}
+		v.mu.Lock()
+		v.listening = false
+		v.mu.Unlock()
+		return streamUpdateMsg{view: v}
+	}
+}
+
+// Close stops the view from waiting for new lines, so that no goroutine is left
+// blocked once the view is no longer shown. Lines added later are shown once Init is
+// called again.
+func (v *StreamView) Close() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if !v.closed {
+		v.closed = true
+		close(v.done)
+	}
+	return nil
+}
+
+// flush moves the pending lines to the buffer.
+func (v *StreamView) flush() {
+	v.mu.Lock()
+	pending := v.pending
+	v.pending = nil
+	v.mu.Unlock()
+
+	for _, line := range pending {
+		if _, ok := v.labels[line.label]; !ok {
+			v.labels[line.label] = len(v.labels)
+		}
+		v.buffer.push(line)
+	}
+	if v.follow {
+		v.top = v.maxTop()
+	} else {
+		v.unread += len(pending)
+		v.top = max(v.top, v.buffer.first())
+	}
+}
+
+func (v *StreamView) Init() tea.Cmd {
+	v.mu.Lock()
+	if v.closed {
+		v.closed = false
+		v.done = make(chan struct{})
+	}
+	v.mu.Unlock()
+	return v.listen()
+}
+
+func (v *StreamView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *types.RenderState:
+		v.render = msg
+		if v.follow {
+			v.top = v.maxTop()
+		}
+	case streamUpdateMsg:
+		if msg.view != v {
+			return v, nil
+		}
+		v.flush()
+		return v, v.listen()
+	case tea.KeyPressMsg:
+		halfPage := max(v.height()/2, 1)
+		switch msg.String() {
+		case types.KeyUp, "k":
+			v.scroll(-1)
+		case types.KeyDown, "j":
+			v.scroll(1)
+		case "pgup", "u":
+			v.scroll(-halfPage)
+		case "pgdown", "d":
+			v.scroll(halfPage)
+		case "g", "home":
+			v.scroll(-v.buffer.total)
+		case "G", "end":
+			v.setFollow(true)
+		case "f":
+			v.setFollow(!v.follow)
+		case "t":
+			v.showAt = !v.showAt
+		case "l":
+			v.showTag = !v.showTag
+		}
+	}
+	return v, nil
+}
+
+// scroll moves the view by delta lines. Scrolling up pauses following, and scrolling
+// to the bottom resumes it.
+func (v *StreamView) scroll(delta int) {
+	v.top = min(max(v.top+delta, v.buffer.first()), v.maxTop())
+	switch {
+	case delta < 0 && v.top < v.maxTop():
+		v.follow = false
+	case v.top == v.maxTop():
+		v.setFollow(true)
+	}
+}
+
+func (v *StreamView) setFollow(follow bool) {
+	v.follow = follow
+	if follow {
+		v.unread = 0
+		v.top = v.maxTop()
+	}
+}
+
+// Following reports whether the view follows new lines.
+func (v *StreamView) Following() bool {
+	return v.follow
+}
+
+// height is the number of lines shown, above the status line.
+func (v *StreamView) height() int {
+	return max(v.render.ContentHeight-1, 1)
+}
+
+func (v *StreamView) maxTop() int {
+	return max(v.buffer.total-v.height(), v.buffer.first())
+}
+
+func (v *StreamView) View() tea.View {
+	cp := v.render.Theme.ColorPalette()
+	width := v.render.ContentWidth
+	timeStyle := lipgloss.NewStyle().Foreground(cp.GrayColor())
+	labelColors := []lipgloss.Style{
+		lipgloss.NewStyle().Foreground(cp.SecondaryColor()),
+		lipgloss.NewStyle().Foreground(cp.TertiaryColor()),
+		lipgloss.NewStyle().Foreground(cp.InfoColor()),
+		lipgloss.NewStyle().Foreground(cp.PrimaryColor()),
+		lipgloss.NewStyle().Foreground(cp.EmphasisColor()),
+	}
+	labelWidth := 0
+	for label := range v.labels {
+		labelWidth = max(labelWidth, ansi.StringWidth(label))
+	}
+
+	rows := make([]string, 0, v.height())
+	for i := v.top; i < v.buffer.total && len(rows) < v.height(); i++ {
+		line := v.buffer.at(i)
+		var prefix strings.Builder
+		if v.showAt {
+			prefix.WriteString(timeStyle.Render(line.at.Format(time.TimeOnly)) + " ")
+		}
+		if v.showTag && labelWidth > 0 {
+			style := labelColors[v.labels[line.label]%len(labelColors)]
+			prefix.WriteString(style.Width(labelWidth).Render(line.label) + " ")
+		}
+		text := ansi.Truncate(line.text, max(width-ansi.StringWidth(prefix.String()), 0), "…")
+		switch {
+		case strings.Contains(text, "\x1b"):
+			text += ansi.ResetStyle
+		case line.level != "":
+			text = v.render.Theme.RenderLevel(text, line.level)
+		}
+		rows = append(rows, prefix.String()+text)
+	}
+	for len(rows) < v.height() {
+		rows = append(rows, "")
+	}
+	return tea.View{Content: strings.Join(rows, "\n") + "\n" + v.renderStatus()}
+}
+
+func (v *StreamView) renderStatus() string {
+	cp := v.render.Theme.ColorPalette()
+	var status string
+	if v.follow {
+		status = lipgloss.NewStyle().Foreground(cp.SuccessColor()).Render("● " + i18n.T(i18n.MsgFollowing))
+	} else {
+		status = lipgloss.NewStyle().Foreground(cp.WarningColor()).Render("‖ " + i18n.Tf(i18n.MsgPaused, v.unread))
+	}
+	count := lipgloss.NewStyle().Foreground(cp.GrayColor()).Render(i18n.Tf(i18n.MsgLineCount, v.buffer.count))
+	gap := max(v.render.ContentWidth-ansi.StringWidth(status)-ansi.StringWidth(count), 1)
+	return status + strings.Repeat(" ", gap) + count
+}
+
+func (v *StreamView) HelpBindings() []themes.HelpKey {
+	return []themes.HelpKey{
+		{Key: "↑/↓", Desc: i18n.T(i18n.HelpScroll)},
+		{Key: "u/d", Desc: i18n.T(i18n.HelpHalfPage)},
+		{Key: "g/G", Desc: i18n.T(i18n.HelpTopBottom)},
+		{Key: "f", Desc: i18n.T(i18n.HelpFollow)},
+		{Key: "t", Desc: i18n.T(i18n.HelpTimestamps)},
+		{Key: "l", Desc: i18n.T(i18n.HelpLabels)},
+	}
+}
+
+func (v *StreamView) Type() string {
+	return StreamViewType
+}
+
+// streamWriter splits what is written to it into lines.
+type streamWriter struct {
+	line    func(text string)
+	mu      sync.Mutex
+	partial []byte
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	data := append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		emitLine(data[:i], w.line)
+		data = data[i+1:]
+	}
+	w.partial = append([]byte(nil), emitFullLines(data, w.line)...)
+	return len(p), nil
+}
+
+// flush emits the partial line held, once nothing more will be written.
+func (w *streamWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.partial) > 0 {
+		emitLine(w.partial, w.line)
+		w.partial = nil
+	}
+}
@@ -0,0 +1,100 @@
+package views
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/flowexec/tuikit/themes"
+)
+
+// streamLine is a line read from a stream, with the time it was read.
+type streamLine struct {
+	at    time.Time
+	label string
+	text  string
+	level themes.OutputLevel
+}
+
+// streamBuffer is a ring buffer of the most recent lines of a stream. Lines are
+// addressed by their absolute index: the number of lines pushed before them.
+type streamBuffer struct {
+	lines []streamLine
+	start int // position of the oldest line in lines
+	count int
+	total int // lines pushed, including dropped ones
+}
+
+func newStreamBuffer(size int) *streamBuffer {
+	return &streamBuffer{lines: make([]streamLine, max(size, 1))}
+}
+
+func (b *streamBuffer) push(line streamLine) {
+	if b.count < len(b.lines) {
+		b.lines[(b.start+b.count)%len(b.lines)] = line
+		b.count++
+	} else {
+		b.lines[b.start] = line
+		b.start = (b.start + 1) % len(b.lines)
+	}
+	b.total++
+}
+
+// first is the absolute index of the oldest line kept.
+func (b *streamBuffer) first() int {
+	return b.total - b.count
+}
+
+// at returns the line with the absolute index, which must be kept.
+func (b *streamBuffer) at(index int) streamLine {
+	return b.lines[(b.start+index-b.first())%len(b.lines)]
+}
+
+// resize keeps the most recent lines that fit the new size.
+func (b *streamBuffer) resize(size int) {
+	resized := newStreamBuffer(size)
+	for i := max(b.first(), b.total-len(resized.lines)); i < b.total; i++ {
+		resized.push(b.at(i))
+	}
+	resized.total = b.total
+	*b = *resized
+}
+
+var (
+	logfmtLevelPattern = regexp.MustCompile(`(?:^|\s)(?:level|lvl|severity)=("?)([A-Za-z]+)`)
+	jsonLevelKeys      = []string{"level", "lvl", "severity"}
+)
+
+// lineLevel reads the level of a logfmt or JSON log line. It is empty for other lines
+// and for unknown levels.
+func lineLevel(text string) themes.OutputLevel {
+	var level string
+	if trimmed := strings.TrimSpace(text); strings.HasPrefix(trimmed, "{") {
+		var fields map[string]any
+		if json.Unmarshal([]byte(trimmed), &fields) == nil {
+			for _, key := range jsonLevelKeys {
+				if s, ok := fields[key].(string); ok {
+					level = s
+					break
+				}
+			}
+		}
+	} else if m := logfmtLevelPattern.FindStringSubmatch(text); m != nil {
+		level = m[2]
+	}
+
+	switch strings.ToLower(level) {
+	case "error", "err", "fatal", "panic", "critical", "crit":
+		return themes.OutputLevelError
+	case "warn", "warning":
+		return themes.OutputLevelWarning
+	case "notice":
+		return themes.OutputLevelNotice
+	case "success":
+		return themes.OutputLevelSuccess
+	case "info":
+		return themes.OutputLevelInfo
+	}
+	return ""
+}
@@ -1,13 +1,16 @@
 package views
 
 import (
-	"fmt"
 	"strings"
+	"time"
 
+	"charm.land/bubbles/v2/spinner"
 	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
 
+	"github.com/flowexec/tuikit/i18n"
 	"github.com/flowexec/tuikit/themes"
 	"github.com/flowexec/tuikit/types"
 )
@@ -22,14 +25,39 @@ const (
 )
 
 type TableRow struct {
+	// Key is an optional stable identifier used to match rows in live updates.
+	Key      string
 	Data     []string
 	Children []TableRow
 	Expanded bool
+	// Lazy marks a row whose children are loaded by Table.LoadChildren on first expansion.
+	Lazy bool
 }
 
 type TableColumn struct {
-	Title      string
-	Percentage int // width as percentage of total table width
+	Title      string `json:"title"                yaml:"title"`
+	Percentage int    `json:"percentage,omitempty" yaml:"percentage,omitempty"` // width as percentage of total table width
+
+	// Width is a fixed content width. It takes precedence over AutoWidth and Percentage.
+	Width int `json:"width,omitempty" yaml:"width,omitempty"`
+	// AutoWidth sizes the column to fit its widest title or cell.
+	AutoWidth bool `json:"autoWidth,omitempty" yaml:"autoWidth,omitempty"`
+	// MinWidth and MaxWidth bound the computed width. Columns are shrunk towards
+	// MinWidth before trailing columns are hidden on narrow terminals.
+	MinWidth int         `json:"minWidth,omitempty" yaml:"minWidth,omitempty"`
+	MaxWidth int         `json:"maxWidth,omitempty" yaml:"maxWidth,omitempty"`
+	Align    ColumnAlign `json:"align,omitempty"    yaml:"align,omitempty"`
+	// Wrap wraps long cell values onto multiple lines instead of truncating them.
+	Wrap bool `json:"wrap,omitempty" yaml:"wrap,omitempty"`
+	// Hidden hides the column until it is shown with the column chooser.
+	Hidden bool `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+
+	// Compare is used when sorting by this column. When nil, the cells are compared as
+	// numbers when all non-empty cells are numbers, as dates when they are all dates
+	// and as strings otherwise.
+	Compare CompareFunc `json:"-" yaml:"-"`
+	// Format formats cell values for display, e.g. FormatDuration or FormatBytes.
+	Format CellFormatter `json:"-" yaml:"-"`
 }
 
 type Table struct {
@@ -47,22 +75,63 @@ type Table struct {
 
 	// FilterFunc is an optional custom filter. When set, it receives the
 	// query string and a row's data and returns true if the row matches.
-	// When nil, a default case-insensitive substring match on all cells is used.
+	// When nil, the query is parsed with ParseFilterQuery and matches are highlighted.
 	FilterFunc func(query string, row []string) bool
+	// FuzzyFilter matches unquoted filter terms as fuzzy subsequences instead of
+	// substrings.
+	FuzzyFilter bool
+
+	// MultiSelect enables marking rows with space or m. Tab still expands rows.
+	MultiSelect bool
+	// BulkActions are run with the marked rows when their key is pressed.
+	BulkActions []TableBulkAction
+	// LoadChildren loads the children of Lazy rows.
+	LoadChildren ChildLoader
+	// RowStyler and CellStyler style rows and cells, e.g. to color failed runs. The
+	// selected and marked row styles take precedence.
+	RowStyler  RowStyler
+	CellStyler CellStyler
+	// FrozenColumns is the number of leading columns that stay in place when the
+	// columns are scrolled horizontally.
+	FrozenColumns int
+	// HighlightDuration is how long cells changed by live updates stay highlighted.
+	// When zero, DefaultHighlightDuration is used.
+	HighlightDuration time.Duration
+
+	sortColumn int
+	sortOrder  SortOrder
+	marked     map[rowKey]bool
+	loading    map[rowKey]int // in-flight child loads by row path
+	loads      int
+	spinner    spinner.Model
+	source     *tableSource
+	changes    map[string]cellChange
+
+	// contentWidths caches the content width of AutoWidth columns until the rows change.
+	contentWidths      map[int]int
+	contentWidthsMulti bool
+
+	order        []int
+	hidden       map[int]bool
+	columnOffset int
+	chooser      *columnChooser
 
 	showBorder      bool
 	filtering       bool
 	filterInput     textinput.Model
 	filterQuery     string
 	prevFilterQuery string
+	filterExpr      *FilterQuery
+	filterErr       error
+	exportPrompt    exportPrompt
 }
 
 type VisibleRow struct {
-	data      []string
-	isChild   bool
-	parentIdx int
-	childIdx  int
-	rowIdx    int // index in original rows slice (-1 for children)
+	data  []string
+	path  []int  // indices from the top-level rows down to this row
+	guide string // tree guides rendered before nested rows
+	// placeholder is set for data source rows whose page has not been loaded yet.
+	placeholder bool
 }
 
 func (vr *VisibleRow) Data() []string {
@@ -71,22 +140,42 @@ func (vr *VisibleRow) Data() []string {
 
 func NewTable(render *types.RenderState, columns []TableColumn, rows []TableRow, mode TableDisplayMode) *Table {
 	fi := textinput.New()
-	fi.Prompt = "Filter: "
-	fi.Placeholder = "filter..."
+	fi.Prompt = i18n.T(i18n.MsgFilterPrompt)
+	fi.Placeholder = i18n.T(i18n.MsgFilterPlaceholder)
 	fi.CharLimit = 64
+	spin := spinner.New()
+	spin.Spinner = spinner.MiniDot
+	if render != nil {
+		spin.Style = render.Theme.SpinnerStyle()
+	}
 	t := &Table{
-		render:      render,
-		columns:     columns,
-		rows:        rows,
-		displayMode: mode,
-		showBorder:  mode == TableDisplayMini,
-		filterInput: fi,
+		render:       render,
+		columns:      columns,
+		rows:         rows,
+		displayMode:  mode,
+		showBorder:   mode == TableDisplayMini,
+		filterInput:  fi,
+		exportPrompt: newExportPrompt(true),
+		sortColumn:   -1,
+		marked:       make(map[rowKey]bool),
+		loading:      make(map[rowKey]int),
+		changes:      make(map[string]cellChange),
+		hidden:       make(map[int]bool),
+		spinner:      spin,
+	}
+	for i, col := range columns {
+		if col.Hidden {
+			t.hidden[i] = true
+		}
 	}
 	t.buildVisibleRows()
 	return t
 }
 
 func (t *Table) Init() tea.Cmd {
+	if t.source != nil {
+		return t.reloadSource()
+	}
 	return nil
 }
 
@@ -94,11 +183,51 @@ func (t *Table) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case *types.RenderState:
 		t.render = msg
+		t.invalidateContentWidths()
+	case childrenLoadedMsg:
+		if msg.table == t {
+			return t, t.handleChildrenLoaded(msg)
+		}
+	case spinner.TickMsg:
+		return t, t.updateSpinner(msg)
+	case sourceCountMsg:
+		if msg.table == t {
+			return t, t.handleSourceMsg(msg)
+		}
+	case sourcePageMsg:
+		if msg.table == t {
+			return t, t.handleSourceMsg(msg)
+		}
+	case filterDebounceMsg:
+		if msg.table == t {
+			return t, t.handleSourceMsg(msg)
+		}
+	case TableUpsertMsg:
+		return t, t.UpsertRows(msg.Rows...)
+	case TableDeleteMsg:
+		t.DeleteRows(msg.Keys...)
+	case highlightExpiredMsg:
+		if msg.table == t {
+			t.expireHighlights()
+		}
 	case tea.KeyPressMsg:
-		if t.filtering {
-			return t, t.handleFilterKeyMsg(msg)
+		var cmd tea.Cmd
+		switch {
+		case t.chooser != nil:
+			t.handleChooserKeyMsg(msg)
+		case t.exportPrompt.active:
+			cmd = t.exportPrompt.update(msg, func(children bool) exportJob {
+				return t.exportSnapshot(ExportOptions{IncludeChildren: children, Theme: t.render.Theme})
+			})
+		case t.filtering:
+			cmd = t.handleFilterKeyMsg(msg)
+		default:
+			cmd = t.handleKeyMsg(msg)
+		}
+		if t.source != nil {
+			cmd = tea.Batch(cmd, t.prefetchPages())
 		}
-		return t, t.handleKeyMsg(msg)
+		return t, cmd
 	}
 	return t, nil
 }
@@ -111,19 +240,65 @@ func (t *Table) handleKeyMsg(msg tea.KeyPressMsg) tea.Cmd {
 		t.moveCursor(1)
 	case types.KeyEnter:
 		return t.selectRow()
-	case "space", "tab":
-		t.toggleExpansion()
-		t.buildVisibleRows()
-		t.ensureSelectedVisible()
+	case "space":
+		if t.MultiSelect {
+			t.toggleMark()
+		} else {
+			return t.expandSelected()
+		}
+	case "m":
+		if t.MultiSelect {
+			t.toggleMark()
+		}
+	case "tab":
+		return t.expandSelected()
+	case "+":
+		t.ExpandAll()
+	case "-":
+		t.CollapseAll()
+	case "ctrl+a":
+		if t.MultiSelect && t.source == nil {
+			t.toggleMarkAll()
+		}
+	case "*":
+		if t.MultiSelect && t.source == nil {
+			t.invertMarks()
+		}
 	case "/":
+		if t.source != nil && !t.source.supportsFilter() {
+			return nil
+		}
 		t.prevFilterQuery = t.filterQuery
 		t.filtering = true
 		t.filterInput.Focus()
 		t.filterInput.SetValue(t.filterQuery)
+	case "s":
+		return t.cycleSortOrder()
+	case "S":
+		return t.nextSortColumn()
+	case "ctrl+s":
+		return t.exportPrompt.open()
+	case "<", "shift+left":
+		t.ScrollColumns(-1)
+	case ">", "shift+right":
+		t.ScrollColumns(1)
+	case "C":
+		t.openColumnChooser()
+	default:
+		if action, ok := t.bulkAction(msg.String()); ok {
+			return t.runBulkAction(action)
+		}
 	}
 	return nil
 }
 
+func (t *Table) expandSelected() tea.Cmd {
+	cmd := t.toggleExpansion()
+	t.buildVisibleRows()
+	t.ensureSelectedVisible()
+	return cmd
+}
+
 func (t *Table) handleFilterKeyMsg(msg tea.KeyPressMsg) tea.Cmd {
 	switch msg.String() {
 	case "esc":
@@ -131,38 +306,60 @@ func (t *Table) handleFilterKeyMsg(msg tea.KeyPressMsg) tea.Cmd {
 		t.filterQuery = t.prevFilterQuery
 		t.filtering = false
 		t.filterInput.Blur()
-		t.buildVisibleRows()
-		t.selectedIndex = 0
-		t.scrollOffset = 0
-		return nil
+		return t.applyFilter(false)
 	case types.KeyEnter:
 		// Accept the current filter.
 		t.filterQuery = t.filterInput.Value()
 		t.filtering = false
 		t.filterInput.Blur()
-		t.buildVisibleRows()
-		t.selectedIndex = 0
-		t.scrollOffset = 0
-		return nil
+		return t.applyFilter(false)
 	}
 	// Forward to textinput.
 	var cmd tea.Cmd
 	t.filterInput, cmd = t.filterInput.Update(msg)
 	// Live-filter as the user types.
 	t.filterQuery = t.filterInput.Value()
+	return tea.Batch(cmd, t.applyFilter(true))
+}
+
+// applyFilter rebuilds the rows for the current filter query. Tables backed by a data
+// source query the source instead; live input is debounced.
+func (t *Table) applyFilter(live bool) tea.Cmd {
+	t.parseFilter()
+	if t.source != nil {
+		if live {
+			return t.debounceFilter()
+		}
+		t.source.filterSeq++ // drop any pending debounced query
+		t.selectedIndex, t.scrollOffset = 0, 0
+		return t.reloadSource()
+	}
 	t.buildVisibleRows()
 	t.selectedIndex = 0
 	t.scrollOffset = 0
-	return cmd
+	return nil
+}
+
+// parseFilter parses the filter query. When the query is invalid, the last valid
+// query stays applied and the error is shown in the filter bar.
+func (t *Table) parseFilter() {
+	if t.FilterFunc != nil {
+		return
+	}
+	expr, err := ParseFilterQuery(t.filterQuery, t.columns, t.FuzzyFilter)
+	t.filterErr = err
+	if err == nil {
+		t.filterExpr = expr
+	}
 }
 
 func (t *Table) CapturingInput() bool {
-	return t.filtering
+	return t.filtering || t.exportPrompt.active || t.chooser != nil
 }
 
 func (t *Table) moveCursor(delta int) {
 	next := t.selectedIndex + delta
-	if next < 0 || next >= len(t.visibleRows) {
+	if next < 0 || next >= t.rowCount() {
 		return
 	}
 	t.selectedIndex = next
@@ -187,7 +384,7 @@ func (t *Table) selectRow() tea.Cmd {
 
 func (t *Table) View() tea.View {
 	if t.render == nil {
-		return tea.View{Content: "No data"}
+		return tea.View{Content: i18n.T(i18n.MsgNoRows)}
 	}
 
 	tableWidth := t.calculateTableWidth()
@@ -195,44 +392,55 @@ func (t *Table) View() tea.View {
 
 	var content strings.Builder
 
-	if len(t.visibleRows) == 0 {
+	if t.rowCount() == 0 {
 		cp := t.render.Theme.ColorPalette()
+		msg := i18n.T(i18n.MsgNoMatches)
+		if t.source != nil && t.source.count < 0 {
+			msg = i18n.T(i18n.MsgLoading)
+		}
 		noMatch := lipgloss.NewStyle().
 			Foreground(cp.GrayColor()).
 			Width(tableWidth).
 			Align(lipgloss.Center).
-			Render("No matches")
+			Render(msg)
 		content.WriteString(noMatch)
 	} else {
+		scrollLeft, scrollRight := t.scrollIndicators(colWidths)
+		gutters := scrollLeft || scrollRight
 		header := t.renderHeader(colWidths)
+		if gutters {
+			header = t.addScrollGutters(header, scrollLeft, scrollRight)
+		}
 		content.WriteString(header)
 		content.WriteString("\n")
 
-		maxRows := t.maxVisibleRows()
 		start := t.scrollOffset
-		end := min(start+maxRows, len(t.visibleRows))
+		end := t.visibleEnd(start, colWidths)
 
 		if start > 0 {
 			scrollHint := lipgloss.NewStyle().
 				Foreground(t.render.Theme.ColorPalette().GrayColor()).
 				Width(tableWidth).Align(lipgloss.Center).
-				Render(fmt.Sprintf("↑ %d more", start))
+				Render(i18n.Tf(i18n.MsgMoreAbove, start))
 			content.WriteString(scrollHint)
 			content.WriteString("\n")
 		}
 
 		for i := start; i < end; i++ {
-			rowStr := t.renderRow(t.visibleRows[i], colWidths, i == t.selectedIndex)
+			rowStr := t.renderRow(t.rowAt(i), colWidths, i == t.selectedIndex)
+			if gutters {
+				rowStr = t.addScrollGutters(rowStr, false, false)
+			}
 			content.WriteString(rowStr)
 			content.WriteString("\n")
 		}
 
-		remaining := len(t.visibleRows) - end
+		remaining := t.rowCount() - end
 		if remaining > 0 {
 			scrollHint := lipgloss.NewStyle().
 				Foreground(t.render.Theme.ColorPalette().GrayColor()).
 				Width(tableWidth).Align(lipgloss.Center).
-				Render(fmt.Sprintf("↓ %d more", remaining))
+				Render(i18n.Tf(i18n.MsgMoreBelow, remaining))
 			content.WriteString(scrollHint)
 			content.WriteString("\n")
 		}
@@ -244,30 +452,36 @@ func (t *Table) View() tea.View {
 		result = t.renderMiniTable(result, tableWidth)
 	}
 
-	// Render filter bar at the bottom when active or when a filter is set.
-	var filterBar string
-	if t.filtering {
-		filterBar = t.renderFilterBar(tableWidth)
+	// Render the selection count and filter bar at the bottom when set.
+	cp := t.render.Theme.ColorPalette()
+	var footer []string
+	if status := t.renderSelectionStatus(); status != "" {
+		footer = append(footer, lipgloss.NewStyle().Foreground(cp.SecondaryColor()).Render(status))
+	}
+	if t.exportPrompt.active {
+		footer = append(footer, t.exportPrompt.view(t.render.Theme, tableWidth))
+	} else if t.filtering {
+		footer = append(footer, t.renderFilterBar(tableWidth))
 	} else if t.filterQuery != "" {
-		cp := t.render.Theme.ColorPalette()
-		filterBar = lipgloss.NewStyle().
+		summary := lipgloss.NewStyle().
 			Foreground(cp.GrayColor()).
-			Render(fmt.Sprintf("Filter: %s  (/ to edit, esc to clear)", t.filterQuery))
+			Render(i18n.Tf(i18n.MsgFilterSummary, t.filterQuery))
+		footer = append(footer, summary+t.renderFilterError())
 	}
 
 	// Pad to fill available height so the table occupies the full content area.
-	tableHeight := t.render.ContentHeight
-	if filterBar != "" {
-		tableHeight-- // reserve 1 line for filter bar
-	}
+	tableHeight := t.render.ContentHeight - len(footer) // reserve 1 line per footer item
 	rendered := lipgloss.NewStyle().
 		MarginLeft(2).
 		Width(t.render.ContentWidth - 2).
 		Height(tableHeight).
 		Render(result)
 
-	if filterBar != "" {
-		rendered = rendered + "\n" + lipgloss.NewStyle().MarginLeft(2).Render(filterBar)
+	for _, line := range footer {
+		rendered = rendered + "\n" + lipgloss.NewStyle().MarginLeft(2).Render(line)
+	}
+	if t.chooser != nil {
+		rendered = t.overlayColumnChooser(rendered)
 	}
 
 	return tea.View{Content: rendered}
@@ -278,16 +492,61 @@ func (t *Table) renderFilterBar(width int) string {
 	return lipgloss.NewStyle().
 		Foreground(cp.SecondaryColor()).
 		Width(width).
-		Render(t.filterInput.View())
+		Render(t.filterInput.View() + t.renderFilterError())
+}
+
+func (t *Table) renderFilterError() string {
+	if t.filterErr == nil {
+		return ""
+	}
+	return "  " + lipgloss.NewStyle().
+		Foreground(t.render.Theme.ColorPalette().ErrorColor()).
+		Render(t.filterErr.Error())
+}
+
+// highlightMatches styles the parts of a cell matched by the filter query.
+func (t *Table) highlightMatches(column int, value string) string {
+	if t.filterExpr == nil || t.FilterFunc != nil || strings.TrimSpace(t.filterQuery) == "" {
+		return value
+	}
+	style := lipgloss.NewStyle().
+		Foreground(t.render.Theme.ColorPalette().WarningColor()).
+		Underline(true)
+	return t.filterExpr.Highlight(column, value, style)
 }
 
 func (t *Table) HelpBindings() []themes.HelpKey {
-	return []themes.HelpKey{
-		{Key: "↑/↓/j/k", Desc: "navigate"},
-		{Key: "enter", Desc: "select"},
-		{Key: "space/tab", Desc: "expand/collapse"},
-		{Key: "/", Desc: "filter"},
+	keys := []themes.HelpKey{
+		{Key: "↑/↓/j/k", Desc: i18n.T(i18n.HelpNavigate)},
+		{Key: "enter", Desc: i18n.T(i18n.HelpSelect)},
+	}
+	if t.MultiSelect {
+		keys = append(keys,
+			themes.HelpKey{Key: "tab", Desc: i18n.T(i18n.HelpExpandCollapse)},
+			themes.HelpKey{Key: "space/m", Desc: i18n.T(i18n.HelpMark)},
+		)
+		if t.source == nil {
+			keys = append(keys,
+				themes.HelpKey{Key: "ctrl+a", Desc: i18n.T(i18n.HelpSelectAll)},
+				themes.HelpKey{Key: "*", Desc: i18n.T(i18n.HelpInvert)},
+			)
+		}
+	} else {
+		keys = append(keys, themes.HelpKey{Key: "space/tab", Desc: i18n.T(i18n.HelpExpandCollapse)})
+	}
+	keys = append(keys,
+		themes.HelpKey{Key: "+/-", Desc: i18n.T(i18n.HelpExpandAll)},
+		themes.HelpKey{Key: "/", Desc: i18n.T(i18n.HelpFilter)},
+		themes.HelpKey{Key: "s", Desc: i18n.T(i18n.HelpSort)},
+		themes.HelpKey{Key: "S", Desc: i18n.T(i18n.HelpSortColumn)},
+		themes.HelpKey{Key: "</>", Desc: i18n.T(i18n.HelpScrollColumns)},
+		themes.HelpKey{Key: "C", Desc: i18n.T(i18n.HelpColumns)},
+		themes.HelpKey{Key: "ctrl+s", Desc: i18n.T(i18n.HelpExport)},
+	)
+	for _, a := range t.BulkActions {
+		keys = append(keys, themes.HelpKey{Key: a.Key, Desc: a.Label})
 	}
+	return keys
 }
 
 func (t *Table) Type() string {
@@ -304,15 +563,38 @@ func (t *Table) SetOnHover(callback func(index int)) {
 
 func (t *Table) SetRows(rows []TableRow) {
 	t.rows = rows
+	t.invalidateContentWidths()
 	t.selectedIndex = 0
+	t.ClearMarks()
+	t.loading = make(map[rowKey]int)
+	t.changes = make(map[string]cellChange)
 	t.buildVisibleRows()
 }
 
 func (t *Table) GetSelectedRow() *VisibleRow {
-	if t.selectedIndex >= 0 && t.selectedIndex < len(t.visibleRows) {
-		return &t.visibleRows[t.selectedIndex]
+	if t.selectedIndex < 0 || t.selectedIndex >= t.rowCount() {
+		return nil
 	}
-	return nil
+	if t.source != nil {
+		row := t.sourceRow(t.selectedIndex)
+		return &row
+	}
+	return &t.visibleRows[t.selectedIndex]
+}
+
+// rowCount is the number of rows that can be navigated to.
+func (t *Table) rowCount() int {
+	if t.source != nil {
+		return max(t.source.count, 0)
+	}
+	return len(t.visibleRows)
+}
+
+func (t *Table) rowAt(i int) VisibleRow {
+	if t.source != nil {
+		return t.sourceRow(i)
+	}
+	return t.visibleRows[i]
 }
 
 func (t *Table) calculateTableWidth() int {
@@ -327,24 +609,6 @@ func (t *Table) calculateTableWidth() int {
 	return t.render.ContentWidth - 2
 }
 
-func (t *Table) calculateColumnWidths(totalWidth int) []int {
-	widths := make([]int, len(t.columns))
-	usedWidth := 0
-
-	for i, col := range t.columns {
-		if i == len(t.columns)-1 {
-			// last column gets remaining width
-			widths[i] = totalWidth - usedWidth
-		} else {
-			width := (totalWidth * col.Percentage) / 100
-			widths[i] = width
-			usedWidth += width
-		}
-	}
-
-	return widths
-}
-
 func (t *Table) renderHeader(colWidths []int) string {
 	var header string
 
@@ -355,79 +619,98 @@ func (t *Table) renderHeader(colWidths []int) string {
 		BorderBottomForeground(t.render.Theme.ColorPalette().BorderColor()).
 		Foreground(t.render.Theme.ColorPalette().PrimaryColor())
 
-	for i, col := range t.columns {
-		title := col.Title
-		if len(title) > colWidths[i]-1 {
-			title = title[:colWidths[i]-4] + "..."
+	for _, i := range t.VisibleColumns() {
+		col := t.columns[i]
+		if colWidths[i] <= 0 {
+			continue
 		}
+		indicator := t.sortIndicator(i)
+		maxLen := colWidths[i] - columnGap
+		titleLen := maxLen - ansi.StringWidth(indicator)
+		title := ansi.Truncate(col.Title, titleLen, truncationTail(titleLen)) + indicator
 
-		cellContent := style.Width(colWidths[i] - 1).Render(title)
+		cellContent := style.Width(maxLen).Align(col.Align.position()).Render(title)
 		header = lipgloss.JoinHorizontal(lipgloss.Right, header, cellContent)
 	}
 
 	return header
 }
 
-func (t *Table) rowStyle(row VisibleRow, selected bool) lipgloss.Style {
-	cp := t.render.Theme.ColorPalette()
-	switch {
-	case selected:
-		return lipgloss.NewStyle().
-			Background(cp.PrimaryColor()).
-			Foreground(cp.GrayColor()).Bold(true)
-	case row.isChild:
-		return lipgloss.NewStyle().Foreground(cp.TertiaryColor())
-	default:
-		return lipgloss.NewStyle().Foreground(cp.BodyColor())
+func (t *Table) renderRow(row VisibleRow, colWidths []int, selected bool) string {
+	style := t.rowStyle(row, selected)
+	if row.placeholder {
+		width := 0
+		for _, w := range colWidths {
+			width += w
+		}
+		return style.Foreground(t.render.Theme.ColorPalette().GrayColor()).
+			Width(max(width-columnGap, 1)).
+			Render(t.cellPrefix(row, selected) + i18n.T(i18n.MsgLoading))
 	}
-}
-
-func (t *Table) cellPrefix(row VisibleRow, colIdx int, selected bool) string {
-	if colIdx != 0 {
-		return ""
+	cells := t.cellContents(row, colWidths, selected)
+	height := 1
+	for _, c := range cells {
+		height = max(height, lipgloss.Height(c))
 	}
-	if row.isChild {
-		if selected {
-			return "  > "
+
+	rendered := make([]string, 0, len(cells))
+	for _, i := range t.VisibleColumns() {
+		if colWidths[i] <= 0 {
+			continue
 		}
-		return "    "
-	}
-	if row.rowIdx < 0 {
-		return ""
+		content := cells[i]
+		cellStyle := t.cellStyle(style, row, i, selected).
+			Width(colWidths[i] - columnGap).
+			Align(t.columns[i].Align.position())
+		if height > 1 {
+			cellStyle = cellStyle.Height(height)
+		}
+		rendered = append(rendered, cellStyle.Render(content))
 	}
-	children := t.rows[row.rowIdx].Children
-	switch {
-	case len(children) > 0 && t.rows[row.rowIdx].Expanded:
-		return "◉ "
-	case len(children) > 0:
-		return "● "
-	default:
-		return "◌ "
+	if height > 1 {
+		return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
 	}
+	return strings.Join(rendered, "")
 }
 
-func (t *Table) renderRow(row VisibleRow, colWidths []int, selected bool) string {
-	var rowStr strings.Builder
-	style := t.rowStyle(row, selected)
-
-	for i, cellData := range row.data {
-		if i >= len(colWidths) {
-			break
+// cellContents returns the prefixed and formatted content of each column's cell,
+// truncated or wrapped to the column width. Cells of columns that aren't rendered
+// are empty.
+func (t *Table) cellContents(row VisibleRow, colWidths []int, selected bool) []string {
+	cells := make([]string, len(t.columns))
+	prefixed := t.prefixColumn(colWidths)
+	for i := range t.columns {
+		if i >= len(colWidths) || colWidths[i] <= 0 {
+			continue
 		}
 
-		content := t.cellPrefix(row, i, selected) + cellData
-		maxLen := colWidths[i] - 1
-		if len(content) > maxLen && maxLen > 3 {
-			content = content[:maxLen-3] + "..."
-		} else if len(content) > maxLen {
-			content = content[:maxLen]
+		value := t.formatCell(i, cellValue(row.data, i))
+		if selected {
+			// Formatter styling would reset the selected row's background.
+			value = ansi.Strip(value)
+		} else {
+			value = t.highlightMatches(i, value)
 		}
-
-		cellContent := style.Width(colWidths[i] - 1).Render(content)
-		rowStr.WriteString(cellContent)
+		content := value
+		if i == prefixed {
+			content = t.cellPrefix(row, selected) + value
+		}
+		maxLen := colWidths[i] - columnGap
+		if t.columns[i].Wrap {
+			content = ansi.Wrap(content, maxLen, "")
+		} else {
+			content = ansi.Truncate(content, maxLen, truncationTail(maxLen))
+		}
+		cells[i] = content
 	}
+	return cells
+}
 
-	return rowStr.String()
+func truncationTail(width int) string {
+	if width > 3 {
+		return "..."
+	}
+	return ""
 }
 
 func (t *Table) renderMiniTable(content string, tableWidth int) string {
@@ -449,27 +732,54 @@ func (t *Table) renderMiniTable(content string, tableWidth int) string {
 
 func (t *Table) maxVisibleRows() int {
 	if t.render == nil || t.render.ContentHeight <= 0 {
-		return len(t.visibleRows)
+		return t.rowCount()
 	}
+	return min(t.availableLines(), t.rowCount())
+}
+
+// availableLines is the number of lines available for rows.
+func (t *Table) availableLines() int {
 	// Reserve lines for: header (2 lines: title + border), scroll hints (up to 2 lines)
 	available := t.render.ContentHeight - 2
 	if t.filtering || t.filterQuery != "" {
 		available-- // filter bar
 	}
+	if len(t.marked) > 0 {
+		available-- // selection count
+	}
+	if t.exportPrompt.active && !t.filtering && t.filterQuery == "" {
+		available-- // export prompt
+	}
 	if t.displayMode == TableDisplayMini {
 		// Mini mode border + padding takes extra space
 		available -= 4
 	}
-	if available < 1 {
-		available = 1
+	return max(available, 1)
+}
+
+// visibleEnd returns the index after the last row that fits when rendering from start.
+func (t *Table) visibleEnd(start int, colWidths []int) int {
+	if !t.hasWrappedColumns() {
+		return min(start+t.maxVisibleRows(), t.rowCount())
 	}
-	if available >= len(t.visibleRows) {
-		return len(t.visibleRows)
+	available := t.availableLines()
+	lines, end := 0, start
+	for end < t.rowCount() {
+		h := t.rowHeight(t.rowAt(end), colWidths)
+		if lines+h > available && end > start {
+			break
+		}
+		lines += h
+		end++
 	}
-	return available
+	return end
 }
 
 func (t *Table) ensureSelectedVisible() {
+	if t.hasWrappedColumns() && t.render != nil && t.render.ContentHeight > 0 {
+		t.ensureSelectedVisibleWrapped()
+		return
+	}
 	maxRows := t.maxVisibleRows()
 	if t.selectedIndex < t.scrollOffset {
 		t.scrollOffset = t.selectedIndex
@@ -481,73 +791,14 @@ func (t *Table) ensureSelectedVisible() {
 	}
 }
 
-func (t *Table) buildVisibleRows() {
-	t.visibleRows = make([]VisibleRow, 0)
-	query := strings.TrimSpace(t.filterQuery)
-
-	for i, row := range t.rows {
-		if query == "" {
-			t.appendUnfilteredRow(i, row)
-		} else {
-			t.appendFilteredRow(i, row, query)
-		}
-	}
-
-	if t.selectedIndex >= len(t.visibleRows) {
-		t.selectedIndex = len(t.visibleRows) - 1
-	}
-	if t.selectedIndex < 0 {
-		t.selectedIndex = 0
-	}
-}
-
-func (t *Table) appendUnfilteredRow(i int, row TableRow) {
-	t.visibleRows = append(t.visibleRows, VisibleRow{
-		data: row.Data, parentIdx: -1, childIdx: -1, rowIdx: i,
-	})
-	if row.Expanded {
-		for j, child := range row.Children {
-			t.visibleRows = append(t.visibleRows, VisibleRow{
-				data: child.Data, isChild: true, parentIdx: i, childIdx: j, rowIdx: -1,
-			})
-		}
-	}
-}
-
-func (t *Table) appendFilteredRow(i int, row TableRow, query string) {
-	parentMatches := t.matchesFilter(query, row.Data)
-	var matchingChildren []int
-	for j, child := range row.Children {
-		if t.matchesFilter(query, child.Data) {
-			matchingChildren = append(matchingChildren, j)
-		}
-	}
-
-	if !parentMatches && len(matchingChildren) == 0 {
-		return
-	}
-
-	t.visibleRows = append(t.visibleRows, VisibleRow{
-		data: row.Data, parentIdx: -1, childIdx: -1, rowIdx: i,
-	})
-	for _, j := range matchingChildren {
-		t.visibleRows = append(t.visibleRows, VisibleRow{
-			data: row.Children[j].Data, isChild: true, parentIdx: i, childIdx: j, rowIdx: -1,
-		})
-	}
-}
-
 func (t *Table) matchesFilter(query string, data []string) bool {
 	if t.FilterFunc != nil {
 		return t.FilterFunc(query, data)
 	}
-	q := strings.ToLower(query)
-	for _, cell := range data {
-		if strings.Contains(strings.ToLower(cell), q) {
-			return true
-		}
+	if t.filterExpr == nil {
+		return true
 	}
-	return false
+	return t.filterExpr.Match(data)
 }
 
 func (t *Table) SelectedIndex() int {
@@ -560,29 +811,3 @@ func (t *Table) SelectedData() []string {
 	}
 	return nil
 }
-
-func (t *Table) toggleExpansion() {
-	if t.selectedIndex < 0 || t.selectedIndex >= len(t.visibleRows) {
-		return
-	}
-
-	selectedRow := t.visibleRows[t.selectedIndex]
-	if selectedRow.isChild || selectedRow.rowIdx < 0 {
-		return
-	}
-
-	rowIdx := selectedRow.rowIdx
-	if rowIdx >= len(t.rows) {
-		return
-	}
-	if len(t.rows[rowIdx].Children) == 0 {
-		return
-	}
-
-	for i := range t.rows {
-		if i != rowIdx {
-			t.rows[i].Expanded = false
-		}
-	}
-	t.rows[rowIdx].Expanded = !t.rows[rowIdx].Expanded
-}
@@ -0,0 +1,290 @@
+package views
+
+import (
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ColumnAlign is the horizontal alignment of a column's cells.
+type ColumnAlign string
+
+const (
+	AlignLeft   ColumnAlign = "left"
+	AlignRight  ColumnAlign = "right"
+	AlignCenter ColumnAlign = "center"
+)
+
+func (a ColumnAlign) position() lipgloss.Position {
+	switch a {
+	case AlignRight:
+		return lipgloss.Right
+	case AlignCenter:
+		return lipgloss.Center
+	default:
+		return lipgloss.Left
+	}
+}
+
+// defaultMinColumnWidth is the width a column without a MinWidth can be shrunk to
+// before trailing columns are hidden.
+const defaultMinColumnWidth = 4
+
+// columnGap is the space rendered after each cell. Computed column widths include it.
+const columnGap = 1
+
+// columnScrollGutter is the width reserved on each side of a table whose columns
+// don't fit, for the indicators of columns scrolled off-screen.
+const columnScrollGutter = 1
+
+// calculateColumnWidths sizes the displayed columns to fill totalWidth. Hidden columns
+// have width 0. When the columns don't fit, space is reserved for the scroll
+// indicators and the frozen columns are shown followed by as many columns as fit from
+// the horizontal scroll offset.
+func (t *Table) calculateColumnWidths(totalWidth int) []int {
+	widths := t.sizeColumns(totalWidth)
+	if t.shrinkColumns(widths, totalWidth) <= 0 {
+		return widths
+	}
+	totalWidth -= 2 * columnScrollGutter
+	widths = t.sizeColumns(totalWidth)
+	t.shrinkColumns(widths, totalWidth)
+	t.windowColumns(widths, totalWidth)
+	return widths
+}
+
+// sizeColumns computes the preferred widths. Fixed and auto-width columns are sized
+// first, percentage columns take their share of the total and columns with no sizing
+// split whatever is left. When there are no such columns, the last resizable column
+// absorbs the remainder.
+func (t *Table) sizeColumns(totalWidth int) []int {
+	widths := make([]int, len(t.columns))
+	var flex []int
+	used := 0
+	for _, i := range t.VisibleColumns() {
+		col := t.columns[i]
+		switch {
+		case col.Width > 0:
+			widths[i] = col.Width
+		case col.AutoWidth:
+			widths[i] = t.contentWidth(i)
+		case col.Percentage > 0:
+			widths[i] = (totalWidth*col.Percentage)/100 - columnGap
+		default:
+			flex = append(flex, i)
+			continue
+		}
+		widths[i] = t.clampColumnWidth(i, widths[i]) + columnGap
+		used += widths[i]
+	}
+
+	remaining := totalWidth - used
+	switch {
+	case remaining > 0 && len(flex) > 0:
+		for n, i := range flex {
+			share := remaining / (len(flex) - n)
+			widths[i] = t.clampColumnWidth(i, share-columnGap) + columnGap
+			remaining -= widths[i]
+		}
+	case remaining > 0:
+		if last := t.lastResizableColumn(); last >= 0 {
+			widths[last] += remaining
+		}
+	default:
+		for _, i := range flex {
+			widths[i] = t.minColumnWidth(i) + columnGap
+		}
+	}
+	return widths
+}
+
+// shrinkColumns shrinks the trailing columns towards their minimum widths and returns
+// the width that still doesn't fit.
+func (t *Table) shrinkColumns(widths []int, totalWidth int) int {
+	overflow := -totalWidth
+	for _, w := range widths {
+		overflow += w
+	}
+	displayed := t.VisibleColumns()
+	for n := len(displayed) - 1; n >= 0 && overflow > 0; n-- {
+		i := displayed[n]
+		if t.columns[i].Width > 0 {
+			continue
+		}
+		shrinkable := widths[i] - columnGap - t.minColumnWidth(i)
+		if shrinkable <= 0 {
+			continue
+		}
+		shrink := min(shrinkable, overflow)
+		widths[i] -= shrink
+		overflow -= shrink
+	}
+	return overflow
+}
+
+// windowColumns hides the columns that are scrolled off-screen or don't fit.
+func (t *Table) windowColumns(widths []int, totalWidth int) {
+	displayed := t.VisibleColumns()
+	frozen, scrollable := t.splitFrozen(displayed)
+	available := totalWidth
+	for _, i := range frozen {
+		available -= widths[i]
+	}
+	offset := t.clampedColumnOffset()
+	for n, i := range scrollable {
+		switch {
+		case n < offset:
+			widths[i] = 0
+		case widths[i] <= available:
+			available -= widths[i]
+		case n == offset && available > columnGap+1:
+			// Truncate the first scrolled column rather than showing none.
+			widths[i] = available
+			available = 0
+		default:
+			widths[i] = 0
+			available = -1 // keep the shown columns contiguous
+		}
+	}
+	if available >= 0 || len(frozen) == 0 {
+		return
+	}
+	// The frozen columns alone don't fit: hide trailing ones, but never the first.
+	overflow := -available
+	for n := len(frozen) - 1; n > 0 && overflow > 0; n-- {
+		overflow -= widths[frozen[n]]
+		widths[frozen[n]] = 0
+	}
+	if overflow > 0 {
+		widths[frozen[0]] = max(widths[frozen[0]]-overflow, columnGap+1)
+	}
+}
+
+func (t *Table) lastResizableColumn() int {
+	displayed := t.VisibleColumns()
+	for n := len(displayed) - 1; n >= 0; n-- {
+		col := t.columns[displayed[n]]
+		if col.Width == 0 && col.MaxWidth == 0 {
+			return displayed[n]
+		}
+	}
+	if len(displayed) == 0 {
+		return -1
+	}
+	return displayed[len(displayed)-1]
+}
+
+func (t *Table) minColumnWidth(i int) int {
+	if t.columns[i].MinWidth > 0 {
+		return t.columns[i].MinWidth
+	}
+	if t.columns[i].Width > 0 {
+		return t.columns[i].Width
+	}
+	return defaultMinColumnWidth
+}
+
+func (t *Table) clampColumnWidth(i int, width int) int {
+	col := t.columns[i]
+	if col.MaxWidth > 0 && width > col.MaxWidth {
+		width = col.MaxWidth
+	}
+	if col.MinWidth > 0 && width < col.MinWidth {
+		width = col.MinWidth
+	}
+	return max(width, 1)
+}
+
+// contentWidth is the widest title or formatted cell in the column, including the
+// row prefixes rendered in the first column. Widths are cached until the rows change.
+func (t *Table) contentWidth(i int) int {
+	if t.contentWidths == nil || t.contentWidthsMulti != t.MultiSelect {
+		t.contentWidths = make(map[int]int)
+		t.contentWidthsMulti = t.MultiSelect
+	}
+	if width, ok := t.contentWidths[i]; ok {
+		return width
+	}
+	width := t.measureContentWidth(i)
+	t.contentWidths[i] = width
+	return width
+}
+
+// invalidateContentWidths drops the cached content widths, e.g. when rows change.
+func (t *Table) invalidateContentWidths() {
+	t.contentWidths = nil
+}
+
+func (t *Table) measureContentWidth(i int) int {
+	width := ansi.StringWidth(t.columns[i].Title + SortAscending.indicator())
+	if t.source != nil {
+		// Only cached pages are measured, so the width can grow while scrolling.
+		for _, page := range t.source.pages {
+			width = max(width, t.maxCellWidth(page, i, 0))
+		}
+		return width
+	}
+	return max(width, t.maxCellWidth(t.rows, i, 0))
+}
+
+func (t *Table) maxCellWidth(rows []TableRow, i, depth int) int {
+	width := 0
+	prefixed := i == t.prefixColumn(nil)
+	for _, row := range rows {
+		cell := ansi.StringWidth(t.formatCell(i, cellValue(row.Data, i)))
+		if prefixed {
+			// Mark, tree guides (2 + 3 per nested level) and the expansion marker.
+			cell += ansi.StringWidth(t.markPrefix(VisibleRow{})) + 2
+			if depth > 0 {
+				cell += 2 + 3*depth
+			}
+		}
+		width = max(width, cell, t.maxCellWidth(row.Children, i, depth+1))
+	}
+	return width
+}
+
+func (t *Table) formatCell(i int, value string) string {
+	if i >= len(t.columns) || t.columns[i].Format == nil {
+		return value
+	}
+	return t.columns[i].Format(value, t.render.Theme)
+}
+
+func (t *Table) hasWrappedColumns() bool {
+	for _, col := range t.columns {
+		if col.Wrap {
+			return true
+		}
+	}
+	return false
+}
+
+// rowHeight is the number of lines the row takes when wrapped columns are rendered.
+func (t *Table) rowHeight(row VisibleRow, colWidths []int) int {
+	height := 1
+	for _, c := range t.cellContents(row, colWidths, false) {
+		height = max(height, lipgloss.Height(c))
+	}
+	return height
+}
+
+// ensureSelectedVisibleWrapped scrolls so that every line of the selected row fits
+// when rows span multiple lines.
+func (t *Table) ensureSelectedVisibleWrapped() {
+	if t.selectedIndex < t.scrollOffset {
+		t.scrollOffset = t.selectedIndex
+	}
+	colWidths := t.calculateColumnWidths(t.calculateTableWidth())
+	available := t.availableLines()
+	for t.scrollOffset < t.selectedIndex {
+		lines := 0
+		for i := t.scrollOffset; i <= t.selectedIndex; i++ {
+			lines += t.rowHeight(t.rowAt(i), colWidths)
+		}
+		if lines <= available {
+			break
+		}
+		t.scrollOffset++
+	}
+	t.scrollOffset = max(t.scrollOffset, 0)
+}
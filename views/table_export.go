@@ -0,0 +1,81 @@
+package views
+
+import (
+	"io"
+	"strings"
+)
+
+// Export writes the rows currently shown by the table: the current filter, sort
+// order and visible columns are applied, regardless of which rows are expanded.
+// Child rows are included when opts.IncludeChildren is set. Tables backed by a data
+// source fetch every matching row from the source; their rows have no children.
+func (t *Table) Export(w io.Writer, opts ExportOptions) error {
+	if opts.Theme == nil {
+		opts.Theme = t.render.Theme
+	}
+	_, err := t.exportSnapshot(opts)(w, opts.Format)
+	return err
+}
+
+// exportSnapshot captures the rows to export so that they can be written outside of
+// the update loop. The format of opts is replaced by the format the job is run with.
+func (t *Table) exportSnapshot(opts ExportOptions) exportJob {
+	displayed := t.VisibleColumns()
+	columns := make([]TableColumn, len(displayed))
+	for n, i := range displayed {
+		columns[n] = t.columns[i]
+	}
+	if t.source != nil {
+		source, query := t.source.source, t.sourceQuery()
+		return func(w io.Writer, format ExportFormat) (int, error) {
+			count, err := source.Count(query)
+			if err != nil {
+				return 0, err
+			}
+			rows, err := source.Fetch(query, 0, count)
+			if err != nil {
+				return 0, err
+			}
+			opts.Format = format
+			return len(rows), ExportRows(w, columns, projectRows(rows, displayed), opts)
+		}
+	}
+	rows := projectRows(t.exportRows(t.rows, strings.TrimSpace(t.filterQuery), opts.IncludeChildren), displayed)
+	return func(w io.Writer, format ExportFormat) (int, error) {
+		opts.Format = format
+		return countRows(rows, opts.IncludeChildren), ExportRows(w, columns, rows, opts)
+	}
+}
+
+// exportRows returns copies of the rows in display order, keeping rows that match
+// the filter or have matching descendants, like the rendered tree.
+func (t *Table) exportRows(rows []TableRow, query string, includeChildren bool) []TableRow {
+	exported := make([]TableRow, 0, len(rows))
+	for _, i := range t.sortedIndices(rows) {
+		row := rows[i]
+		if query != "" && !t.matchesFilter(query, row.Data) && !t.descendantMatches(query, row.Children) {
+			continue
+		}
+		row.Children = nil
+		if includeChildren {
+			row.Children = t.exportRows(rows[i].Children, query, true)
+		}
+		exported = append(exported, row)
+	}
+	return exported
+}
+
+// projectRows returns the rows with their cells in the order of the given columns.
+func projectRows(rows []TableRow, columns []int) []TableRow {
+	projected := make([]TableRow, len(rows))
+	for n, row := range rows {
+		data := make([]string, len(columns))
+		for c, i := range columns {
+			data[c] = cellValue(row.Data, i)
+		}
+		row.Data = data
+		row.Children = projectRows(row.Children, columns)
+		projected[n] = row
+	}
+	return projected
+}
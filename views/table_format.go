@@ -0,0 +1,90 @@
+package views
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flowexec/tuikit/themes"
+)
+
+// CellFormatter formats a cell value for display. The raw value is still used for
+// sorting and filtering. Values a formatter can't parse should be returned as-is.
+// The theme is never nil.
+type CellFormatter func(value string, theme themes.Theme) string
+
+// FormatDuration formats Go durations ("1m30s") or a number of seconds as a compact
+// duration, e.g. "1m30s", "2h05m" or "250ms".
+func FormatDuration(value string, _ themes.Theme) string {
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		secs, numErr := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if numErr != nil {
+			return value
+		}
+		d = time.Duration(secs * float64(time.Second))
+	}
+	switch {
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d >= time.Second:
+		return strconv.FormatFloat(d.Seconds(), 'f', 1, 64) + "s"
+	default:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+}
+
+// FormatTimestamp returns a formatter that renders dates, timestamps or unix seconds
+// in local time with the given layout.
+func FormatTimestamp(layout string) CellFormatter {
+	return func(value string, _ themes.Theme) string {
+		ts, ok := parseDate(value)
+		if !ok {
+			secs, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				return value
+			}
+			ts = time.Unix(secs, 0)
+		}
+		return ts.Local().Format(layout)
+	}
+}
+
+// FormatBytes formats a number of bytes using binary units, e.g. "1.5 KiB".
+func FormatBytes(value string, _ themes.Theme) string {
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return value
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", int64(n))
+	}
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB"}
+	i := -1
+	for n >= unit && i < len(units)-1 {
+		n /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", n, units[i])
+}
+
+// FormatStatusBadge returns a formatter that colors values with the theme color of
+// their output level. Values are matched case-insensitively; unknown values are
+// rendered unchanged.
+func FormatStatusBadge(levels map[string]themes.OutputLevel) CellFormatter {
+	normalized := make(map[string]themes.OutputLevel, len(levels))
+	for k, v := range levels {
+		normalized[strings.ToLower(k)] = v
+	}
+	return func(value string, theme themes.Theme) string {
+		lvl, ok := normalized[strings.ToLower(strings.TrimSpace(value))]
+		if !ok || theme == nil {
+			return value
+		}
+		return theme.RenderLevel(value, lvl)
+	}
+}
@@ -0,0 +1,234 @@
+package views
+
+import (
+	"slices"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// DefaultHighlightDuration is how long changed cells stay highlighted after a live update.
+const DefaultHighlightDuration = 2 * time.Second
+
+// TableUpsertMsg inserts or updates rows in the Table that receives it. Rows are
+// matched by TableRow.Key anywhere in the row tree; unmatched rows are appended at the
+// top level. Send it with tea.Program.Send to update a running table from another
+// goroutine.
+type TableUpsertMsg struct {
+	Rows []TableRow
+}
+
+// TableDeleteMsg deletes the rows with the given keys from the Table that receives it.
+type TableDeleteMsg struct {
+	Keys []string
+}
+
+type highlightExpiredMsg struct {
+	table *Table
+}
+
+// cellChange records which cells of a keyed row changed and when.
+type cellChange struct {
+	columns []int // nil when the whole row is new
+	at      time.Time
+}
+
+// UpsertRows inserts or updates keyed rows without resetting the selection, scroll
+// position or filter. Rows without a key are always appended. Updated rows keep their
+// expansion state, and their children when the update has none. The returned command
+// clears the change highlight once HighlightDuration has passed.
+func (t *Table) UpsertRows(rows ...TableRow) tea.Cmd {
+	if t.source != nil || len(rows) == 0 {
+		return nil
+	}
+	now := time.Now()
+	t.invalidateContentWidths()
+	t.preservingSelection(func() {
+		for _, row := range rows {
+			path := findRowPath(t.rows, row.Key, nil)
+			if row.Key == "" || path == nil {
+				t.rows = append(t.rows, row)
+				t.recordChange(row.Key, nil, now)
+				continue
+			}
+			node := t.node(path)
+			t.recordChange(row.Key, changedColumns(node.Data, row.Data), now)
+			node.Data = row.Data
+			if row.Children != nil {
+				node.Children = row.Children
+				node.Lazy = row.Lazy
+			}
+		}
+	})
+	return tea.Tick(t.highlightDuration(), func(time.Time) tea.Msg {
+		return highlightExpiredMsg{table: t}
+	})
+}
+
+// DeleteRows removes the rows with the given keys, including their children.
+func (t *Table) DeleteRows(keys ...string) {
+	if t.source != nil || len(keys) == 0 {
+		return
+	}
+	t.invalidateContentWidths()
+	t.preservingSelection(func() {
+		t.rows = deleteRows(t.rows, keys)
+	})
+	for _, k := range keys {
+		delete(t.changes, k)
+	}
+}
+
+// IsHighlighted returns true if the cell of the keyed row changed recently.
+func (t *Table) IsHighlighted(key string, column int) bool {
+	change, ok := t.changes[key]
+	if !ok || key == "" || time.Since(change.at) >= t.highlightDuration() {
+		return false
+	}
+	return change.columns == nil || slices.Contains(change.columns, column)
+}
+
+func (t *Table) highlightDuration() time.Duration {
+	if t.HighlightDuration > 0 {
+		return t.HighlightDuration
+	}
+	return DefaultHighlightDuration
+}
+
+func (t *Table) recordChange(key string, columns []int, at time.Time) {
+	if key == "" {
+		return
+	}
+	if columns != nil && len(columns) == 0 {
+		return
+	}
+	t.changes[key] = cellChange{columns: columns, at: at}
+}
+
+func (t *Table) expireHighlights() {
+	for k, c := range t.changes {
+		if time.Since(c.at) >= t.highlightDuration() {
+			delete(t.changes, k)
+		}
+	}
+}
+
+// preservingSelection applies a change to the rows and keeps the cursor, marks and
+// in-flight child loads on the same rows, even when their paths change. Loads of rows
+// that are gone are dropped.
+func (t *Table) preservingSelection(change func()) {
+	var selected rowAnchor
+	if row := t.GetSelectedRow(); row != nil {
+		selected = t.anchorRow(row.path)
+	}
+	marked := make([]rowAnchor, 0, len(t.marked))
+	for k := range t.marked {
+		marked = append(marked, t.anchorRow(parseRowKey(k)))
+	}
+	loading := make(map[int]rowAnchor, len(t.loading))
+	for k, id := range t.loading {
+		loading[id] = t.anchorRow(parseRowKey(k))
+	}
+
+	change()
+
+	t.ClearMarks()
+	for _, a := range marked {
+		if path := t.resolveRow(a); path != nil {
+			t.marked[pathKey(path)] = true
+		}
+	}
+	t.loading = make(map[rowKey]int, len(loading))
+	for id, a := range loading {
+		if path := t.resolveRow(a); path != nil {
+			t.loading[pathKey(path)] = id
+		}
+	}
+	t.buildVisibleRows()
+	if path := t.resolveRow(selected); path != nil {
+		t.reselect(VisibleRow{path: path})
+	} else if selected.path != nil {
+		t.reselect(VisibleRow{path: selected.path})
+	}
+}
+
+// rowAnchor remembers a row across changes to the rows: keyed rows by their key and
+// other rows by their path and data.
+type rowAnchor struct {
+	key  string
+	path []int
+	data []string
+}
+
+func (t *Table) anchorRow(path []int) rowAnchor {
+	a := rowAnchor{path: path}
+	if node := t.node(path); node != nil {
+		a.key, a.data = node.Key, slices.Clone(node.Data)
+	}
+	return a
+}
+
+// resolveRow returns the current path of the anchored row, or nil when it is gone.
+// Rows without a key are only found when their path is unchanged.
+func (t *Table) resolveRow(a rowAnchor) []int {
+	if a.key != "" {
+		return findRowPath(t.rows, a.key, nil)
+	}
+	if a.path == nil {
+		return nil
+	}
+	if node := t.node(a.path); node != nil && node.Key == "" && slices.Equal(node.Data, a.data) {
+		return a.path
+	}
+	return nil
+}
+
+func (t *Table) highlightChange(style lipgloss.Style, row VisibleRow, column int) lipgloss.Style {
+	if len(t.changes) == 0 {
+		return style
+	}
+	node := t.node(row.path)
+	if node == nil || !t.IsHighlighted(node.Key, column) {
+		return style
+	}
+	return style.Foreground(t.render.Theme.ColorPalette().EmphasisColor()).Bold(true)
+}
+
+func findRowPath(rows []TableRow, key string, parent []int) []int {
+	if key == "" {
+		return nil
+	}
+	for i, row := range rows {
+		path := append(slices.Clone(parent), i)
+		if row.Key == key {
+			return path
+		}
+		if found := findRowPath(row.Children, key, path); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func deleteRows(rows []TableRow, keys []string) []TableRow {
+	rows = slices.DeleteFunc(rows, func(r TableRow) bool {
+		return r.Key != "" && slices.Contains(keys, r.Key)
+	})
+	for i := range rows {
+		if len(rows[i].Children) > 0 {
+			rows[i].Children = deleteRows(rows[i].Children, keys)
+		}
+	}
+	return rows
+}
+
+func changedColumns(before, after []string) []int {
+	changed := make([]int, 0)
+	for i := range max(len(before), len(after)) {
+		if cellValue(before, i) != cellValue(after, i) {
+			changed = append(changed, i)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,439 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/sahilm/fuzzy"
+)
+
+// FilterQuery is a parsed table filter. The query language supports:
+//
+//	deploy            cells containing "deploy" (case-insensitive)
+//	"build failed"    cells containing the quoted phrase
+//	/^build-\d+/      cells matching the regular expression
+//	status:failed     the term only matches the column titled "Status"
+//	-tag:test         rows that don't match the term
+//	a b, a AND b      rows matching both terms
+//	a OR b            rows matching either term
+//	(a OR b) -c       grouping
+//
+// Column names are matched case-insensitively, ignoring spaces, so "Last Run" is
+// written as lastrun:. In fuzzy mode, unquoted terms match when their characters
+// appear in order in a cell. Text that doesn't form a term is matched literally, like
+// "10:30" without a column named 10, an unterminated quote or "-v" on its own.
+type FilterQuery struct {
+	root  filterNode
+	terms []*filterTerm // terms that are not negated, used for highlighting
+}
+
+type filterNode interface {
+	match(row []string) bool
+}
+
+type filterAnd []filterNode
+
+func (n filterAnd) match(row []string) bool {
+	for _, c := range n {
+		if !c.match(row) {
+			return false
+		}
+	}
+	return true
+}
+
+type filterOr []filterNode
+
+func (n filterOr) match(row []string) bool {
+	for _, c := range n {
+		if c.match(row) {
+			return true
+		}
+	}
+	return false
+}
+
+type filterNot struct {
+	node filterNode
+}
+
+func (n filterNot) match(row []string) bool {
+	return !n.node.match(row)
+}
+
+type filterTerm struct {
+	column int // -1 matches any column
+	re     *regexp.Regexp
+	fuzzy  string
+}
+
+func (t *filterTerm) match(row []string) bool {
+	if t.column >= 0 {
+		return t.matchCell(cellValue(row, t.column))
+	}
+	return slices.ContainsFunc(row, t.matchCell)
+}
+
+func (t *filterTerm) matchCell(cell string) bool {
+	if t.re != nil {
+		return t.re.MatchString(cell)
+	}
+	return len(fuzzy.Find(t.fuzzy, []string{cell})) > 0
+}
+
+// byteRanges returns the byte offsets of the matched parts of the cell.
+func (t *filterTerm) byteRanges(cell string) [][2]int {
+	if t.re != nil {
+		var ranges [][2]int
+		for _, m := range t.re.FindAllStringIndex(cell, -1) {
+			if m[1] > m[0] {
+				ranges = append(ranges, [2]int{m[0], m[1]})
+			}
+		}
+		return ranges
+	}
+	matches := fuzzy.Find(t.fuzzy, []string{cell})
+	if len(matches) == 0 {
+		return nil
+	}
+	ranges := make([][2]int, 0, len(matches[0].MatchedIndexes))
+	for _, i := range matches[0].MatchedIndexes {
+		_, size := utf8.DecodeRuneInString(cell[i:])
+		ranges = append(ranges, [2]int{i, i + size})
+	}
+	return ranges
+}
+
+// ParseFilterQuery parses a filter query for a table with the given columns. An empty
+// query matches every row. Data sources that support filtering can use it to apply
+// the same query language as in-memory tables.
+func ParseFilterQuery(query string, columns []TableColumn, fuzzyTerms bool) (*FilterQuery, error) {
+	tokens := tokenizeFilter(query, columns)
+	p := &filterParser{tokens: tokens, columns: columns, fuzzy: fuzzyTerms, query: &FilterQuery{}}
+	if len(tokens) == 0 {
+		p.query.root = filterAnd{}
+		return p.query, nil
+	}
+	root, err := p.parseOr(false)
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, errors.New("unexpected )")
+	}
+	p.query.root = root
+	return p.query, nil
+}
+
+// Match returns true if the row matches the query.
+func (q *FilterQuery) Match(row []string) bool {
+	return q.root.match(row)
+}
+
+// Highlight styles the parts of a cell value in the given column that are matched by
+// the query's terms. The value may already be styled.
+func (q *FilterQuery) Highlight(column int, value string, style lipgloss.Style) string {
+	plain := ansi.Strip(value)
+	var ranges [][2]int
+	for _, term := range q.terms {
+		if term.column < 0 || term.column == column {
+			ranges = append(ranges, term.byteRanges(plain)...)
+		}
+	}
+	if len(ranges) == 0 {
+		return value
+	}
+	slices.SortFunc(ranges, func(a, b [2]int) int { return a[0] - b[0] })
+	styled := make([]lipgloss.Range, 0, len(ranges))
+	end := 0
+	for _, r := range ranges {
+		start := max(r[0], end)
+		if start >= r[1] {
+			continue
+		}
+		// StyleRanges works with cell positions rather than bytes.
+		styled = append(styled, lipgloss.NewRange(
+			ansi.StringWidth(plain[:start]), ansi.StringWidth(plain[:r[1]]), style,
+		))
+		end = r[1]
+	}
+	return lipgloss.StyleRanges(value, styled...)
+}
+
+type filterTokenKind int
+
+const (
+	tokenTerm filterTokenKind = iota
+	tokenAnd
+	tokenOr
+	tokenOpen
+	tokenClose
+)
+
+type filterToken struct {
+	kind    filterTokenKind
+	negate  bool
+	column  string
+	text    string
+	regex   bool
+	quoted  bool
+	hasText bool
+	source  string // the term as written
+}
+
+func tokenizeFilter(query string, columns []TableColumn) []filterToken {
+	runes := []rune(query)
+	var tokens []filterToken
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, filterToken{kind: tokenOpen})
+			i++
+			continue
+		case r == ')':
+			tokens = append(tokens, filterToken{kind: tokenClose})
+			i++
+			continue
+		}
+
+		begin := i
+		tok := filterToken{kind: tokenTerm}
+		if r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			tok.negate = true
+			i++
+			if runes[i] == '(' {
+				// A negated group: the group is parsed from the following tokens.
+				tokens = append(tokens, tok)
+				continue
+			}
+		}
+		if name, n := columnPrefix(runes[i:]); n > 0 && columnIndex(columns, name) >= 0 {
+			tok.column = name
+			i += n
+		}
+
+		var ok bool
+		switch {
+		case i < len(runes) && runes[i] == '"':
+			tok.text, i, ok = readDelimited(runes, i, '"')
+			tok.quoted = ok
+		case i < len(runes) && runes[i] == '/':
+			tok.text, i, ok = readDelimited(runes, i, '/')
+			tok.regex = ok
+		}
+		if !ok {
+			// Anything else, including an unterminated quote or regex, is literal text.
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+				i++
+			}
+			tok.text = string(runes[start:i])
+		}
+		tok.source = string(runes[begin:i])
+		tok.hasText = tok.text != "" || tok.quoted || tok.regex
+		if !tok.negate && tok.column == "" && !tok.quoted && !tok.regex {
+			switch tok.text {
+			case "AND":
+				tok.kind = tokenAnd
+			case "OR":
+				tok.kind = tokenOr
+			}
+		}
+		tokens = append(tokens, tok)
+	}
+	// A lone negated term, e.g. "-v", is more likely text to find than a filter that
+	// hides almost every row.
+	if len(tokens) == 1 && tokens[0].negate && tokens[0].hasText {
+		tokens[0] = filterToken{kind: tokenTerm, text: tokens[0].source, hasText: true}
+	}
+	return tokens
+}
+
+// columnPrefix returns the column name of a "name:" prefix and its length in runes.
+func columnPrefix(runes []rune) (string, int) {
+	for i, r := range runes {
+		switch {
+		case r == ':':
+			if i == 0 {
+				return "", 0
+			}
+			return string(runes[:i]), i + 1
+		case unicode.IsSpace(r), r == '"', r == '/', r == '(', r == ')':
+			return "", 0
+		}
+	}
+	return "", 0
+}
+
+// readDelimited reads a quoted phrase or regex starting at the opening delimiter. A
+// backslash escapes the delimiter. It returns false unless the closing delimiter ends
+// the term, so that e.g. "/usr/bin" is read as text.
+func readDelimited(runes []rune, start int, delim rune) (string, int, bool) {
+	var b strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && i+1 < len(runes) && runes[i+1] == delim:
+			b.WriteRune(delim)
+			i++
+		case runes[i] == delim:
+			if i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != ')' {
+				return "", start, false
+			}
+			return b.String(), i + 1, true
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+	return "", start, false
+}
+
+type filterParser struct {
+	tokens  []filterToken
+	pos     int
+	columns []TableColumn
+	fuzzy   bool
+	query   *FilterQuery
+}
+
+func (p *filterParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) parseOr(negated bool) (filterNode, error) {
+	left, err := p.parseAnd(negated)
+	if err != nil {
+		return nil, err
+	}
+	nodes := filterOr{left}
+	for !p.done() && p.peek().kind == tokenOr {
+		p.pos++
+		right, err := p.parseAnd(negated)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, right)
+	}
+	if len(nodes) == 1 {
+		return left, nil
+	}
+	return nodes, nil
+}
+
+func (p *filterParser) parseAnd(negated bool) (filterNode, error) {
+	var nodes filterAnd
+	for !p.done() {
+		tok := p.peek()
+		if tok.kind == tokenOr || tok.kind == tokenClose {
+			break
+		}
+		if tok.kind == tokenAnd {
+			p.pos++
+			if p.done() {
+				return nil, errors.New("expected a term after AND")
+			}
+			continue
+		}
+		node, err := p.parseUnary(negated)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	switch len(nodes) {
+	case 0:
+		return nil, errors.New("expected a term")
+	case 1:
+		return nodes[0], nil
+	default:
+		return nodes, nil
+	}
+}
+
+func (p *filterParser) parseUnary(negated bool) (filterNode, error) {
+	tok := p.peek()
+	p.pos++
+	if tok.kind == tokenOpen || (tok.negate && !tok.hasText && tok.column == "") {
+		if tok.negate {
+			if p.done() || p.peek().kind != tokenOpen {
+				return nil, errors.New("expected a term after -")
+			}
+			p.pos++
+		}
+		node, err := p.parseOr(negated != tok.negate)
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.peek().kind != tokenClose {
+			return nil, errors.New("missing )")
+		}
+		p.pos++
+		if tok.negate {
+			return filterNot{node}, nil
+		}
+		return node, nil
+	}
+
+	term, err := p.term(tok)
+	if err != nil {
+		return nil, err
+	}
+	// Terms under an odd number of negations exclude rows, so they are not highlighted.
+	if negated == tok.negate {
+		p.query.terms = append(p.query.terms, term)
+	}
+	if tok.negate {
+		return filterNot{term}, nil
+	}
+	return term, nil
+}
+
+func (p *filterParser) term(tok filterToken) (*filterTerm, error) {
+	term := &filterTerm{column: -1}
+	if tok.column != "" {
+		term.column = columnIndex(p.columns, tok.column)
+	}
+	switch {
+	case tok.regex:
+		re, err := regexp.Compile(tok.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex /%s/", tok.text)
+		}
+		term.re = re
+	case tok.text == "":
+		return nil, errors.New("expected a term")
+	case p.fuzzy && !tok.quoted:
+		term.fuzzy = tok.text
+	default:
+		term.re = regexp.MustCompile("(?i)" + regexp.QuoteMeta(tok.text))
+	}
+	return term, nil
+}
+
+// columnIndex returns the index of the column with the given name, or -1.
+func columnIndex(columns []TableColumn, name string) int {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.Join(strings.Fields(s), ""))
+	}
+	name = normalize(name)
+	for i, col := range columns {
+		if normalize(col.Title) == name {
+			return i
+		}
+	}
+	return -1
+}
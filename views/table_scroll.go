@@ -0,0 +1,222 @@
+package views
+
+import (
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/flowexec/tuikit/i18n"
+	"github.com/flowexec/tuikit/types"
+)
+
+// VisibleColumns returns the indices of the columns that are not hidden, in display
+// order. Columns scrolled off-screen are included.
+func (t *Table) VisibleColumns() []int {
+	order := t.columnOrder()
+	return slices.DeleteFunc(order, func(i int) bool { return t.hidden[i] })
+}
+
+// SetColumnVisible shows or hides a column. The last visible column can't be hidden.
+func (t *Table) SetColumnVisible(column int, visible bool) {
+	if column < 0 || column >= len(t.columns) {
+		return
+	}
+	if !visible && !t.hidden[column] && len(t.VisibleColumns()) <= 1 {
+		return
+	}
+	if visible {
+		delete(t.hidden, column)
+	} else {
+		t.hidden[column] = true
+	}
+	// The row prefixes are measured in the first visible column.
+	t.invalidateContentWidths()
+	t.columnOffset = t.clampedColumnOffset()
+}
+
+// SetColumnOrder sets the display order of the columns by index. Columns missing
+// from the order are shown after it in their original order; invalid and duplicate
+// indices are ignored.
+func (t *Table) SetColumnOrder(order []int) {
+	seen := make(map[int]bool, len(t.columns))
+	next := make([]int, 0, len(t.columns))
+	for _, i := range order {
+		if i >= 0 && i < len(t.columns) && !seen[i] {
+			seen[i] = true
+			next = append(next, i)
+		}
+	}
+	for i := range t.columns {
+		if !seen[i] {
+			next = append(next, i)
+		}
+	}
+	t.order = next
+	t.invalidateContentWidths()
+}
+
+// ScrollColumns scrolls the columns after the frozen columns horizontally by delta
+// columns. It has no effect when every column fits.
+func (t *Table) ScrollColumns(delta int) {
+	if t.render == nil {
+		return
+	}
+	_, right := t.scrollIndicators(t.calculateColumnWidths(t.calculateTableWidth()))
+	if delta > 0 && !right {
+		return
+	}
+	t.columnOffset = max(t.columnOffset+delta, 0)
+	t.columnOffset = t.clampedColumnOffset()
+}
+
+func (t *Table) columnOrder() []int {
+	if len(t.order) == len(t.columns) {
+		return slices.Clone(t.order)
+	}
+	order := make([]int, len(t.columns))
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+// splitFrozen splits the displayed columns into the frozen leading columns and the
+// columns that scroll horizontally.
+func (t *Table) splitFrozen(displayed []int) ([]int, []int) {
+	n := min(max(t.FrozenColumns, 0), len(displayed))
+	return displayed[:n], displayed[n:]
+}
+
+func (t *Table) clampedColumnOffset() int {
+	_, scrollable := t.splitFrozen(t.VisibleColumns())
+	return max(min(t.columnOffset, len(scrollable)-1), 0)
+}
+
+// prefixColumn returns the column rendered first, which holds the row prefix. When
+// colWidths is nil, columns scrolled off-screen are ignored.
+func (t *Table) prefixColumn(colWidths []int) int {
+	for _, i := range t.VisibleColumns() {
+		if colWidths == nil || (i < len(colWidths) && colWidths[i] > 0) {
+			return i
+		}
+	}
+	return -1
+}
+
+// scrollIndicators reports whether columns are hidden to the left or right of the
+// rendered columns.
+func (t *Table) scrollIndicators(colWidths []int) (left, right bool) {
+	displayed := t.VisibleColumns()
+	if len(displayed) == 0 || !slices.ContainsFunc(displayed, func(i int) bool { return colWidths[i] <= 0 }) {
+		return false, false
+	}
+	_, scrollable := t.splitFrozen(displayed)
+	left = len(scrollable) > 0 && colWidths[scrollable[0]] <= 0
+	right = colWidths[displayed[len(displayed)-1]] <= 0
+	return left, right
+}
+
+// addScrollGutters pads each line of a rendered header or row with the gutters
+// reserved for the scroll indicators. The indicators are shown on the first line.
+func (t *Table) addScrollGutters(block string, left, right bool) string {
+	style := lipgloss.NewStyle().Foreground(t.render.Theme.ColorPalette().GrayColor())
+	indicator := func(show bool, symbol string) string {
+		if show {
+			return style.Render(symbol)
+		}
+		return " "
+	}
+	lines := strings.Split(block, "\n")
+	for n, line := range lines {
+		if n == 0 {
+			lines[n] = indicator(left, "‹") + line + indicator(right, "›")
+		} else {
+			lines[n] = " " + line + " "
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// columnChooser is the state of the overlay used to show, hide and reorder columns.
+type columnChooser struct {
+	cursor int // position in the column order
+}
+
+// openColumnChooser opens the chooser; a table without columns has nothing to choose.
+func (t *Table) openColumnChooser() {
+	if len(t.columns) == 0 {
+		return
+	}
+	t.chooser = &columnChooser{}
+}
+
+func (t *Table) handleChooserKeyMsg(msg tea.KeyPressMsg) {
+	order := t.columnOrder()
+	c := t.chooser
+	if len(order) == 0 {
+		t.chooser = nil
+		return
+	}
+	c.cursor = min(c.cursor, len(order)-1)
+	switch msg.String() {
+	case "esc", types.KeyEnter, "C":
+		t.chooser = nil
+	case types.KeyUp, "k":
+		c.cursor = max(c.cursor-1, 0)
+	case types.KeyDown, "j":
+		c.cursor = min(c.cursor+1, len(order)-1)
+	case "space", "x":
+		column := order[c.cursor]
+		t.SetColumnVisible(column, t.hidden[column])
+	case "shift+up", "K":
+		if c.cursor > 0 {
+			order[c.cursor], order[c.cursor-1] = order[c.cursor-1], order[c.cursor]
+			t.SetColumnOrder(order)
+			c.cursor--
+		}
+	case "shift+down", "J":
+		if c.cursor < len(order)-1 {
+			order[c.cursor], order[c.cursor+1] = order[c.cursor+1], order[c.cursor]
+			t.SetColumnOrder(order)
+			c.cursor++
+		}
+	}
+}
+
+func (t *Table) renderColumnChooser() string {
+	cp := t.render.Theme.ColorPalette()
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(cp.PrimaryColor()).Render(i18n.T(i18n.MsgColumnsTitle)),
+	}
+	for n, i := range t.columnOrder() {
+		mark := "[x] "
+		if t.hidden[i] {
+			mark = "[ ] "
+		}
+		style := lipgloss.NewStyle().Foreground(cp.BodyColor())
+		cursor := "  "
+		if n == t.chooser.cursor {
+			style = style.Foreground(cp.SecondaryColor()).Bold(true)
+			cursor = "> "
+		}
+		lines = append(lines, style.Render(cursor+mark+t.columns[i].Title))
+	}
+	lines = append(lines, lipgloss.NewStyle().Foreground(cp.GrayColor()).Render(i18n.T(i18n.MsgColumnsHint)))
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(cp.BorderColor()).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+// overlayColumnChooser draws the column chooser centered over the rendered table.
+func (t *Table) overlayColumnChooser(content string) string {
+	box := t.renderColumnChooser()
+	x := max((lipgloss.Width(content)-lipgloss.Width(box))/2, 0)
+	y := max((lipgloss.Height(content)-lipgloss.Height(box))/2, 0)
+	base := lipgloss.NewLayer(content)
+	base.AddLayers(lipgloss.NewLayer(box).X(x).Y(y).Z(1))
+	return lipgloss.NewCompositor(base).Render()
+}
@@ -0,0 +1,167 @@
+package views
+
+import (
+	"slices"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/flowexec/tuikit/i18n"
+)
+
+// TableBulkAction binds a key to a callback that receives every marked row. When no
+// rows are marked, the callback receives the row under the cursor.
+type TableBulkAction struct {
+	Key      string
+	Label    string
+	Callback func(rows []TableRow) error
+}
+
+// IsMarked returns true if the row has been marked in multi-select mode.
+func (t *Table) IsMarked(row *VisibleRow) bool {
+	return row != nil && t.marked[row.key()]
+}
+
+// MarkedCount returns the number of marked rows.
+func (t *Table) MarkedCount() int {
+	return len(t.marked)
+}
+
+// SelectedRows returns the marked rows in the table's original row order, parents
+// before their children. Rows hidden by the current filter or a collapsed parent
+// are included. Rows of a data source are only marked once loaded, and selecting all
+// rows or inverting the marks is not supported for data sources.
+func (t *Table) SelectedRows() []TableRow {
+	if t.source != nil {
+		return t.sourceMarkedRows()
+	}
+	rows := make([]TableRow, 0, len(t.marked))
+	return t.appendMarkedRows(rows, t.rows, nil)
+}
+
+func (t *Table) appendMarkedRows(marked, rows []TableRow, parent []int) []TableRow {
+	for i, row := range rows {
+		path := append(slices.Clone(parent), i)
+		if t.marked[pathKey(path)] {
+			marked = append(marked, row)
+		}
+		marked = t.appendMarkedRows(marked, row.Children, path)
+	}
+	return marked
+}
+
+// ClearMarks unmarks all rows.
+func (t *Table) ClearMarks() {
+	t.marked = make(map[rowKey]bool)
+	if t.source != nil {
+		t.source.marked = make(map[int]TableRow)
+	}
+}
+
+func (t *Table) toggleMark() {
+	row := t.GetSelectedRow()
+	if row == nil || row.placeholder {
+		return
+	}
+	k := row.key()
+	if t.marked[k] {
+		delete(t.marked, k)
+	} else {
+		t.marked[k] = true
+	}
+	if t.source != nil {
+		if i := row.path[0]; t.marked[k] {
+			t.source.marked[i] = *t.sourceNode(i)
+		} else {
+			delete(t.source.marked, i)
+		}
+	}
+	t.moveCursor(1)
+}
+
+// toggleMarkAll marks every visible row, or clears the marks when all visible rows
+// are already marked.
+func (t *Table) toggleMarkAll() {
+	allMarked := t.rowCount() > 0
+	for i := range t.rowCount() {
+		if !t.marked[t.rowKeyAt(i)] {
+			allMarked = false
+			break
+		}
+	}
+	for i := range t.rowCount() {
+		k := t.rowKeyAt(i)
+		if allMarked {
+			delete(t.marked, k)
+		} else {
+			t.marked[k] = true
+		}
+	}
+}
+
+func (t *Table) invertMarks() {
+	for i := range t.rowCount() {
+		k := t.rowKeyAt(i)
+		if t.marked[k] {
+			delete(t.marked, k)
+		} else {
+			t.marked[k] = true
+		}
+	}
+}
+
+func (t *Table) bulkAction(key string) (TableBulkAction, bool) {
+	for _, a := range t.BulkActions {
+		if a.Key == key {
+			return a, true
+		}
+	}
+	return TableBulkAction{}, false
+}
+
+func (t *Table) runBulkAction(action TableBulkAction) tea.Cmd {
+	rows := t.SelectedRows()
+	if len(rows) == 0 {
+		row := t.GetSelectedRow()
+		if row == nil {
+			return nil
+		}
+		rows = []TableRow{t.tableRow(*row)}
+	}
+	return func() tea.Msg {
+		if err := action.Callback(rows); err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+func (t *Table) tableRow(row VisibleRow) TableRow {
+	if node := t.node(row.path); node != nil {
+		return *node
+	}
+	return TableRow{Data: row.data}
+}
+
+func (t *Table) markPrefix(row VisibleRow) string {
+	if !t.MultiSelect {
+		return ""
+	}
+	if t.marked[row.key()] {
+		return "[x] "
+	}
+	return "[ ] "
+}
+
+func (t *Table) renderSelectionStatus() string {
+	if len(t.marked) == 0 {
+		return ""
+	}
+	return i18n.Tf(i18n.MsgSelectedCount, len(t.marked))
+}
+
+func (t *Table) rowKeyAt(i int) rowKey {
+	if t.source != nil {
+		return pathKey([]int{i})
+	}
+	return t.visibleRows[i].key()
+}
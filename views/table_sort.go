@@ -0,0 +1,247 @@
+package views
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+type SortOrder int
+
+const (
+	SortNone SortOrder = iota
+	SortAscending
+	SortDescending
+)
+
+// next cycles ascending -> descending -> off.
+func (o SortOrder) next() SortOrder {
+	switch o {
+	case SortNone:
+		return SortAscending
+	case SortAscending:
+		return SortDescending
+	default:
+		return SortNone
+	}
+}
+
+func (o SortOrder) indicator() string {
+	switch o {
+	case SortAscending:
+		return " ▲"
+	case SortDescending:
+		return " ▼"
+	default:
+		return ""
+	}
+}
+
+// CompareFunc compares two cell values, returning a negative number when a sorts
+// before b, a positive number when a sorts after b and zero when they are equal.
+type CompareFunc func(a, b string) int
+
+// CompareStrings compares cell values case-insensitively.
+func CompareStrings(a, b string) int {
+	return cmp.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// CompareNumbers compares cell values as numbers. Values that are not numbers
+// sort after numbers and are compared as strings.
+func CompareNumbers(a, b string) int {
+	na, errA := parseNumber(a)
+	nb, errB := parseNumber(b)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return CompareStrings(a, b)
+	}
+}
+
+// CompareDates compares cell values as dates or timestamps. Values that can't be
+// parsed sort after dates and are compared as strings.
+func CompareDates(a, b string) int {
+	ta, okA := parseDate(a)
+	tb, okB := parseDate(b)
+	switch {
+	case okA && okB:
+		return ta.Compare(tb)
+	case okA:
+		return -1
+	case okB:
+		return 1
+	default:
+		return CompareStrings(a, b)
+	}
+}
+
+// columnComparator picks how the cells of a column without a Compare func are sorted:
+// as numbers when all non-empty cells are numbers, as dates when they are all dates
+// and as strings otherwise. Picking once per column keeps the order consistent.
+func columnComparator(rows []TableRow, column int) CompareFunc {
+	numbers, dates := true, true
+	for _, row := range rows {
+		value := cellValue(row.Data, column)
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		if numbers {
+			_, err := parseNumber(value)
+			numbers = err == nil
+		}
+		if dates {
+			_, dates = parseDate(value)
+		}
+		if !numbers && !dates {
+			break
+		}
+	}
+	switch {
+	case numbers:
+		return CompareNumbers
+	case dates:
+		return CompareDates
+	default:
+		return CompareStrings
+	}
+}
+
+func parseNumber(s string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", ""), 64)
+}
+
+var dateLayouts = []string{
+	time.RFC3339,
+	time.DateTime,
+	time.DateOnly,
+	time.RFC1123,
+	time.Kitchen,
+}
+
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// SetSort sorts the table by the given column. Passing SortNone (or an out of range
+// column) restores the original row order. For tables backed by a data source, the
+// returned command reloads the rows; sources that can't sort are left unchanged.
+func (t *Table) SetSort(column int, order SortOrder) tea.Cmd {
+	if column < 0 || column >= len(t.columns) {
+		column, order = -1, SortNone
+	}
+	if t.source != nil {
+		if !t.source.supportsSort() {
+			return nil
+		}
+		t.sortColumn, t.sortOrder = column, order
+		t.selectedIndex, t.scrollOffset = 0, 0
+		return t.reloadSource()
+	}
+	prev := t.GetSelectedRow()
+	t.sortColumn = column
+	t.sortOrder = order
+	t.buildVisibleRows()
+	if prev != nil {
+		t.reselect(*prev)
+	}
+	return nil
+}
+
+// Sort returns the current sort column (-1 when unsorted) and order.
+func (t *Table) Sort() (int, SortOrder) {
+	if t.sortOrder == SortNone {
+		return -1, SortNone
+	}
+	return t.sortColumn, t.sortOrder
+}
+
+func (t *Table) cycleSortOrder() tea.Cmd {
+	column := t.sortColumn
+	if column < 0 {
+		column = 0
+	}
+	return t.SetSort(column, t.sortOrder.next())
+}
+
+// nextSortColumn sorts by the next visible column in display order.
+func (t *Table) nextSortColumn() tea.Cmd {
+	displayed := t.VisibleColumns()
+	if len(displayed) == 0 {
+		return nil
+	}
+	order := t.sortOrder
+	if order == SortNone {
+		order = SortAscending
+	}
+	next := (slices.Index(displayed, t.sortColumn) + 1) % len(displayed)
+	return t.SetSort(displayed[next], order)
+}
+
+func (t *Table) sortIndicator(column int) string {
+	if column != t.sortColumn {
+		return ""
+	}
+	return t.sortOrder.indicator()
+}
+
+// sortedIndices returns the indices of rows in display order. Rows are never
+// reordered in place so that turning sorting off restores the original order.
+func (t *Table) sortedIndices(rows []TableRow) []int {
+	indices := make([]int, len(rows))
+	for i := range indices {
+		indices[i] = i
+	}
+	if t.sortOrder == SortNone || t.sortColumn < 0 || t.sortColumn >= len(t.columns) {
+		return indices
+	}
+
+	compare := t.columns[t.sortColumn].Compare
+	if compare == nil {
+		compare = columnComparator(rows, t.sortColumn)
+	}
+	slices.SortStableFunc(indices, func(a, b int) int {
+		c := compare(cellValue(rows[a].Data, t.sortColumn), cellValue(rows[b].Data, t.sortColumn))
+		if t.sortOrder == SortDescending {
+			return -c
+		}
+		return c
+	})
+	return indices
+}
+
+func cellValue(data []string, column int) string {
+	if column < len(data) {
+		return data[column]
+	}
+	return ""
+}
+
+// reselect moves the cursor to the row that was selected before the visible rows
+// were rebuilt.
+func (t *Table) reselect(prev VisibleRow) {
+	if t.source != nil {
+		return
+	}
+	for i, row := range t.visibleRows {
+		if slices.Equal(row.path, prev.path) {
+			t.selectedIndex = i
+			break
+		}
+	}
+	t.ensureSelectedVisible()
+}
@@ -0,0 +1,265 @@
+package views
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/flowexec/tuikit/types"
+)
+
+const (
+	// DefaultTablePageSize is the number of rows fetched per page from a TableDataSource.
+	DefaultTablePageSize = 100
+	// maxCachedPages bounds the page cache; the pages farthest from the cursor are evicted.
+	maxCachedPages = 10
+	// filterDebounce is how long filter input must be idle before a source is queried.
+	filterDebounce = 250 * time.Millisecond
+)
+
+// TableQuery describes the rows requested from a TableDataSource. Filter and sort
+// settings are only set when the source supports them (see TableSourceCapabilities).
+// Filter is the raw filter query; sources can apply it with ParseFilterQuery.
+type TableQuery struct {
+	Filter     string
+	SortColumn int
+	SortOrder  SortOrder
+}
+
+// TableDataSource supplies rows to a virtualized Table. Only the pages around the
+// visible window are fetched. Methods are called outside of the update loop.
+// Rows from a data source are rendered flat; children are not shown.
+type TableDataSource interface {
+	// Count returns the total number of rows matching the query.
+	Count(query TableQuery) (int, error)
+	// Fetch returns up to limit rows matching the query, starting at offset.
+	Fetch(query TableQuery, offset, limit int) ([]TableRow, error)
+}
+
+// TableSourceCapabilities is optionally implemented by a TableDataSource that filters
+// or sorts rows itself. Filtering and sorting are disabled for other sources.
+type TableSourceCapabilities interface {
+	SupportsFilter() bool
+	SupportsSort() bool
+}
+
+type sourceCountMsg struct {
+	table      *Table
+	generation int
+	count      int
+	err        error
+}
+
+type sourcePageMsg struct {
+	table      *Table
+	generation int
+	page       int
+	rows       []TableRow
+	err        error
+}
+
+type filterDebounceMsg struct {
+	table *Table
+	seq   int
+}
+
+// tableSource holds the state of a Table backed by a TableDataSource.
+type tableSource struct {
+	source   TableDataSource
+	pageSize int
+	// count is -1 until the first count for the current query is returned.
+	count   int
+	pages   map[int][]TableRow
+	pending map[int]bool
+	// generation is incremented whenever the query changes so that stale results
+	// are discarded.
+	generation int
+	filterSeq  int
+	// marked holds copies of the marked rows by index, so that they outlive the
+	// eviction of their page.
+	marked map[int]TableRow
+}
+
+// NewTableFromSource creates a Table that renders rows from a paged data source. The
+// table's Init command loads the row count and first page. A pageSize <= 0 uses
+// DefaultTablePageSize.
+func NewTableFromSource(
+	render *types.RenderState,
+	columns []TableColumn,
+	source TableDataSource,
+	pageSize int,
+	mode TableDisplayMode,
+) *Table {
+	if pageSize <= 0 {
+		pageSize = DefaultTablePageSize
+	}
+	t := NewTable(render, columns, nil, mode)
+	t.source = &tableSource{
+		source:   source,
+		pageSize: pageSize,
+		count:    -1,
+		pages:    make(map[int][]TableRow),
+		pending:  make(map[int]bool),
+		marked:   make(map[int]TableRow),
+	}
+	return t
+}
+
+func (s *tableSource) supportsFilter() bool {
+	c, ok := s.source.(TableSourceCapabilities)
+	return ok && c.SupportsFilter()
+}
+
+func (s *tableSource) supportsSort() bool {
+	c, ok := s.source.(TableSourceCapabilities)
+	return ok && c.SupportsSort()
+}
+
+func (t *Table) sourceQuery() TableQuery {
+	q := TableQuery{SortColumn: -1}
+	if t.source.supportsFilter() {
+		q.Filter = strings.TrimSpace(t.filterQuery)
+	}
+	if t.source.supportsSort() && t.sortOrder != SortNone {
+		q.SortColumn, q.SortOrder = t.sortColumn, t.sortOrder
+	}
+	return q
+}
+
+// reloadSource drops the cached pages and fetches the count and the pages around the cursor.
+func (t *Table) reloadSource() tea.Cmd {
+	s := t.source
+	s.generation++
+	s.count = -1
+	s.pages = make(map[int][]TableRow)
+	s.pending = make(map[int]bool)
+	t.invalidateContentWidths()
+	t.ClearMarks()
+
+	query, generation, source := t.sourceQuery(), s.generation, s.source
+	count := func() tea.Msg {
+		n, err := source.Count(query)
+		return sourceCountMsg{table: t, generation: generation, count: n, err: err}
+	}
+	return tea.Batch(count, t.fetchPage(t.selectedIndex/s.pageSize))
+}
+
+func (t *Table) fetchPage(page int) tea.Cmd {
+	s := t.source
+	if page < 0 || s.pending[page] || s.pages[page] != nil {
+		return nil
+	}
+	if s.count >= 0 && page*s.pageSize >= s.count {
+		return nil
+	}
+	s.pending[page] = true
+	query, generation, source, size := t.sourceQuery(), s.generation, s.source, s.pageSize
+	return func() tea.Msg {
+		rows, err := source.Fetch(query, page*size, size)
+		return sourcePageMsg{table: t, generation: generation, page: page, rows: rows, err: err}
+	}
+}
+
+// prefetchPages requests the pages covering the visible window and the pages just
+// before and after it.
+func (t *Table) prefetchPages() tea.Cmd {
+	s := t.source
+	first := max(t.scrollOffset-s.pageSize/2, 0) / s.pageSize
+	last := (t.scrollOffset + t.availableLines() + s.pageSize/2) / s.pageSize
+	var cmds []tea.Cmd
+	for page := first; page <= last; page++ {
+		cmds = append(cmds, t.fetchPage(page))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (t *Table) handleSourceMsg(msg tea.Msg) tea.Cmd {
+	s := t.source
+	switch msg := msg.(type) {
+	case sourceCountMsg:
+		if msg.generation != s.generation {
+			return nil
+		}
+		if msg.err != nil {
+			return func() tea.Msg { return msg.err }
+		}
+		s.count = msg.count
+		t.clampSelection()
+		return t.prefetchPages()
+	case sourcePageMsg:
+		if msg.generation != s.generation {
+			return nil
+		}
+		delete(s.pending, msg.page)
+		if msg.err != nil {
+			return func() tea.Msg { return msg.err }
+		}
+		s.pages[msg.page] = msg.rows
+		t.evictPages()
+		t.invalidateContentWidths()
+	case filterDebounceMsg:
+		if msg.seq == s.filterSeq {
+			t.selectedIndex, t.scrollOffset = 0, 0
+			return t.reloadSource()
+		}
+	}
+	return nil
+}
+
+func (t *Table) evictPages() {
+	s := t.source
+	if len(s.pages) <= maxCachedPages {
+		return
+	}
+	current := t.selectedIndex / s.pageSize
+	loaded := make([]int, 0, len(s.pages))
+	for page := range s.pages {
+		loaded = append(loaded, page)
+	}
+	distance := func(page int) int { return max(page-current, current-page) }
+	slices.SortFunc(loaded, func(a, b int) int { return distance(b) - distance(a) })
+	for _, page := range loaded[:len(loaded)-maxCachedPages] {
+		delete(s.pages, page)
+	}
+}
+
+// debounceFilter schedules a source query once filter input has been idle.
+func (t *Table) debounceFilter() tea.Cmd {
+	t.source.filterSeq++
+	seq := t.source.filterSeq
+	return tea.Tick(filterDebounce, func(time.Time) tea.Msg {
+		return filterDebounceMsg{table: t, seq: seq}
+	})
+}
+
+// sourceRow returns the row at index i, or a placeholder while its page is loading.
+func (t *Table) sourceRow(i int) VisibleRow {
+	s := t.source
+	page := s.pages[i/s.pageSize]
+	if offset := i % s.pageSize; offset < len(page) {
+		return VisibleRow{data: page[offset].Data, path: []int{i}}
+	}
+	return VisibleRow{path: []int{i}, placeholder: true}
+}
+
+func (t *Table) sourceNode(i int) *TableRow {
+	s := t.source
+	page := s.pages[i/s.pageSize]
+	if offset := i % s.pageSize; offset < len(page) {
+		return &page[offset]
+	}
+	return nil
+}
+
+// sourceMarkedRows returns the marked rows in index order.
+func (t *Table) sourceMarkedRows() []TableRow {
+	indices := slices.Sorted(maps.Keys(t.source.marked))
+	rows := make([]TableRow, 0, len(indices))
+	for _, i := range indices {
+		rows = append(rows, t.source.marked[i])
+	}
+	return rows
+}
@@ -0,0 +1,69 @@
+package views
+
+import (
+	"charm.land/lipgloss/v2"
+
+	"github.com/flowexec/tuikit/themes"
+)
+
+// TableStyle is theme-driven styling for a row or cell. The zero value keeps the
+// default style.
+type TableStyle struct {
+	// Level colors the text with the theme's color for the output level, e.g. red
+	// for OutputLevelError.
+	Level themes.OutputLevel
+	// Dim renders the text in the theme's gray color, e.g. for disabled rows.
+	Dim  bool
+	Bold bool
+}
+
+// RowStyler returns the style of a row.
+type RowStyler func(row TableRow) TableStyle
+
+// CellStyler returns the style of a single cell. It takes precedence over the row's style.
+type CellStyler func(row TableRow, column int) TableStyle
+
+func (s TableStyle) apply(style lipgloss.Style, cp *themes.ColorPalette) lipgloss.Style {
+	switch {
+	case s.Dim:
+		style = style.Foreground(cp.GrayColor())
+	case s.Level != "":
+		style = style.Foreground(cp.LevelColor(s.Level))
+	}
+	if s.Bold {
+		style = style.Bold(true)
+	}
+	return style
+}
+
+func (t *Table) rowStyle(row VisibleRow, selected bool) lipgloss.Style {
+	cp := t.render.Theme.ColorPalette()
+	switch {
+	case selected:
+		return lipgloss.NewStyle().
+			Background(cp.PrimaryColor()).
+			Foreground(cp.GrayColor()).Bold(true)
+	case t.marked[row.key()]:
+		return lipgloss.NewStyle().Foreground(cp.SecondaryColor()).Bold(true)
+	}
+	style := lipgloss.NewStyle().Foreground(cp.BodyColor())
+	if row.Depth() > 0 {
+		style = style.Foreground(cp.TertiaryColor())
+	}
+	if t.RowStyler != nil && !row.placeholder {
+		style = t.RowStyler(t.tableRow(row)).apply(style, cp)
+	}
+	return style
+}
+
+// cellStyle applies the cell styler and live update highlights. The selected and
+// marked row styles always win.
+func (t *Table) cellStyle(style lipgloss.Style, row VisibleRow, column int, selected bool) lipgloss.Style {
+	if selected || t.marked[row.key()] {
+		return style
+	}
+	if t.CellStyler != nil {
+		style = t.CellStyler(t.tableRow(row), column).apply(style, t.render.Theme.ColorPalette())
+	}
+	return t.highlightChange(style, row, column)
+}
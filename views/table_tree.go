@@ -0,0 +1,281 @@
+package views
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/spinner"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ChildLoader loads the children of a row marked as Lazy the first time it is expanded.
+// It is called outside of the update loop.
+type ChildLoader func(row TableRow) ([]TableRow, error)
+
+// childrenLoadedMsg carries the result of a ChildLoader back to the table. The load
+// identifies the row in the table's loading map, which follows the row when live
+// updates move it.
+type childrenLoadedMsg struct {
+	table    *Table
+	load     int
+	children []TableRow
+	err      error
+}
+
+// rowKey identifies a row by its path of indices in the row tree, independently of
+// its visible position.
+type rowKey string
+
+func pathKey(path []int) rowKey {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = strconv.Itoa(p)
+	}
+	return rowKey(strings.Join(parts, "/"))
+}
+
+func parseRowKey(k rowKey) []int {
+	if k == "" {
+		return nil
+	}
+	parts := strings.Split(string(k), "/")
+	path := make([]int, len(parts))
+	for i, p := range parts {
+		path[i], _ = strconv.Atoi(p)
+	}
+	return path
+}
+
+func (vr *VisibleRow) key() rowKey {
+	return pathKey(vr.path)
+}
+
+// Depth returns the nesting level of the row; top-level rows have depth 0.
+func (vr *VisibleRow) Depth() int {
+	return len(vr.path) - 1
+}
+
+// node returns the row at the path, or nil if the path no longer exists.
+func (t *Table) node(path []int) *TableRow {
+	if t.source != nil {
+		if len(path) != 1 {
+			return nil
+		}
+		return t.sourceNode(path[0])
+	}
+	rows := t.rows
+	var row *TableRow
+	for _, i := range path {
+		if i < 0 || i >= len(rows) {
+			return nil
+		}
+		row = &rows[i]
+		rows = row.Children
+	}
+	return row
+}
+
+func (t *Table) buildVisibleRows() {
+	if t.source == nil {
+		t.visibleRows = make([]VisibleRow, 0)
+		t.appendRows(t.rows, nil, "", strings.TrimSpace(t.filterQuery))
+	}
+	t.clampSelection()
+}
+
+func (t *Table) clampSelection() {
+	if t.selectedIndex >= t.rowCount() {
+		t.selectedIndex = t.rowCount() - 1
+	}
+	if t.selectedIndex < 0 {
+		t.selectedIndex = 0
+	}
+}
+
+// appendRows appends the visible rows of one tree level. When filtering, rows are shown
+// if they or any of their descendants match, and the branches leading to matching
+// descendants are expanded.
+func (t *Table) appendRows(rows []TableRow, parent []int, guide, query string) {
+	order := t.sortedIndices(rows)
+	if query != "" {
+		order = slices.DeleteFunc(order, func(i int) bool {
+			return !t.matchesFilter(query, rows[i].Data) && !t.descendantMatches(query, rows[i].Children)
+		})
+	}
+
+	for n, i := range order {
+		row := rows[i]
+		path := append(slices.Clone(parent), i)
+		last := n == len(order)-1
+		vr := VisibleRow{data: row.Data, path: path}
+		if len(parent) > 0 {
+			vr.guide = guide + treeBranch(last)
+		}
+		t.visibleRows = append(t.visibleRows, vr)
+
+		childGuide := "  "
+		if len(parent) > 0 {
+			childGuide = guide + treeContinuation(last)
+		}
+		switch {
+		case query != "" && t.descendantMatches(query, row.Children):
+			t.appendRows(row.Children, path, childGuide, query)
+		case query == "" && row.Expanded:
+			t.appendRows(row.Children, path, childGuide, query)
+		}
+	}
+}
+
+func (t *Table) descendantMatches(query string, rows []TableRow) bool {
+	for _, row := range rows {
+		if t.matchesFilter(query, row.Data) || t.descendantMatches(query, row.Children) {
+			return true
+		}
+	}
+	return false
+}
+
+func treeBranch(last bool) string {
+	if last {
+		return "└─ "
+	}
+	return "├─ "
+}
+
+func treeContinuation(last bool) string {
+	if last {
+		return "   "
+	}
+	return "│  "
+}
+
+func hasChildren(row *TableRow) bool {
+	return len(row.Children) > 0 || row.Lazy
+}
+
+// cellPrefix renders the mark, tree guides and expansion marker shown before the
+// first rendered cell.
+func (t *Table) cellPrefix(row VisibleRow, selected bool) string {
+	prefix := t.markPrefix(row) + row.guide
+	node := t.node(row.path)
+	switch {
+	case node == nil || t.source != nil:
+		return prefix + "◌ "
+	case t.loading[row.key()] != 0:
+		frame := t.spinner.View()
+		if selected {
+			frame = ansi.Strip(frame)
+		}
+		return prefix + frame + " "
+	case hasChildren(node) && node.Expanded:
+		return prefix + "◉ "
+	case hasChildren(node):
+		return prefix + "● "
+	case row.Depth() == 0:
+		return prefix + "◌ "
+	default:
+		return prefix
+	}
+}
+
+// toggleExpansion expands or collapses the selected row. Lazy rows start loading
+// their children on first expansion.
+func (t *Table) toggleExpansion() tea.Cmd {
+	row := t.GetSelectedRow()
+	if row == nil || t.source != nil {
+		return nil
+	}
+	node := t.node(row.path)
+	if node == nil || !hasChildren(node) || t.loading[row.key()] != 0 {
+		return nil
+	}
+	if !node.Expanded && node.Lazy && t.LoadChildren != nil {
+		return t.loadChildren(row.path, *node)
+	}
+	node.Expanded = !node.Expanded
+	return nil
+}
+
+// ExpandAll expands every row with loaded children. Lazy rows are not loaded.
+func (t *Table) ExpandAll() {
+	setExpanded(t.rows, true)
+	t.buildVisibleRows()
+	t.ensureSelectedVisible()
+}
+
+// CollapseAll collapses every row.
+func (t *Table) CollapseAll() {
+	prev := t.GetSelectedRow()
+	setExpanded(t.rows, false)
+	t.buildVisibleRows()
+	if prev != nil {
+		// Keep the cursor on the top-level ancestor of the previous selection.
+		t.reselect(VisibleRow{path: prev.path[:1]})
+	}
+}
+
+func setExpanded(rows []TableRow, expanded bool) {
+	for i := range rows {
+		if len(rows[i].Children) > 0 {
+			rows[i].Expanded = expanded
+		}
+		setExpanded(rows[i].Children, expanded)
+	}
+}
+
+func (t *Table) loadChildren(path []int, row TableRow) tea.Cmd {
+	startSpinner := len(t.loading) == 0
+	t.loads++
+	id := t.loads
+	t.loading[pathKey(path)] = id
+	loader := t.LoadChildren
+	load := func() tea.Msg {
+		children, err := loader(row)
+		return childrenLoadedMsg{table: t, load: id, children: children, err: err}
+	}
+	if startSpinner {
+		return tea.Batch(load, t.spinner.Tick)
+	}
+	return load
+}
+
+func (t *Table) handleChildrenLoaded(msg childrenLoadedMsg) tea.Cmd {
+	var path []int
+	for k, id := range t.loading {
+		if id == msg.load {
+			path = parseRowKey(k)
+			delete(t.loading, k)
+			break
+		}
+	}
+	if path == nil {
+		// The row was removed or the rows were replaced while loading.
+		return nil
+	}
+	if msg.err != nil {
+		return func() tea.Msg { return msg.err }
+	}
+	prev := t.GetSelectedRow()
+	if node := t.node(path); node != nil {
+		t.invalidateContentWidths()
+		node.Children = msg.children
+		node.Lazy = false
+		node.Expanded = true
+	}
+	t.buildVisibleRows()
+	if prev != nil {
+		t.reselect(*prev)
+	}
+	return nil
+}
+
+func (t *Table) updateSpinner(msg spinner.TickMsg) tea.Cmd {
+	if len(t.loading) == 0 {
+		return nil
+	}
+	var cmd tea.Cmd
+	t.spinner, cmd = t.spinner.Update(msg)
+	return cmd
+}